@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"sistema-faculdade/internal/data"
+	"time"
+)
+
+// runLeaveReturns retorna automaticamente os alunos trancados quando o semestre de retorno começa e
+// inicia os trancamentos aprovados com antecedência quando o semestre trancado começa.
+// Roda ao iniciar o servidor e depois a cada 6 horas.
+func (app *application) runLeaveReturns(leaves data.LeaveRepository) {
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		n, err := leaves.ProcessReturns(time.Now())
+		if err != nil {
+			log.Println("Erro ao processar retornos de trancamento:", err)
+		} else if n > 0 {
+			log.Printf("%d aluno(s) retornaram de trancamento", n)
+		}
+
+		n, err = leaves.ProcessStarts(time.Now())
+		if err != nil {
+			log.Println("Erro ao iniciar trancamentos:", err)
+		} else if n > 0 {
+			log.Printf("%d aluno(s) passaram a trancado", n)
+		}
+		<-ticker.C
+	}
+}
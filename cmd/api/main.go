@@ -1,17 +1,16 @@
 package main
 
 import (
-	"bufio"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sistema-faculdade/internal/config"
 	"sistema-faculdade/internal/data"
 	"sistema-faculdade/internal/handlers"
+	"sistema-faculdade/internal/signing"
 	"strconv"
-	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -21,39 +20,10 @@ type application struct {
 	handlers *handlers.Handler
 }
 
-func loadEnv() {
-
-	cwd, err := os.Getwd()
-	if err != nil {
-		log.Fatal("Erro ao carregar o diretório atual: ", err)
-	}
-
-	envPath := filepath.Join(cwd, ".env")
-
-	file, err := os.Open(envPath)
-	if err != nil {
-		log.Println("Erro ao abrir o arquivo .env: ", envPath)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			os.Setenv(key, value)
-		}
-
-	}
-}
-
 func main() {
-	loadEnv()
+	if err := config.LoadEnv(); err != nil {
+		log.Println("Erro ao abrir o arquivo .env: ", err)
+	}
 
 	connStr := os.Getenv("DB_DSN")
 	if connStr == "" {
@@ -74,20 +44,64 @@ func main() {
 		log.Fatal("Erro ao conectar ao banco de dados: ", err)
 	}
 
-	studentRepo := data.StudentRepository{DB: db}
+	// Formato das matrículas geradas; ver academic.RegistrationFormat
+	numbering, err := config.RegistrationNumbering()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	studentRepo := data.StudentRepository{DB: db, Numbering: numbering}
 	teacherRepo := data.TeacherRepository{DB: db}
 	courseRepo := data.CourseRepository{DB: db}
 	deptRepo := data.DepartmentRepository{DB: db}
 	disciplineRepo := data.DisciplineRepository{DB: db}
 	semesterRepo := data.SemesterRepository{DB: db}
 	dashboardRepo := data.DashboardRepository{DB: db}
+	offerRepo := data.OfferRepository{DB: db}
+	calendarRepo := data.CalendarRepository{DB: db}
+	registrationRepo := data.RegistrationRepository{DB: db}
+	sessionRepo := data.SessionRepository{DB: db}
+	leaveRepo := data.LeaveRepository{DB: db}
+	transferRepo := data.TransferRepository{DB: db}
+	transcriptRepo := data.TranscriptRepository{DB: db}
+	equivalenceRepo := data.EquivalenceRepository{DB: db}
+	externalCreditRepo := data.ExternalCreditRepository{DB: db}
+	reenrollmentRepo := data.ReenrollmentRepository{DB: db}
+	enrollmentRequestRepo := data.EnrollmentRequestRepository{DB: db}
+	reportRepo := data.ReportRepository{DB: db}
+	admissionRepo := data.AdmissionRepository{DB: db, Numbering: numbering}
+	importRepo := data.ImportRepository{DB: db, Numbering: numbering}
+	documentRepo := data.DocumentRepository{DB: db}
+	diplomaRepo := data.DiplomaRepository{DB: db}
+
+	myHandlers := handlers.NewHandler(studentRepo, teacherRepo, courseRepo, deptRepo, disciplineRepo, semesterRepo, dashboardRepo, offerRepo, calendarRepo, registrationRepo, sessionRepo, leaveRepo, transferRepo, transcriptRepo, equivalenceRepo, externalCreditRepo, reenrollmentRepo, enrollmentRequestRepo, reportRepo, admissionRepo, importRepo, documentRepo, diplomaRepo)
+
+	// Endereço público usado nos links de verificação dos documentos emitidos; vazio usa o host da requisição
+	myHandlers.PublicURL = os.Getenv("PUBLIC_BASE_URL")
+	myHandlers.Institution = os.Getenv("INSTITUTION_NAME")
+	if myHandlers.Institution == "" {
+		myHandlers.Institution = "UniSystem"
+	}
 
-	myHandlers := handlers.NewHandler(studentRepo, teacherRepo, courseRepo, deptRepo, disciplineRepo, semesterRepo, dashboardRepo)
+	// Chaves de assinatura dos documentos; ver signing.Load. Sem chave privada, a emissão de
+	// documentos assinados fica indisponível, mas a verificação continua com as chaves públicas.
+	keysDir := os.Getenv("SIGNING_KEYS_DIR")
+	if keysDir == "" {
+		keysDir = "keys"
+	}
+	myHandlers.Signer, err = signing.Load(keysDir, os.Getenv("SIGNING_KEY_ID"))
+	if err != nil {
+		log.Println("Assinatura digital desativada: ", err)
+	} else if !myHandlers.Signer.CanSign() {
+		log.Println("Assinatura digital desativada: nenhuma chave privada em ", keysDir)
+	}
 
 	app := &application{
 		handlers: myHandlers,
 	}
 
+	go app.runLeaveReturns(leaveRepo)
+
 	portStr := os.Getenv("PORT")
 	var port int
 	if portStr != "" {
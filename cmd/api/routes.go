@@ -7,16 +7,67 @@ func (app *application) routes() *http.ServeMux {
 
 	mux.HandleFunc("POST /api/departments", app.handlers.CreateDepartmentHandler)
 	mux.HandleFunc("GET /api/departments", app.handlers.GetAllDepartmentsHandler)
+	mux.HandleFunc("GET /api/departments/{id}/heads", app.handlers.GetDepartmentHeadsHandler)
+	mux.HandleFunc("POST /api/departments/{id}/heads", app.handlers.AppointDepartmentHeadHandler)
 
 	mux.HandleFunc("POST /api/courses", app.handlers.CreateCourseHandler)
 	mux.HandleFunc("GET /api/courses", app.handlers.GetAllCoursesHandler)
+	mux.HandleFunc("GET /api/courses/{id}/coordinators", app.handlers.GetCourseCoordinatorsHandler)
+	mux.HandleFunc("POST /api/courses/{id}/coordinators", app.handlers.AppointCourseCoordinatorHandler)
+	mux.HandleFunc("GET /api/courses/{id}/curriculum", app.handlers.GetCurriculumHandler)
+	mux.HandleFunc("POST /api/courses/{id}/curriculum", app.handlers.SaveCurriculumItemHandler)
+	mux.HandleFunc("DELETE /api/courses/{id}/curriculum/{discipline_id}", app.handlers.RemoveCurriculumItemHandler)
 
 	mux.HandleFunc("POST /api/students", app.handlers.CreateStudentHandler)
 	mux.HandleFunc("GET /api/students", app.handlers.GetAllStudentsHandler)
 	mux.HandleFunc("GET /api/students/{id}", app.handlers.GetStudentByIDHandler)
+	mux.HandleFunc("GET /api/students/by-registration", app.handlers.GetStudentByRegistrationHandler)
 	mux.HandleFunc("PUT /api/students/{id}", app.handlers.UpdateStudentHandler)
 	mux.HandleFunc("DELETE /api/students/{id}", app.handlers.DeleteStudentHandler)
 	mux.HandleFunc("PATCH /api/students/{id}/activate", app.handlers.ActivateStudentHandler)
+	mux.HandleFunc("GET /api/students/{id}/schedule.ics", app.handlers.StudentScheduleICSHandler)
+	mux.HandleFunc("POST /api/students/{id}/status", app.handlers.ChangeStudentStatusHandler)
+	mux.HandleFunc("GET /api/students/{id}/status-history", app.handlers.GetStudentStatusHistoryHandler)
+	mux.HandleFunc("POST /api/students/{id}/leave-requests", app.handlers.CreateLeaveRequestHandler)
+	mux.HandleFunc("GET /api/students/{id}/leave-requests", app.handlers.GetStudentLeaveRequestsHandler)
+	mux.HandleFunc("GET /api/students/{id}/academic-clock", app.handlers.GetAcademicClockHandler)
+	mux.HandleFunc("POST /api/students/{id}/transfer", app.handlers.TransferStudentHandler)
+	mux.HandleFunc("GET /api/students/{id}/transfers", app.handlers.GetStudentTransfersHandler)
+	mux.HandleFunc("GET /api/students/{id}/transcript", app.handlers.GetTranscriptHandler)
+	mux.HandleFunc("POST /api/students/{id}/external-credits", app.handlers.CreateExternalCreditHandler)
+	mux.HandleFunc("GET /api/students/{id}/external-credits", app.handlers.GetStudentExternalCreditsHandler)
+	mux.HandleFunc("POST /api/students/{id}/enrollment-requests", app.handlers.CreateEnrollmentRequestsHandler)
+	mux.HandleFunc("GET /api/students/{id}/enrollment-requests", app.handlers.GetStudentEnrollmentRequestsHandler)
+	mux.HandleFunc("POST /api/students/{id}/enrollment-requests/review", app.handlers.ReviewEnrollmentRequestsHandler)
+	mux.HandleFunc("GET /api/students/{id}/recommendations", app.handlers.GetRecommendationsHandler)
+	mux.HandleFunc("POST /api/students/{id}/documents/{kind}", app.handlers.IssueDocumentHandler)
+	mux.HandleFunc("GET /api/students/{id}/documents", app.handlers.GetStudentDocumentsHandler)
+	mux.HandleFunc("GET /api/students/{id}/graduation-audit", app.handlers.GetGraduationAuditHandler)
+	mux.HandleFunc("POST /api/students/{id}/diploma", app.handlers.IssueDiplomaHandler)
+
+	mux.HandleFunc("GET /api/documents/{code}/pdf", app.handlers.GetDocumentPDFHandler)
+	mux.HandleFunc("GET /api/documents/{code}/signature", app.handlers.GetDocumentSignatureHandler)
+	mux.HandleFunc("GET /api/diplomas", app.handlers.GetDiplomasHandler)
+	mux.HandleFunc("GET /api/diplomas/{code}/xml", app.handlers.GetDiplomaXMLHandler)
+	mux.HandleFunc("GET /api/diplomas/schema.xsd", app.handlers.GetDiplomaSchemaHandler)
+	mux.HandleFunc("GET /verify/{code}", app.handlers.VerifyDocumentHandler)
+	mux.HandleFunc("GET /verify/diplomas/{code}", app.handlers.VerifyDiplomaHandler)
+	mux.HandleFunc("GET /verify/keys", app.handlers.GetSigningKeysHandler)
+	mux.HandleFunc("POST /verify/signature", app.handlers.VerifySignatureHandler)
+
+	mux.HandleFunc("GET /api/leave-requests", app.handlers.GetLeaveRequestsHandler)
+	mux.HandleFunc("POST /api/leave-requests/{id}/approve", app.handlers.ReviewLeaveRequestHandler(true))
+	mux.HandleFunc("POST /api/leave-requests/{id}/reject", app.handlers.ReviewLeaveRequestHandler(false))
+
+	mux.HandleFunc("GET /api/enrollment-requests", app.handlers.GetEnrollmentRequestsHandler)
+
+	mux.HandleFunc("GET /api/external-credits", app.handlers.GetExternalCreditsHandler)
+	mux.HandleFunc("POST /api/external-credits/{id}/approve", app.handlers.ReviewExternalCreditHandler(true))
+	mux.HandleFunc("POST /api/external-credits/{id}/reject", app.handlers.ReviewExternalCreditHandler(false))
+
+	mux.HandleFunc("GET /api/equivalences", app.handlers.GetEquivalencesHandler)
+	mux.HandleFunc("POST /api/equivalences", app.handlers.CreateEquivalenceHandler)
+	mux.HandleFunc("DELETE /api/equivalences/{id}", app.handlers.DeleteEquivalenceHandler)
 
 	mux.HandleFunc("POST /api/teachers", app.handlers.CreateTeacherHandler)
 	mux.HandleFunc("GET /api/teachers", app.handlers.GetAllTeachersHandler)
@@ -24,18 +75,82 @@ func (app *application) routes() *http.ServeMux {
 	mux.HandleFunc("PUT /api/teachers/{id}", app.handlers.UpdateTeacherHandler)
 	mux.HandleFunc("DELETE /api/teachers/{id}", app.handlers.DeleteTeacherHandler)
 	mux.HandleFunc("PATCH /api/teachers/{id}/activate", app.handlers.ActivateTeacherHandler)
+	mux.HandleFunc("GET /api/teachers/{id}/schedule.ics", app.handlers.TeacherScheduleICSHandler)
+	mux.HandleFunc("GET /api/teachers/{id}/contracts", app.handlers.GetTeacherContractsHandler)
+	mux.HandleFunc("GET /api/teachers/{id}/disciplines", app.handlers.GetTeacherDisciplinesHandler)
+	mux.HandleFunc("POST /api/teachers/{id}/disciplines", app.handlers.AddTeacherDisciplineHandler)
+	mux.HandleFunc("DELETE /api/teachers/{id}/disciplines/{discipline_id}", app.handlers.RemoveTeacherDisciplineHandler)
 
 	mux.HandleFunc("POST /api/disciplines", app.handlers.CreateDisciplinesHandler)
 	mux.HandleFunc("GET /api/disciplines", app.handlers.GetAllDisciplinesHandler)
 	mux.HandleFunc("GET /api/disciplines/{id}", app.handlers.GetDisciplineByIDHandler)
 	mux.HandleFunc("PUT /api/disciplines/{id}", app.handlers.UpdateDisciplineHandler)
 	mux.HandleFunc("DELETE /api/disciplines/{id}", app.handlers.DeleteDisciplineHandler)
+	mux.HandleFunc("GET /api/disciplines/{id}/prerequisites", app.handlers.GetPrerequisitesHandler)
+	mux.HandleFunc("POST /api/disciplines/{id}/prerequisites", app.handlers.AddPrerequisiteHandler)
+	mux.HandleFunc("DELETE /api/disciplines/{id}/prerequisites/{prerequisite_id}", app.handlers.RemovePrerequisiteHandler)
+	mux.HandleFunc("GET /api/disciplines/{id}/candidate-teachers", app.handlers.GetCandidateTeachersHandler)
 
 	mux.HandleFunc("POST /api/semesters", app.handlers.CreateSemesterHandler)
 	mux.HandleFunc("GET /api/semesters", app.handlers.GetAllSemestersHandler)
 	mux.HandleFunc("DELETE /api/semesters/{id}", app.handlers.DeleteSemesterHandler)
+	mux.HandleFunc("POST /api/semesters/{id}/rollover", app.handlers.RolloverSemesterHandler)
+
+	mux.HandleFunc("GET /api/semesters/{id}/calendar", app.handlers.GetSemesterCalendarHandler)
+	mux.HandleFunc("POST /api/semesters/{id}/calendar", app.handlers.CreateCalendarEventHandler)
+	mux.HandleFunc("POST /api/semesters/{id}/calendar/import", app.handlers.ImportCalendarHandler)
+	mux.HandleFunc("PUT /api/calendar/{id}", app.handlers.UpdateCalendarEventHandler)
+	mux.HandleFunc("DELETE /api/calendar/{id}", app.handlers.DeleteCalendarEventHandler)
+
+	mux.HandleFunc("POST /api/semesters/{id}/reenrollment", app.handlers.OpenReenrollmentHandler)
+	mux.HandleFunc("GET /api/semesters/{id}/reenrollment", app.handlers.GetReenrollmentHandler)
+	mux.HandleFunc("POST /api/reenrollments/{id}/confirm", app.handlers.ConfirmReenrollmentHandler)
+	mux.HandleFunc("GET /api/reenrollments/{id}/pending", app.handlers.GetPendingReenrollmentsHandler)
+	mux.HandleFunc("POST /api/reenrollments/{id}/close", app.handlers.CloseReenrollmentHandler)
+
+	mux.HandleFunc("POST /api/offers", app.handlers.CreateOfferHandler)
+	mux.HandleFunc("GET /api/offers", app.handlers.GetAllOffersHandler)
+	mux.HandleFunc("GET /api/offers/{id}", app.handlers.GetOfferByIDHandler)
+	mux.HandleFunc("PUT /api/offers/{id}", app.handlers.UpdateOfferHandler)
+	mux.HandleFunc("DELETE /api/offers/{id}", app.handlers.DeleteOfferHandler)
+	mux.HandleFunc("GET /api/offers/{id}/registrations", app.handlers.GetOfferRegistrationsHandler)
+	mux.HandleFunc("POST /api/offers/{id}/close", app.handlers.CloseOfferHandler)
+	mux.HandleFunc("GET /api/offers/{id}/sessions", app.handlers.GetOfferSessionsHandler)
+	mux.HandleFunc("POST /api/offers/{id}/sessions/generate", app.handlers.GenerateSessionsHandler)
+	mux.HandleFunc("GET /api/offers/{id}/diary", app.handlers.ExportDiaryHandler)
+	mux.HandleFunc("GET /api/offers/{id}/teachers", app.handlers.GetOfferTeachersHandler)
+	mux.HandleFunc("PUT /api/offers/{id}/teachers", app.handlers.SetOfferTeachersHandler)
+	mux.HandleFunc("GET /api/offers/{id}/substitutions", app.handlers.GetOfferSubstitutionsHandler)
+	mux.HandleFunc("POST /api/offers/{id}/substitutions", app.handlers.CreateOfferSubstitutionHandler)
+	mux.HandleFunc("DELETE /api/substitutions/{id}", app.handlers.DeleteSubstitutionHandler)
+
+	mux.HandleFunc("PUT /api/sessions/{id}", app.handlers.UpdateSessionHandler)
+	mux.HandleFunc("POST /api/sessions/{id}/attendance", app.handlers.SaveSessionAttendanceHandler)
+
+	mux.HandleFunc("POST /api/registrations", app.handlers.CreateRegistrationHandler)
+	mux.HandleFunc("DELETE /api/registrations/{id}", app.handlers.DeleteRegistrationHandler)
+	mux.HandleFunc("GET /api/registrations/{id}/attendance", app.handlers.GetAttendanceHandler)
+	mux.HandleFunc("POST /api/registrations/{id}/attendance", app.handlers.CreateAttendanceHandler)
+
+	mux.HandleFunc("GET /api/rooms/{room}/schedule.ics", app.handlers.RoomScheduleICSHandler)
+
+	mux.HandleFunc("POST /api/admissions", app.handlers.CreateAdmissionProcessHandler)
+	mux.HandleFunc("GET /api/admissions", app.handlers.GetAdmissionProcessesHandler)
+	mux.HandleFunc("GET /api/admissions/{id}", app.handlers.GetAdmissionProcessHandler)
+	mux.HandleFunc("POST /api/admissions/{id}/applicants", app.handlers.CreateApplicantHandler)
+	mux.HandleFunc("GET /api/admissions/{id}/applicants", app.handlers.GetApplicantsHandler)
+	mux.HandleFunc("POST /api/admissions/{id}/scores/import", app.handlers.ImportScoresHandler)
+	mux.HandleFunc("POST /api/admissions/{id}/rank", app.handlers.RankApplicantsHandler)
+	mux.HandleFunc("POST /api/admissions/{id}/calls", app.handlers.CreateAdmissionCallHandler)
+	mux.HandleFunc("POST /api/applicants/{id}/confirm", app.handlers.ConfirmApplicantHandler)
+	mux.HandleFunc("POST /api/applicants/{id}/decline", app.handlers.DeclineApplicantHandler)
+
+	mux.HandleFunc("POST /api/import/{entity}", app.handlers.ImportHandler)
 
 	mux.HandleFunc("GET /api/dashboard/stats", app.handlers.GetDashboardStatsHandler)
+	mux.HandleFunc("GET /api/reports/demand", app.handlers.DemandForecastHandler)
+	mux.HandleFunc("GET /api/reports/teacher-workload", app.handlers.TeacherWorkloadHandler)
+	mux.HandleFunc("GET /api/reports/faculty-profile", app.handlers.FacultyProfileHandler)
 	// Servidor de arquivos para o frontend
 	// Servir CSS
 	mux.Handle("/css/", http.StripPrefix("/css/", http.FileServer(http.Dir("ui/static/css"))))
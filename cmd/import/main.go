@@ -0,0 +1,86 @@
+// Comando de importação em lote, para cargas grandes fora da API:
+//
+//	go run ./cmd/import -entity students -file alunos.csv -dry-run
+//
+// Usa o mesmo DB_DSN (e REGISTRATION_NUMBER_FORMAT) do servidor, lidos do ambiente ou do .env.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sistema-faculdade/internal/config"
+	"sistema-faculdade/internal/data"
+	"sistema-faculdade/internal/importer"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	entity := flag.String("entity", "", "entidade importada: students, teachers ou disciplines")
+	path := flag.String("file", "-", "arquivo CSV (- para a entrada padrão)")
+	dryRun := flag.Bool("dry-run", false, "apenas confere o arquivo, sem gravar")
+	user := flag.String("user", "importacao", "usuário registrado nos históricos")
+	flag.Parse()
+
+	if _, ok := importer.Columns[*entity]; !ok {
+		fmt.Fprintln(os.Stderr, "informe -entity students, teachers ou disciplines")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Sem .env, valem só as variáveis do ambiente
+	config.LoadEnv()
+	connStr := os.Getenv("DB_DSN")
+	if connStr == "" {
+		log.Fatal("A variável de ambiente DB_DSN não está definida")
+	}
+
+	numbering, err := config.RegistrationNumbering()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var body io.Reader = os.Stdin
+	if *path != "-" {
+		file, err := os.Open(*path)
+		if err != nil {
+			log.Fatal("Erro ao abrir o arquivo: ", err)
+		}
+		defer file.Close()
+		body = file
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := data.ImportRepository{DB: db, Numbering: numbering}
+	result, err := importer.Import(repo, *entity, body, *dryRun, *user)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, e := range result.Errors {
+		if e.Field != "" {
+			fmt.Printf("linha %d [%s]: %s\n", e.Line, e.Field, e.Message)
+		} else {
+			fmt.Printf("linha %d: %s\n", e.Line, e.Message)
+		}
+	}
+
+	fmt.Printf("%d linhas lidas, %d inseridas, %d atualizadas, %d recusadas\n",
+		result.Rows, result.Inserted, result.Updated, result.Rejected)
+	if result.DryRun {
+		fmt.Println("Simulação: nada foi gravado")
+	}
+
+	if len(result.Errors) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+// Comando das chaves de assinatura dos documentos. Gera e lista as chaves e confere assinaturas sem
+// acesso ao servidor nem ao banco:
+//
+//	go run ./cmd/signature -generate
+//	go run ./cmd/signature -list
+//	go run ./cmd/signature -verify declaracao.pdf -sig declaracao.pdf.sig
+//	go run ./cmd/signature -verify historico.json
+//	go run ./cmd/signature -verify diploma.xml
+//
+// -keys aponta para o diretório de chaves (padrão SIGNING_KEYS_DIR ou "keys") ou para a lista de
+// chaves públicas baixada de /verify/keys.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sistema-faculdade/internal/diploma"
+	"sistema-faculdade/internal/signing"
+	"strings"
+	"time"
+)
+
+func main() {
+	defaultKeys := os.Getenv("SIGNING_KEYS_DIR")
+	if defaultKeys == "" {
+		defaultKeys = "keys"
+	}
+	keys := flag.String("keys", defaultKeys, "diretório de chaves ou arquivo com as chaves públicas de /verify/keys")
+	generate := flag.Bool("generate", false, "gera um novo par de chaves, que passa a assinar os documentos")
+	id := flag.String("id", "", "ID da chave gerada (padrão: data e hora)")
+	defaultOrg := os.Getenv("INSTITUTION_NAME")
+	if defaultOrg == "" {
+		defaultOrg = "UniSystem"
+	}
+	org := flag.String("org", defaultOrg, "instituição no certificado da chave gerada")
+	list := flag.Bool("list", false, "lista as chaves públicas ativas")
+	verify := flag.String("verify", "", "arquivo a conferir: PDF (com -sig), documento JSON assinado ou diploma XML")
+	sigPath := flag.String("sig", "", "assinatura destacada do PDF (.sig)")
+	flag.Parse()
+
+	switch {
+	case *generate:
+		if *id == "" {
+			*id = time.Now().Format("20060102-150405")
+		}
+		created, err := signing.Generate(*keys, *id, *org)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Chave %s gerada em %s. Ela assina os novos documentos a partir do próximo início do servidor, "+
+			"a menos que SIGNING_KEY_ID indique outra.\n", created, *keys)
+
+	case *list:
+		ring := load(*keys, os.Getenv("SIGNING_KEY_ID"))
+		for _, k := range ring.PublicKeys() {
+			mark := ""
+			if k.Signing {
+				mark = " (assina)"
+			}
+			fmt.Printf("%s %s%s\n", k.KeyID, k.Algorithm, mark)
+		}
+
+	case *verify != "":
+		ring := load(*keys, "")
+		content, err := os.ReadFile(*verify)
+		if err != nil {
+			log.Fatal("Erro ao abrir o arquivo: ", err)
+		}
+
+		if *sigPath == "" && strings.HasPrefix(strings.TrimSpace(string(content)), "<") {
+			info, err := diploma.Verify(ring, content)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("Assinatura válida: diploma %s, registro %s, de %s (chave %s)\n",
+				info.Code, info.RegistryNumber, info.StudentName, info.KeyID)
+			return
+		}
+		if *sigPath == "" {
+			_, doc, err := ring.VerifyJSON(content)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("Assinatura válida: %s emitido em %s\n", doc.Kind, doc.IssuedAt.Local().Format("02/01/2006 15:04"))
+			return
+		}
+
+		b, err := os.ReadFile(*sigPath)
+		if err != nil {
+			log.Fatal("Erro ao abrir a assinatura: ", err)
+		}
+		var sig signing.Signature
+		if err := json.Unmarshal(b, &sig); err != nil {
+			log.Fatal("Arquivo de assinatura inválido: ", err)
+		}
+		if err := ring.Verify(content, sig); err != nil {
+			fail(err)
+		}
+		fmt.Printf("Assinatura válida (chave %s)\n", sig.KeyID)
+
+	default:
+		fmt.Fprintln(os.Stderr, "informe -generate, -list ou -verify")
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func load(path, signingID string) *signing.Keyring {
+	ring, err := signing.Load(path, signingID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ring
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Assinatura NÃO confere: "+err.Error())
+	os.Exit(1)
+}
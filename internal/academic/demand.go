@@ -0,0 +1,138 @@
+package academic
+
+import (
+	"math"
+	"sort"
+)
+
+// DemandStudent é a situação de um aluno usada na previsão de demanda
+type DemandStudent struct {
+	ID       int
+	CourseID int
+	// Level é o semestre do curso que o aluno vai cursar
+	Level      int
+	Fulfilled  map[int]bool
+	InProgress map[int]bool
+}
+
+// CurriculumEntry é uma disciplina da matriz de um curso
+type CurriculumEntry struct {
+	CourseID          int
+	DisciplineID      int
+	SuggestedSemester int
+}
+
+// DisciplineStats são as aprovações e reprovações históricas de uma disciplina
+type DisciplineStats struct {
+	Approved int
+	Failed   int
+}
+
+// FailureRate retorna a taxa de reprovação; sem histórico a taxa é zero
+func (s DisciplineStats) FailureRate() float64 {
+	if s.Approved+s.Failed == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Approved+s.Failed)
+}
+
+// Demand é a demanda estimada de uma disciplina para o próximo semestre
+type Demand struct {
+	DisciplineID int
+	// Eligible são os alunos que já cumpriram os pré-requisitos
+	Eligible int
+	// AwaitingPrerequisites são os alunos cursando algum pré-requisito agora
+	AwaitingPrerequisites int
+	// OnTrack e Overdue separam os elegíveis pela posição da disciplina na matriz
+	OnTrack int
+	Overdue int
+	// ExpectedRetakes é a estimativa de reprovações de quem está cursando a disciplina agora
+	ExpectedRetakes int
+	FailureRate     float64
+	Estimated       int
+	Sections        int
+}
+
+// ForecastDemand estima, por disciplina, quantos alunos vão precisar dela no próximo semestre.
+// Quem aguarda pré-requisito entra ponderado pela taxa de aprovação do pré-requisito mais difícil,
+// e quem está cursando a disciplina agora entra ponderado pela taxa de reprovação dela.
+// Só são considerados alunos que já chegaram ao semestre sugerido da disciplina (ou disciplinas sem semestre).
+func ForecastDemand(students []DemandStudent, curriculum []CurriculumEntry, prereqs map[int][]int, stats map[int]DisciplineStats, sectionSize int) []Demand {
+	byCourse := map[int][]CurriculumEntry{}
+	for _, c := range curriculum {
+		byCourse[c.CourseID] = append(byCourse[c.CourseID], c)
+	}
+
+	demand := map[int]*Demand{}
+	expected := map[int]float64{}
+	retakes := map[int]float64{}
+	get := func(id int) *Demand {
+		if demand[id] == nil {
+			demand[id] = &Demand{DisciplineID: id, FailureRate: stats[id].FailureRate()}
+		}
+		return demand[id]
+	}
+
+	for _, s := range students {
+		for _, c := range byCourse[s.CourseID] {
+			if s.Fulfilled[c.DisciplineID] {
+				continue
+			}
+			d := get(c.DisciplineID)
+
+			if s.InProgress[c.DisciplineID] {
+				retakes[c.DisciplineID] += d.FailureRate
+				expected[c.DisciplineID] += d.FailureRate
+				continue
+			}
+			if c.SuggestedSemester > s.Level {
+				continue
+			}
+
+			ready, passChance := true, 1.0
+			for _, pre := range prereqs[c.DisciplineID] {
+				if s.Fulfilled[pre] {
+					continue
+				}
+				if !s.InProgress[pre] {
+					ready, passChance = false, 0
+					break
+				}
+				ready = false
+				passChance = math.Min(passChance, 1-stats[pre].FailureRate())
+			}
+
+			switch {
+			case ready:
+				d.Eligible++
+				if c.SuggestedSemester > 0 && c.SuggestedSemester < s.Level {
+					d.Overdue++
+				} else {
+					d.OnTrack++
+				}
+				expected[c.DisciplineID]++
+			case passChance > 0:
+				d.AwaitingPrerequisites++
+				expected[c.DisciplineID] += passChance
+			}
+		}
+	}
+
+	list := make([]Demand, 0, len(demand))
+	for id, d := range demand {
+		d.ExpectedRetakes = int(math.Round(retakes[id]))
+		d.Estimated = int(math.Round(expected[id]))
+		if sectionSize > 0 {
+			d.Sections = (d.Estimated + sectionSize - 1) / sectionSize
+		}
+		list = append(list, *d)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Estimated != list[j].Estimated {
+			return list[i].Estimated > list[j].Estimated
+		}
+		return list[i].DisciplineID < list[j].DisciplineID
+	})
+	return list
+}
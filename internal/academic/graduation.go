@@ -0,0 +1,36 @@
+package academic
+
+import (
+	"fmt"
+	"sistema-faculdade/internal/models"
+)
+
+// AuditGraduation confere se o aluno cumpriu os requisitos de conclusão do curso: as disciplinas
+// obrigatórias da matriz (cursadas, aproveitadas ou por equivalência, conforme fulfilled) e os
+// créditos exigidos pelo curso
+func AuditGraduation(course *models.Course, curriculum []models.CurriculumItem, fulfilled map[int]bool, earnedCredits int) models.GraduationAudit {
+	audit := models.GraduationAudit{
+		CourseID:         course.ID,
+		CourseName:       course.Name,
+		RequiredCredits:  course.TotalCreditsRequired,
+		EarnedCredits:    earnedCredits,
+		MissingMandatory: []models.CurriculumItem{},
+		Pending:          []string{},
+	}
+
+	for _, item := range curriculum {
+		if item.Mandatory && !fulfilled[item.DisciplineID] {
+			audit.MissingMandatory = append(audit.MissingMandatory, item)
+		}
+	}
+	if n := len(audit.MissingMandatory); n > 0 {
+		audit.Pending = append(audit.Pending, fmt.Sprintf("%d disciplina(s) obrigatória(s) não cumprida(s)", n))
+	}
+	if earnedCredits < course.TotalCreditsRequired {
+		audit.Pending = append(audit.Pending, fmt.Sprintf("faltam %d crédito(s) de %d exigidos",
+			course.TotalCreditsRequired-earnedCredits, course.TotalCreditsRequired))
+	}
+
+	audit.Eligible = len(audit.Pending) == 0
+	return audit
+}
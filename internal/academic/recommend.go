@@ -0,0 +1,131 @@
+package academic
+
+import "sort"
+
+// Candidate é uma oferta do semestre que o aluno ainda pode cursar
+type Candidate struct {
+	OfferID      int
+	DisciplineID int
+	Credits      int
+	Schedule     string
+	// SuggestedSemester é o semestre da disciplina na matriz (0 quando não informado)
+	SuggestedSemester int
+	Mandatory         bool
+	Prerequisites     []int
+}
+
+// Motivos para uma oferta não ser recomendada
+const (
+	SkipPrerequisite    = "prerequisite"
+	SkipConflict        = "schedule_conflict"
+	SkipCreditLimit     = "credit_limit"
+	SkipInvalidSchedule = "invalid_schedule"
+)
+
+// Pick é o resultado da análise de uma candidata
+type Pick struct {
+	Candidate
+	Overdue bool
+	// SkipReason vazio indica que a oferta foi recomendada
+	SkipReason string
+	// MissingPrerequisites lista os pré-requisitos ainda não cumpridos
+	MissingPrerequisites []int
+}
+
+// Recommend escolhe as ofertas na ordem de prioridade: obrigatórias atrasadas, obrigatórias do
+// semestre atual do aluno, demais obrigatórias e por fim optativas. Ofertas sem pré-requisitos
+// cumpridos, com choque de horário ou que passariam do limite de créditos (0 = sem limite) são puladas.
+// As matrículas que o aluno já tem no semestre (registered) ocupam horário e créditos desde o início.
+func Recommend(candidates, registered []Candidate, completed map[int]bool, level, maxCredits int) []Pick {
+	picks := make([]Pick, len(candidates))
+	for i, c := range candidates {
+		picks[i] = Pick{
+			Candidate: c,
+			Overdue:   c.Mandatory && c.SuggestedSemester > 0 && c.SuggestedSemester < level,
+		}
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		pi, pj := priority(picks[i], level), priority(picks[j], level)
+		if pi != pj {
+			return pi < pj
+		}
+		si, sj := picks[i].SuggestedSemester, picks[j].SuggestedSemester
+		if si == 0 {
+			si = 1 << 30
+		}
+		if sj == 0 {
+			sj = 1 << 30
+		}
+		return si < sj
+	})
+
+	var chosen []Slot
+	credits := 0
+	for _, c := range registered {
+		// Um horário inválido já gravado não impede a recomendação das demais ofertas
+		if slots, err := ParseSchedule(c.Schedule); err == nil {
+			chosen = append(chosen, slots...)
+		}
+		credits += c.Credits
+	}
+
+	for i := range picks {
+		p := &picks[i]
+
+		for _, pre := range p.Prerequisites {
+			if !completed[pre] {
+				p.MissingPrerequisites = append(p.MissingPrerequisites, pre)
+			}
+		}
+		if len(p.MissingPrerequisites) > 0 {
+			p.SkipReason = SkipPrerequisite
+			continue
+		}
+
+		slots, err := ParseSchedule(p.Schedule)
+		if err != nil {
+			p.SkipReason = SkipInvalidSchedule
+			continue
+		}
+		if SchedulesConflict(chosen, slots) {
+			p.SkipReason = SkipConflict
+			continue
+		}
+
+		if maxCredits > 0 && credits+p.Credits > maxCredits {
+			p.SkipReason = SkipCreditLimit
+			continue
+		}
+
+		chosen = append(chosen, slots...)
+		credits += p.Credits
+	}
+
+	return picks
+}
+
+func priority(p Pick, level int) int {
+	switch {
+	case p.Overdue:
+		return 0
+	case p.Mandatory && p.SuggestedSemester == level:
+		return 1
+	case p.Mandatory:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SchedulesConflict indica se algum encontro de a se sobrepõe a algum encontro de b
+func SchedulesConflict(a, b []Slot) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Overlaps(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
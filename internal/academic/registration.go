@@ -0,0 +1,223 @@
+package academic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultRegistrationFormat é o formato de matrícula usado quando REGISTRATION_NUMBER_FORMAT não é definido:
+// ano, período de ingresso, sequência de quatro dígitos e dígito verificador (2025100013)
+const DefaultRegistrationFormat = "{YYYY}{P}{SEQ:4}{DV}"
+
+// RegistrationParts são os dados do aluno usados para montar a matrícula
+type RegistrationParts struct {
+	Year   int
+	Period int
+	Course string
+}
+
+// RegistrationFormat é um formato de matrícula. Campos aceitos:
+//
+//	{YYYY} ano de ingresso      {YY} ano com dois dígitos   {P} período de ingresso
+//	{COURSE} código do curso    {SEQ} ou {SEQ:n} sequência com n dígitos (padrão 4)
+//	{DV} dígito verificador (módulo 11), obrigatoriamente no final
+//
+// Qualquer outro texto é mantido. A sequência é contada por escopo: a matrícula montada sem a
+// sequência e o dígito (ano + período + curso no formato padrão).
+type RegistrationFormat struct {
+	raw        string
+	tokens     []string
+	seqWidth   int
+	checkDigit bool
+	pattern    *regexp.Regexp
+}
+
+var registrationToken = regexp.MustCompile(`\{(YYYY|YY|P|COURSE|SEQ(?::([1-9]))?|DV)\}`)
+
+func ParseRegistrationFormat(s string) (RegistrationFormat, error) {
+	f := RegistrationFormat{raw: s}
+	if strings.TrimSpace(s) == "" {
+		return f, fmt.Errorf("formato de matrícula vazio")
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	seqs := 0
+	for _, m := range registrationToken.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > last {
+			literal := s[last:m[0]]
+			if strings.ContainsAny(literal, "{}") {
+				return f, fmt.Errorf("campo desconhecido no formato de matrícula: %q", literal)
+			}
+			f.tokens = append(f.tokens, literal)
+			pattern.WriteString(regexp.QuoteMeta(literal))
+		}
+		if f.checkDigit {
+			return f, fmt.Errorf("o dígito verificador {DV} deve ser o último campo da matrícula")
+		}
+
+		name := s[m[2]:m[3]]
+		switch {
+		case name == "YYYY":
+			pattern.WriteString(`\d{4}`)
+		case name == "YY":
+			pattern.WriteString(`\d{2}`)
+		case name == "P":
+			pattern.WriteString(`\d{1,2}`)
+		case name == "COURSE":
+			pattern.WriteString(`[A-Z0-9]+`)
+		case name == "DV":
+			f.checkDigit = true
+			pattern.WriteString(`\d`)
+		default:
+			seqs++
+			f.seqWidth = 4
+			if m[4] >= 0 {
+				f.seqWidth, _ = strconv.Atoi(s[m[4]:m[5]])
+			}
+			name = "SEQ"
+			fmt.Fprintf(&pattern, `\d{%d,}`, f.seqWidth)
+		}
+		f.tokens = append(f.tokens, "{"+name+"}")
+		last = m[1]
+	}
+	if last < len(s) {
+		if f.checkDigit {
+			return f, fmt.Errorf("o dígito verificador {DV} deve ser o último campo da matrícula")
+		}
+		literal := s[last:]
+		if strings.ContainsAny(literal, "{}") {
+			return f, fmt.Errorf("campo desconhecido no formato de matrícula: %q", literal)
+		}
+		f.tokens = append(f.tokens, literal)
+		pattern.WriteString(regexp.QuoteMeta(literal))
+	}
+	if seqs != 1 {
+		return f, fmt.Errorf("o formato de matrícula deve ter exatamente uma sequência {SEQ}")
+	}
+	pattern.WriteString("$")
+	f.pattern = regexp.MustCompile(pattern.String())
+	return f, nil
+}
+
+// MustParseRegistrationFormat é ParseRegistrationFormat para formatos fixos no código
+func MustParseRegistrationFormat(s string) RegistrationFormat {
+	f, err := ParseRegistrationFormat(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// OrDefault retorna o formato padrão quando f não foi configurado
+func (f RegistrationFormat) OrDefault() RegistrationFormat {
+	if f.pattern == nil {
+		return MustParseRegistrationFormat(DefaultRegistrationFormat)
+	}
+	return f
+}
+
+func (f RegistrationFormat) String() string {
+	return f.raw
+}
+
+// Scope é a matrícula sem a sequência e o dígito; cada escopo tem a sua própria sequência
+func (f RegistrationFormat) Scope(p RegistrationParts) string {
+	return f.render(p, "#")
+}
+
+// Build monta a matrícula com a sequência e, se o formato pedir, o dígito verificador
+func (f RegistrationFormat) Build(p RegistrationParts, seq int) string {
+	number := f.render(p, fmt.Sprintf("%0*d", f.seqWidth, seq))
+	if f.checkDigit {
+		number += strconv.Itoa(CheckDigit(number))
+	}
+	return number
+}
+
+func (f RegistrationFormat) render(p RegistrationParts, seq string) string {
+	var b strings.Builder
+	for _, t := range f.tokens {
+		switch t {
+		case "{YYYY}":
+			fmt.Fprintf(&b, "%04d", p.Year)
+		case "{YY}":
+			fmt.Fprintf(&b, "%02d", p.Year%100)
+		case "{P}":
+			b.WriteString(strconv.Itoa(p.Period))
+		case "{COURSE}":
+			b.WriteString(strings.ToUpper(p.Course))
+		case "{SEQ}":
+			b.WriteString(seq)
+		case "{DV}":
+		default:
+			b.WriteString(t)
+		}
+	}
+	return b.String()
+}
+
+// Matches indica se o número tem a forma das matrículas geradas pelo formato.
+// Números que não têm essa forma são tratados como matrículas legadas.
+func (f RegistrationFormat) Matches(number string) bool {
+	return f.pattern != nil && f.pattern.MatchString(number)
+}
+
+// Sequence extrai a sequência de uma matrícula do escopo informado (ver Scope); false se o número
+// não pertence ao escopo
+func (f RegistrationFormat) Sequence(number, scope string) (int, bool) {
+	prefix, suffix, ok := strings.Cut(scope, "#")
+	if !ok || !strings.HasPrefix(number, prefix) {
+		return 0, false
+	}
+	rest := number[len(prefix):]
+	if f.checkDigit {
+		if rest == "" {
+			return 0, false
+		}
+		rest = rest[:len(rest)-1]
+	}
+	seq, ok := strings.CutSuffix(rest, suffix)
+	if !ok || len(seq) < f.seqWidth || strings.Trim(seq, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(seq)
+	return n, err == nil
+}
+
+// Valid confere o dígito verificador de uma matrícula no formato (sempre válida sem {DV})
+func (f RegistrationFormat) Valid(number string) bool {
+	if !f.checkDigit {
+		return true
+	}
+	if len(number) < 2 {
+		return false
+	}
+	body, dv := number[:len(number)-1], number[len(number)-1]
+	return dv >= '0' && dv <= '9' && CheckDigit(body) == int(dv-'0')
+}
+
+// CheckDigit calcula o dígito verificador módulo 11 (pesos 2 a 9 da direita para a esquerda)
+// sobre os algarismos do texto; restos que resultariam em 10 ou 11 viram 0
+func CheckDigit(s string) int {
+	sum, weight := 0, 2
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		sum += int(c-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	dv := 11 - sum%11
+	if dv >= 10 {
+		return 0
+	}
+	return dv
+}
@@ -0,0 +1,177 @@
+package academic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultClassDuration é usada quando o horário da oferta informa apenas o início (ex: "Seg/Qua 10h")
+const DefaultClassDuration = 2 * time.Hour
+
+// Slot representa um encontro semanal de uma oferta
+// Start e End são deslocamentos a partir da meia-noite
+type Slot struct {
+	Weekday time.Weekday
+	Start   time.Duration
+	End     time.Duration
+}
+
+// Hours retorna a duração do encontro em horas
+func (s Slot) Hours() float64 {
+	return (s.End - s.Start).Hours()
+}
+
+// Overlaps indica se dois encontros acontecem no mesmo dia com horários sobrepostos
+func (s Slot) Overlaps(o Slot) bool {
+	return s.Weekday == o.Weekday && s.Start < o.End && o.Start < s.End
+}
+
+var weekdays = map[string]time.Weekday{
+	"dom": time.Sunday,
+	"seg": time.Monday,
+	"ter": time.Tuesday,
+	"qua": time.Wednesday,
+	"qui": time.Thursday,
+	"sex": time.Friday,
+	"sab": time.Saturday,
+	"sáb": time.Saturday,
+}
+
+// ParseSchedule interpreta o texto livre de discipline_offers.schedule.
+// Formatos aceitos: "Seg/Qua 10h", "Ter,Qui 14h-16h", "Seg 08:00-09:40; Sex 10h30-12h".
+func ParseSchedule(schedule string) ([]Slot, error) {
+	var slots []Slot
+
+	for _, group := range strings.Split(schedule, ";") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		fields := strings.Fields(group)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("horário inválido: %q", group)
+		}
+
+		start, end, err := parseTimeRange(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, day := range strings.FieldsFunc(fields[0], func(r rune) bool { return r == '/' || r == ',' }) {
+			wd, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+			if !ok {
+				return nil, fmt.Errorf("dia da semana inválido: %q", day)
+			}
+			slots = append(slots, Slot{Weekday: wd, Start: start, End: end})
+		}
+	}
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("horário vazio")
+	}
+
+	return slots, nil
+}
+
+func parseTimeRange(s string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(s, "-", 2)
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end := start + DefaultClassDuration
+	if len(parts) == 2 {
+		end, err = parseClock(parts[1])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if end <= start {
+		return 0, 0, fmt.Errorf("horário de término deve ser após o início: %q", s)
+	}
+	return start, end, nil
+}
+
+// parseClock aceita "10h", "10h30", "10:30" e "10"
+func parseClock(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.Replace(s, "h", ":", 1)
+	s = strings.TrimSuffix(s, ":")
+
+	hourStr, minStr, _ := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("hora inválida: %q", s)
+	}
+
+	minute := 0
+	if minStr != "" {
+		minute, err = strconv.Atoi(minStr)
+		if err != nil || minute < 0 || minute > 59 {
+			return 0, fmt.Errorf("minuto inválido: %q", s)
+		}
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// Occurrence é um encontro concreto de uma oferta em uma data
+type Occurrence struct {
+	Date  time.Time
+	Start time.Time
+	End   time.Time
+}
+
+// Occurrences expande os encontros semanais entre start e end (inclusive),
+// ignorando as datas para as quais skip retorna true (feriados, recessos...)
+func Occurrences(slots []Slot, start, end time.Time, skip func(time.Time) bool) []Occurrence {
+	var list []Occurrence
+
+	first := truncateDay(start)
+	last := truncateDay(end)
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if skip != nil && skip(day) {
+			continue
+		}
+		for _, slot := range slots {
+			if slot.Weekday != day.Weekday() {
+				continue
+			}
+			list = append(list, Occurrence{
+				Date:  day,
+				Start: day.Add(slot.Start),
+				End:   day.Add(slot.End),
+			})
+		}
+	}
+
+	return list
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WithinSlots indica se todos os encontros de a cabem em algum encontro de allowed no mesmo dia
+func WithinSlots(a, allowed []Slot) bool {
+	for _, x := range a {
+		fits := false
+		for _, y := range allowed {
+			if x.Weekday == y.Weekday && x.Start >= y.Start && x.End <= y.End {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package academic
+
+// Situações da carga docente frente ao contrato
+const (
+	WorkloadOK             = "ok"
+	WorkloadOverloaded     = "overloaded"
+	WorkloadUnderallocated = "underallocated"
+	WorkloadNoContract     = "no_contract"
+)
+
+// DefaultSemesterWeeks é usado para estimar as horas semanais quando o semestre não tem datas
+const DefaultSemesterWeeks = 18
+
+// WeeklyHours retorna as horas semanais de aula de uma oferta a partir do horário.
+// Se o horário não puder ser interpretado, estima pela carga do semestre dividida pelas semanas
+// e retorna estimated = true.
+func WeeklyHours(schedule string, semesterHours int, weeks float64) (hours float64, estimated bool) {
+	slots, err := ParseSchedule(schedule)
+	if err == nil && len(slots) > 0 {
+		for _, s := range slots {
+			hours += s.Hours()
+		}
+		return hours, false
+	}
+
+	if weeks <= 0 {
+		weeks = DefaultSemesterWeeks
+	}
+	return float64(semesterHours) / weeks, true
+}
+
+// FullTimeClassShare é a fração máxima das horas de um contrato em tempo integral que pode ser
+// dada em sala de aula; o restante fica para estudo, pesquisa, extensão e planejamento
+const FullTimeClassShare = 0.5
+
+// ClassifyWorkload compara as horas semanais de aula com as horas do contrato.
+// Acima do limite de sala de aula (o contrato inteiro, ou metade dele em tempo integral) é sobrecarga;
+// abaixo de minRatio desse limite é subalocação.
+func ClassifyWorkload(weeklyHours float64, contractHours int, fullTime bool, minRatio float64) string {
+	if contractHours <= 0 {
+		return WorkloadNoContract
+	}
+
+	limit := float64(contractHours)
+	if fullTime {
+		limit *= FullTimeClassShare
+	}
+	switch {
+	case weeklyHours > limit:
+		return WorkloadOverloaded
+	case weeklyHours < limit*minRatio:
+		return WorkloadUnderallocated
+	}
+	return WorkloadOK
+}
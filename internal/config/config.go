@@ -0,0 +1,52 @@
+// Package config lê a configuração comum ao servidor e aos comandos de linha: o arquivo .env do
+// diretório atual e as variáveis de ambiente.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sistema-faculdade/internal/academic"
+	"strings"
+)
+
+// LoadEnv carrega as variáveis do arquivo .env do diretório atual. Variáveis já definidas no
+// ambiente têm prioridade sobre as do arquivo.
+func LoadEnv() error {
+	file, err := os.Open(".env")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			key := strings.TrimSpace(parts[0])
+			if _, set := os.LookupEnv(key); !set {
+				os.Setenv(key, strings.TrimSpace(parts[1]))
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// RegistrationNumbering é o formato das matrículas geradas (REGISTRATION_NUMBER_FORMAT); sem a
+// variável, usa academic.DefaultRegistrationFormat
+func RegistrationNumbering() (academic.RegistrationFormat, error) {
+	format := os.Getenv("REGISTRATION_NUMBER_FORMAT")
+	if format == "" {
+		return academic.MustParseRegistrationFormat(academic.DefaultRegistrationFormat), nil
+	}
+
+	numbering, err := academic.ParseRegistrationFormat(format)
+	if err != nil {
+		return numbering, fmt.Errorf("REGISTRATION_NUMBER_FORMAT inválido: %w", err)
+	}
+	return numbering, nil
+}
@@ -0,0 +1,495 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type AdmissionRepository struct {
+	DB *sql.DB
+	// Numbering é o formato das matrículas dos aprovados (o mesmo de StudentRepository)
+	Numbering academic.RegistrationFormat
+}
+
+const admissionProcessSelect = `
+	SELECT p.id, p.name, p.method, p.semester_id, semester_label(s.kind, s.year, s.period), p.created_by, p.created_at
+	FROM admission_processes p
+	JOIN academic_semesters s ON s.id = p.semester_id
+`
+
+func (r *AdmissionRepository) CreateProcess(p *models.AdmissionProcess) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO admission_processes (name, method, semester_id, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, p.Name, p.Method, p.SemesterID, p.CreatedBy).Scan(&id)
+	if err != nil {
+		return 0, admissionProcessError(err)
+	}
+
+	for _, seat := range p.Seats {
+		_, err := tx.Exec(`INSERT INTO admission_seats (process_id, course_id, seats) VALUES ($1, $2, $3)`,
+			id, seat.CourseID, seat.Seats)
+		if err != nil {
+			return 0, admissionProcessError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func admissionProcessError(err error) error {
+	if pgErr, ok := err.(*pq.Error); ok {
+		switch pgErr.Code {
+		case "23505":
+			if pgErr.Constraint == "admission_processes_name_key" {
+				return fmt.Errorf("processo seletivo já cadastrado")
+			}
+			return fmt.Errorf("curso repetido nas vagas")
+		case "23503":
+			return fmt.Errorf("curso ou semestre inexistente")
+		}
+	}
+	return fmt.Errorf("erro ao criar processo seletivo: %w", err)
+}
+
+func (r *AdmissionRepository) GetProcesses() ([]models.AdmissionProcess, error) {
+	rows, err := r.DB.Query(admissionProcessSelect + ` ORDER BY s.year DESC, p.created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar processos seletivos: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.AdmissionProcess{}
+	for rows.Next() {
+		var p models.AdmissionProcess
+		if err := rows.Scan(&p.ID, &p.Name, &p.Method, &p.SemesterID, &p.Semester, &p.CreatedBy, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao escanear processo seletivo: %w", err)
+		}
+		list = append(list, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os processos seletivos: %w", err)
+	}
+
+	for i := range list {
+		if list[i].Seats, err = r.seats(list[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (r *AdmissionRepository) GetProcess(id int) (*models.AdmissionProcess, error) {
+	var p models.AdmissionProcess
+	err := r.DB.QueryRow(admissionProcessSelect+` WHERE p.id = $1`, id).Scan(
+		&p.ID, &p.Name, &p.Method, &p.SemesterID, &p.Semester, &p.CreatedBy, &p.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar processo seletivo: %w", err)
+	}
+
+	if p.Seats, err = r.seats(id); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// seats retorna as vagas por curso com a ocupação atual
+func (r *AdmissionRepository) seats(processID int) ([]models.AdmissionSeat, error) {
+	rows, err := r.DB.Query(`
+		SELECT s.course_id, c.name, s.seats, COUNT(a.id),
+		       COUNT(a.id) FILTER (WHERE a.status = 'called'),
+		       COUNT(a.id) FILTER (WHERE a.status = 'confirmed')
+		FROM admission_seats s
+		JOIN courses c ON c.id = s.course_id
+		LEFT JOIN applicants a ON a.process_id = s.process_id AND a.course_id = s.course_id
+		WHERE s.process_id = $1
+		GROUP BY s.course_id, c.name, s.seats
+		ORDER BY c.name
+	`, processID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar vagas do processo seletivo: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.AdmissionSeat{}
+	for rows.Next() {
+		var s models.AdmissionSeat
+		if err := rows.Scan(&s.CourseID, &s.CourseName, &s.Seats, &s.Applicants, &s.Called, &s.Confirmed); err != nil {
+			return nil, fmt.Errorf("erro ao escanear vagas: %w", err)
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+const applicantSelect = `
+	SELECT a.id, a.process_id, a.course_id, c.name, a.name, a.cpf, a.email, COALESCE(a.gender, ''), a.date_birth,
+	       a.score_languages, a.score_humanities, a.score_sciences, a.score_math, a.score_essay,
+	       a.final_score, a.rank, a.status, a.call_number, a.called_at, a.student_id, a.created_at
+	FROM applicants a
+	JOIN courses c ON c.id = a.course_id
+`
+
+func scanApplicant(row interface{ Scan(...any) error }) (*models.Applicant, error) {
+	var a models.Applicant
+	var languages, humanities, sciences, math, essay sql.NullFloat64
+	err := row.Scan(
+		&a.ID, &a.ProcessID, &a.CourseID, &a.CourseName, &a.Name, &a.CPF, &a.Email, &a.Gender, &a.DateBirth,
+		&languages, &humanities, &sciences, &math, &essay,
+		&a.FinalScore, &a.Rank, &a.Status, &a.CallNumber, &a.CalledAt, &a.StudentID, &a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if languages.Valid {
+		a.Enem = &models.EnemScores{
+			Languages:  languages.Float64,
+			Humanities: humanities.Float64,
+			Sciences:   sciences.Float64,
+			Math:       math.Float64,
+			Essay:      essay.Float64,
+		}
+	}
+	return &a, nil
+}
+
+// GetApplicants lista os candidatos do processo pela classificação; courseID, status e
+// callNumber são filtros opcionais (zero/vazio para todos)
+func (r *AdmissionRepository) GetApplicants(processID, courseID int, status string, callNumber int) ([]models.Applicant, error) {
+	rows, err := r.DB.Query(applicantSelect+`
+		WHERE a.process_id = $1 AND ($2 = 0 OR a.course_id = $2) AND ($3 = '' OR a.status::text = $3)
+		  AND ($4 = 0 OR a.call_number = $4)
+		ORDER BY c.name, a.rank NULLS LAST, a.name
+	`, processID, courseID, status, callNumber)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar candidatos: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.Applicant{}
+	for rows.Next() {
+		a, err := scanApplicant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear candidato: %w", err)
+		}
+		list = append(list, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os candidatos: %w", err)
+	}
+	return list, nil
+}
+
+func (r *AdmissionRepository) GetApplicant(id int) (*models.Applicant, error) {
+	a, err := scanApplicant(r.DB.QueryRow(applicantSelect+` WHERE a.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar candidato: %w", err)
+	}
+	return a, nil
+}
+
+func (r *AdmissionRepository) CreateApplicant(a *models.Applicant) (int, error) {
+	var id int
+	err := r.DB.QueryRow(`
+		INSERT INTO applicants (process_id, course_id, name, cpf, email, gender, date_birth)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id
+	`, a.ProcessID, a.CourseID, a.Name, a.CPF, a.Email, a.Gender, a.DateBirth).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			switch pgErr.Code {
+			case "23505":
+				return 0, fmt.Errorf("candidato já inscrito neste processo")
+			case "23503":
+				return 0, fmt.Errorf("o curso não tem vagas neste processo")
+			case "23514":
+				return 0, fmt.Errorf("dados do candidato inválidos")
+			}
+		}
+		return 0, fmt.Errorf("erro ao inscrever candidato: %w", err)
+	}
+	return id, nil
+}
+
+// hasCalls indica se o processo já publicou alguma chamada; a partir daí notas e classificação ficam fixas
+func hasCalls(tx *sql.Tx, processID int) (bool, error) {
+	var called bool
+	err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM applicants WHERE process_id = $1 AND call_number IS NOT NULL)`,
+		processID).Scan(&called)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar chamadas: %w", err)
+	}
+	return called, nil
+}
+
+// ImportScores grava as notas pelo CPF do candidato. Se algum CPF não estiver inscrito nada é
+// gravado e os CPFs são devolvidos em missing.
+func (r *AdmissionRepository) ImportScores(processID int, scores []models.ApplicantScore) (missing []string, err error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM admission_processes WHERE id = $1 FOR UPDATE`, processID); err != nil {
+		return nil, fmt.Errorf("erro ao bloquear processo seletivo: %w", err)
+	}
+	called, err := hasCalls(tx, processID)
+	if err != nil {
+		return nil, err
+	}
+	if called {
+		return nil, fmt.Errorf("processo seletivo: as notas não podem mudar depois da primeira chamada")
+	}
+
+	for _, s := range scores {
+		var res sql.Result
+		if s.Enem != nil {
+			res, err = tx.Exec(`
+				UPDATE applicants
+				SET score_languages = $3, score_humanities = $4, score_sciences = $5, score_math = $6, score_essay = $7,
+				    final_score = $8, rank = NULL
+				WHERE process_id = $1 AND cpf = $2
+			`, processID, s.CPF, s.Enem.Languages, s.Enem.Humanities, s.Enem.Sciences, s.Enem.Math, s.Enem.Essay, s.FinalScore)
+		} else {
+			res, err = tx.Exec(`
+				UPDATE applicants
+				SET score_languages = NULL, score_humanities = NULL, score_sciences = NULL, score_math = NULL,
+				    score_essay = NULL, final_score = $3, rank = NULL
+				WHERE process_id = $1 AND cpf = $2
+			`, processID, s.CPF, s.FinalScore)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gravar notas do CPF %s: %w", s.CPF, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			missing = append(missing, s.CPF)
+		}
+	}
+
+	if len(missing) > 0 {
+		return missing, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// Rank classifica os candidatos com nota por curso: maior nota final, depois maior nota de
+// redação e, persistindo o empate, o candidato mais velho. Retorna quantos foram classificados.
+func (r *AdmissionRepository) Rank(processID int) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM admission_processes WHERE id = $1 FOR UPDATE`, processID); err != nil {
+		return 0, fmt.Errorf("erro ao bloquear processo seletivo: %w", err)
+	}
+	called, err := hasCalls(tx, processID)
+	if err != nil {
+		return 0, err
+	}
+	if called {
+		return 0, fmt.Errorf("processo seletivo: a classificação não pode mudar depois da primeira chamada")
+	}
+
+	if _, err := tx.Exec(`UPDATE applicants SET rank = NULL WHERE process_id = $1`, processID); err != nil {
+		return 0, fmt.Errorf("erro ao limpar classificação: %w", err)
+	}
+	res, err := tx.Exec(`
+		UPDATE applicants a
+		SET rank = r.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY course_id
+				ORDER BY final_score DESC, score_essay DESC NULLS LAST, date_birth ASC, id
+			) AS position
+			FROM applicants
+			WHERE process_id = $1 AND final_score IS NOT NULL
+		) r
+		WHERE a.id = r.id
+	`, processID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao classificar candidatos: %w", err)
+	}
+	n, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// NextCall publica a próxima chamada: os convocados de chamadas anteriores que não confirmaram
+// perdem a vaga, e as vagas livres de cada curso vão para os próximos classificados.
+func (r *AdmissionRepository) NextCall(processID int) (*models.AdmissionCall, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM admission_processes WHERE id = $1 FOR UPDATE`, processID); err != nil {
+		return nil, fmt.Errorf("erro ao bloquear processo seletivo: %w", err)
+	}
+
+	var ranked bool
+	var last int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE rank IS NOT NULL) > 0, COALESCE(MAX(call_number), 0)
+		FROM applicants WHERE process_id = $1
+	`, processID).Scan(&ranked, &last)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chamadas: %w", err)
+	}
+	if !ranked {
+		return nil, fmt.Errorf("processo seletivo: classifique os candidatos antes da chamada")
+	}
+
+	call := &models.AdmissionCall{CallNumber: last + 1}
+
+	res, err := tx.Exec(`UPDATE applicants SET status = 'forfeited' WHERE process_id = $1 AND status = 'called'`, processID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao encerrar chamada anterior: %w", err)
+	}
+	forfeited, _ := res.RowsAffected()
+	call.Forfeited = int(forfeited)
+
+	rows, err := tx.Query(`
+		WITH open AS (
+			SELECT s.course_id, s.seats - COUNT(a.id) FILTER (WHERE a.status = 'confirmed') AS free
+			FROM admission_seats s
+			LEFT JOIN applicants a ON a.process_id = s.process_id AND a.course_id = s.course_id
+			WHERE s.process_id = $1
+			GROUP BY s.course_id, s.seats
+		), queue AS (
+			SELECT id, course_id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY rank) AS position
+			FROM applicants
+			WHERE process_id = $1 AND status = 'registered' AND rank IS NOT NULL
+		)
+		UPDATE applicants a
+		SET status = 'called', call_number = $2, called_at = CURRENT_TIMESTAMP
+		FROM queue q
+		JOIN open o ON o.course_id = q.course_id
+		WHERE a.id = q.id AND q.position <= o.free
+		RETURNING a.id
+	`, processID, call.CallNumber)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao convocar candidatos: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("erro ao escanear convocado: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("processo seletivo: não há vagas livres ou candidatos classificados em espera")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	call.Called, err = r.GetApplicants(processID, 0, "", call.CallNumber)
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// Confirm confirma a matrícula do candidato convocado: cria o aluno no curso escolhido com o
+// semestre de ingresso do processo e um número de matrícula gerado.
+func (r *AdmissionRepository) Confirm(applicantID int, changedBy string) (*models.Student, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status, processName string
+	s := &models.Student{Status: models.StudentEnrolled, Active: true}
+	var gender sql.NullString
+	err = tx.QueryRow(`
+		SELECT a.status, a.name, a.email, a.gender, a.date_birth, a.cpf, a.course_id, p.semester_id, p.name
+		FROM applicants a
+		JOIN admission_processes p ON p.id = a.process_id
+		WHERE a.id = $1
+		FOR UPDATE OF a
+	`, applicantID).Scan(&status, &s.Name, &s.Email, &gender, &s.DateBirth, &s.CPF, &s.CourseID, &s.EntrySemesterID,
+		&processName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("nenhum candidato encontrado com o ID %d", applicantID)
+		}
+		return nil, fmt.Errorf("erro ao buscar candidato: %w", err)
+	}
+	if status != models.ApplicantCalled {
+		return nil, fmt.Errorf("processo seletivo: só candidatos convocados podem confirmar a matrícula (situação atual: %s)", status)
+	}
+	s.Gender = gender.String
+
+	s.ID, err = createStudent(tx, r.Numbering, s, changedBy, "Ingresso pelo processo seletivo "+processName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE applicants SET status = 'confirmed', student_id = $2 WHERE id = $1`, applicantID, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao confirmar candidato: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Decline registra a desistência do candidato, liberando a vaga para a próxima chamada
+func (r *AdmissionRepository) Decline(applicantID int) error {
+	res, err := r.DB.Exec(`
+		UPDATE applicants SET status = 'declined'
+		WHERE id = $1 AND status IN ('registered', 'called')
+	`, applicantID)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar desistência: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("processo seletivo: candidato inexistente ou com matrícula já confirmada")
+	}
+	return nil
+}
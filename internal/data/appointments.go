@@ -0,0 +1,116 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Chefias de departamento e coordenações de curso são guardadas em tabelas com a mesma estrutura
+// (department_heads e course_coordinators), diferindo só na coluna da unidade.
+
+func listAppointments(db *sql.DB, table, column string, unitID int) ([]models.Appointment, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT a.id, a.teacher_id, t.name, a.start_date, a.end_date, a.assigned_by, a.created_at
+		FROM %s a
+		JOIN teachers t ON t.id = a.teacher_id
+		WHERE a.%s = $1
+		ORDER BY a.start_date DESC
+	`, table, column), unitID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mandatos: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.Appointment{}
+	for rows.Next() {
+		var a models.Appointment
+		if err := rows.Scan(&a.ID, &a.TeacherID, &a.TeacherName, &a.StartDate, &a.EndDate, &a.AssignedBy, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao escanear mandato: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os mandatos: %w", err)
+	}
+	return list, nil
+}
+
+// appoint registra um novo mandato na unidade. O mandato anterior, se ainda estiver em vigor
+// na data de início, é encerrado na véspera; o histórico não é apagado.
+func appoint(db *sql.DB, table, column string, unitID int, a *models.Appointment) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var lastID int
+	var lastStart time.Time
+	var lastEnd *time.Time
+	err = tx.QueryRow(fmt.Sprintf(`
+		SELECT id, start_date, end_date FROM %s
+		WHERE %s = $1
+		ORDER BY start_date DESC
+		LIMIT 1
+		FOR UPDATE
+	`, table, column), unitID).Scan(&lastID, &lastStart, &lastEnd)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("erro ao buscar mandato atual: %w", err)
+	}
+	if err == nil {
+		if !a.StartDate.After(lastStart) {
+			return 0, fmt.Errorf("nomeação: o início deve ser posterior ao início do mandato atual (%s)", lastStart.Format("02/01/2006"))
+		}
+		if lastEnd == nil || !lastEnd.Before(a.StartDate) {
+			_, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET end_date = $2::date - 1 WHERE id = $1`, table), lastID, a.StartDate)
+			if err != nil {
+				return 0, fmt.Errorf("erro ao encerrar mandato anterior: %w", err)
+			}
+		}
+	}
+
+	var id int
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (%s, teacher_id, start_date, end_date, assigned_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, table, column), unitID, a.TeacherID, a.StartDate, a.EndDate, a.AssignedBy).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return 0, fmt.Errorf("nomeação: professor inexistente")
+		}
+		return 0, fmt.Errorf("erro ao registrar mandato: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// appointedUnits retorna as unidades em que o professor tem mandato em vigor hoje
+func appointedUnits(db *sql.DB, table, column string, teacherID int) ([]int, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE teacher_id = $1 AND start_date <= CURRENT_DATE AND (end_date IS NULL OR end_date >= CURRENT_DATE)
+	`, column, table), teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mandatos do professor: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao escanear mandato: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,145 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+)
+
+type CalendarRepository struct {
+	DB *sql.DB
+}
+
+func (r *CalendarRepository) GetBySemester(semesterID int) ([]models.CalendarEvent, error) {
+	query := `
+		SELECT id, semester_id, kind, start_date, end_date, description
+		FROM calendar_events
+		WHERE semester_id = $1
+		ORDER BY start_date ASC, id ASC
+	`
+
+	rows, err := r.DB.Query(query, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar calendário acadêmico: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.CalendarEvent
+	for rows.Next() {
+		var e models.CalendarEvent
+		err := rows.Scan(&e.ID, &e.SemesterID, &e.Kind, &e.StartDate, &e.EndDate, &e.Description)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear evento do calendário: %w", err)
+		}
+		list = append(list, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os eventos do calendário: %w", err)
+	}
+	return list, nil
+}
+
+func (r *CalendarRepository) GetByID(id int) (*models.CalendarEvent, error) {
+	query := `
+		SELECT id, semester_id, kind, start_date, end_date, description
+		FROM calendar_events
+		WHERE id = $1
+	`
+
+	var e models.CalendarEvent
+	err := r.DB.QueryRow(query, id).Scan(&e.ID, &e.SemesterID, &e.Kind, &e.StartDate, &e.EndDate, &e.Description)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar evento do calendário: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *CalendarRepository) Create(e *models.CalendarEvent) (int, error) {
+	query := `
+		INSERT INTO calendar_events (semester_id, kind, start_date, end_date, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var id int
+	err := r.DB.QueryRow(query, e.SemesterID, e.Kind, e.StartDate, e.EndDate, e.Description).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar evento do calendário: %w", err)
+	}
+	return id, nil
+}
+
+func (r *CalendarRepository) Update(e *models.CalendarEvent) error {
+	query := `
+		UPDATE calendar_events
+		SET kind = $1, start_date = $2, end_date = $3, description = $4
+		WHERE id = $5
+	`
+
+	result, err := r.DB.Exec(query, e.Kind, e.StartDate, e.EndDate, e.Description, e.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar evento do calendário: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhum evento encontrado com o ID %d", e.ID)
+	}
+	return nil
+}
+
+func (r *CalendarRepository) Delete(id int) error {
+	result, err := r.DB.Exec(`DELETE FROM calendar_events WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar evento do calendário: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhum evento encontrado com o ID %d", id)
+	}
+	return nil
+}
+
+// Import grava os eventos do semestre em uma única transação.
+// Com replace, o calendário existente do semestre é apagado antes.
+func (r *CalendarRepository) Import(semesterID int, events []models.CalendarEvent, replace bool) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM calendar_events WHERE semester_id = $1`, semesterID); err != nil {
+			return fmt.Errorf("erro ao limpar calendário do semestre: %w", err)
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO calendar_events (semester_id, kind, start_date, end_date, description)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return fmt.Errorf("erro ao preparar importação: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, e := range events {
+		if _, err := stmt.Exec(semesterID, e.Kind, e.StartDate, e.EndDate, e.Description); err != nil {
+			return fmt.Errorf("erro ao importar linha %d: %w", i+1, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -4,47 +4,71 @@ import (
 	"database/sql"
 	"fmt"
 	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
 )
 
 type CourseRepository struct {
 	DB *sql.DB
 }
 
+// currentCoordinatorJoin traz o coordenador com mandato em vigor (cc) e seu nome (t)
+const currentCoordinatorJoin = `
+	LEFT JOIN course_coordinators cc ON cc.course_id = c.id
+	  AND cc.start_date <= CURRENT_DATE AND (cc.end_date IS NULL OR cc.end_date >= CURRENT_DATE)
+	LEFT JOIN teachers t ON t.id = cc.teacher_id`
+
 func (r *CourseRepository) GetAll() ([]models.Course, error) {
-	query := `SELECT c.id, c.name, c.total_credits_required, c.duration_semesters, c.created_at
-	FROM courses c 
-	ORDER BY name ASC`
+	var courses []models.Course
+	err := r.Each(func(c models.Course) error {
+		courses = append(courses, c)
+		return nil
+	})
+	return courses, err
+}
+
+// Each percorre os cursos um por vez, na ordem do GetAll (usado pela exportação)
+func (r *CourseRepository) Each(fn func(models.Course) error) error {
+	query := `SELECT c.id, c.name, c.code, c.total_credits_required, c.duration_semesters, c.max_duration_semesters,
+		c.requires_enrollment_approval, c.min_credits_per_semester, c.max_credits_per_semester, c.created_at,
+		cc.teacher_id, t.name
+	FROM courses c ` + currentCoordinatorJoin + `
+	ORDER BY c.name ASC`
 
 	rows, err := r.DB.Query(query)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var courses []models.Course
 	for rows.Next() {
 		var c models.Course
 		err := rows.Scan(
-			&c.ID, &c.Name, &c.TotalCreditsRequired, &c.DurationSemesters, &c.CreatedAt,
+			&c.ID, &c.Name, &c.Code, &c.TotalCreditsRequired, &c.DurationSemesters, &c.MaxDurationSemesters,
+			&c.RequiresEnrollmentApproval, &c.MinCreditsPerSemester, &c.MaxCreditsPerSemester, &c.CreatedAt,
+			&c.CoordinatorID, &c.CoordinatorName,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		courses = append(courses, c)
+		if err := fn(c); err != nil {
+			return err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados dos cursos: %w", err)
+		return fmt.Errorf("erro ao iterar sobre os resultados dos cursos: %w", err)
 	}
 
-	return courses, nil
+	return nil
 }
 
 func (r *CourseRepository) Create(c *models.Course) (int, error) {
 	query := `
-		INSERT INTO courses (name, total_credits_required, duration_semesters)
-		VALUES ($1, $2, $3)
+		INSERT INTO courses (name, code, total_credits_required, duration_semesters, max_duration_semesters,
+		  requires_enrollment_approval, min_credits_per_semester, max_credits_per_semester)
+		VALUES ($1, NULLIF(UPPER($2), ''), $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
@@ -52,12 +76,138 @@ func (r *CourseRepository) Create(c *models.Course) (int, error) {
 
 	err := r.DB.QueryRow(
 		query,
-		c.Name, c.TotalCreditsRequired, c.DurationSemesters,
+		c.Name, c.Code, c.TotalCreditsRequired, c.DurationSemesters, c.MaxDurationSemesters,
+		c.RequiresEnrollmentApproval, c.MinCreditsPerSemester, c.MaxCreditsPerSemester,
 	).Scan(&id)
 
 	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" && pgErr.Constraint == "courses_code_key" {
+				return 0, fmt.Errorf("sigla de curso já cadastrada")
+			}
+			if pgErr.Code == "23514" && pgErr.Constraint == "courses_code_check" {
+				return 0, fmt.Errorf("a sigla do curso deve ter apenas letras e números")
+			}
+		}
 		return 0, fmt.Errorf("erro ao criar curso: %w", err)
 	}
 
 	return id, nil
 }
+
+func (r *CourseRepository) GetByID(id int) (*models.Course, error) {
+	query := `
+		SELECT c.id, c.name, c.code, c.total_credits_required, c.duration_semesters, c.max_duration_semesters,
+		c.requires_enrollment_approval, c.min_credits_per_semester, c.max_credits_per_semester, c.created_at,
+		cc.teacher_id, t.name
+		FROM courses c ` + currentCoordinatorJoin + `
+		WHERE c.id = $1
+	`
+
+	var c models.Course
+	err := r.DB.QueryRow(query, id).Scan(
+		&c.ID, &c.Name, &c.Code, &c.TotalCreditsRequired, &c.DurationSemesters, &c.MaxDurationSemesters,
+		&c.RequiresEnrollmentApproval, &c.MinCreditsPerSemester, &c.MaxCreditsPerSemester, &c.CreatedAt,
+		&c.CoordinatorID, &c.CoordinatorName,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar curso: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *CourseRepository) GetCurriculum(courseID int) ([]models.CurriculumItem, error) {
+	query := `
+		SELECT cd.course_id, d.id, d.name, d.code, d.credits, cd.suggested_semester, cd.mandatory
+		FROM course_disciplines cd
+		JOIN disciplines d ON d.id = cd.discipline_id
+		WHERE cd.course_id = $1
+		ORDER BY cd.suggested_semester NULLS LAST, d.name
+	`
+
+	rows, err := r.DB.Query(query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar matriz curricular: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.CurriculumItem
+	for rows.Next() {
+		var c models.CurriculumItem
+		err := rows.Scan(&c.CourseID, &c.DisciplineID, &c.DisciplineName, &c.DisciplineCode, &c.Credits, &c.SuggestedSemester, &c.Mandatory)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear disciplina da matriz: %w", err)
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre a matriz curricular: %w", err)
+	}
+	return list, nil
+}
+
+// SaveCurriculumItem inclui a disciplina na matriz do curso ou atualiza seus dados
+func (r *CourseRepository) SaveCurriculumItem(c *models.CurriculumItem) error {
+	query := `
+		INSERT INTO course_disciplines (course_id, discipline_id, suggested_semester, mandatory)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (course_id, discipline_id)
+		DO UPDATE SET suggested_semester = EXCLUDED.suggested_semester, mandatory = EXCLUDED.mandatory
+	`
+
+	_, err := r.DB.Exec(query, c.CourseID, c.DisciplineID, c.SuggestedSemester, c.Mandatory)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return fmt.Errorf("curso ou disciplina inexistente")
+		}
+		return fmt.Errorf("erro ao salvar disciplina na matriz: %w", err)
+	}
+	return nil
+}
+
+func (r *CourseRepository) RemoveCurriculumItem(courseID, disciplineID int) error {
+	result, err := r.DB.Exec(
+		`DELETE FROM course_disciplines WHERE course_id = $1 AND discipline_id = $2`,
+		courseID, disciplineID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao remover disciplina da matriz: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("disciplina %d não faz parte da matriz do curso", disciplineID)
+	}
+	return nil
+}
+
+// GetCoordinators retorna o histórico de coordenações do curso, do mandato mais recente ao mais antigo
+func (r *CourseRepository) GetCoordinators(courseID int) ([]models.CourseCoordinator, error) {
+	list, err := listAppointments(r.DB, "course_coordinators", "course_id", courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinators := make([]models.CourseCoordinator, len(list))
+	for i, a := range list {
+		coordinators[i] = models.CourseCoordinator{CourseID: courseID, Appointment: a}
+	}
+	return coordinators, nil
+}
+
+// AppointCoordinator nomeia o coordenador do curso, encerrando a coordenação anterior
+func (r *CourseRepository) AppointCoordinator(c *models.CourseCoordinator) (int, error) {
+	return appoint(r.DB, "course_coordinators", "course_id", c.CourseID, &c.Appointment)
+}
+
+// CoordinatedBy retorna os cursos coordenados hoje pelo professor
+func (r *CourseRepository) CoordinatedBy(teacherID int) ([]int, error) {
+	return appointedUnits(r.DB, "course_coordinators", "course_id", teacherID)
+}
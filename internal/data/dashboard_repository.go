@@ -6,12 +6,13 @@ import (
 )
 
 type DashboardStats struct {
-	Students    int `json:"students"`
-	Teachers    int `json:"teachers"`
-	Courses     int `json:"courses"`
-	Disciplines int `json:"disciplines"`
-	Departments int `json:"departments"`
-	Semesters   int `json:"semesters"`
+	Students         int            `json:"students"`
+	StudentsByStatus map[string]int `json:"students_by_status"`
+	Teachers         int            `json:"teachers"`
+	Courses          int            `json:"courses"`
+	Disciplines      int            `json:"disciplines"`
+	Departments      int            `json:"departments"`
+	Semesters        int            `json:"semesters"`
 }
 
 type DashboardRepository struct {
@@ -21,11 +22,28 @@ type DashboardRepository struct {
 func (r *DashboardRepository) GetStats() (*DashboardStats, error) {
 	stats := &DashboardStats{}
 
-	err := r.DB.QueryRow("SELECT COUNT(*) FROM students WHERE active = true").Scan(&stats.Students)
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM students WHERE status = 'enrolled'").Scan(&stats.Students)
 	if err != nil {
 		log.Println("Erro ao ao obter contagem de estudantes:", err)
 	}
 
+	stats.StudentsByStatus = map[string]int{}
+	rows, err := r.DB.Query("SELECT status, COUNT(*) FROM students GROUP BY status")
+	if err != nil {
+		log.Println("Erro ao obter contagem de estudantes por situação:", err)
+	} else {
+		for rows.Next() {
+			var status string
+			var count int
+			if err := rows.Scan(&status, &count); err != nil {
+				log.Println("Erro ao obter contagem de estudantes por situação:", err)
+				break
+			}
+			stats.StudentsByStatus[status] = count
+		}
+		rows.Close()
+	}
+
 	err = r.DB.QueryRow("SELECT COUNT(*) FROM teachers WHERE active = true").Scan(&stats.Teachers)
 	if err != nil {
 		log.Println("Erro ao ao obter contagem de professores:", err)
@@ -12,9 +12,12 @@ type DepartmentRepository struct {
 
 func (r *DepartmentRepository) GetAll() ([]models.Department, error) {
 	query := `
-		SELECT d.id, d.name, d.abbreviation, d.created_at
+		SELECT d.id, d.name, d.abbreviation, h.teacher_id, t.name, d.created_at
 		FROM departments d
-		ORDER BY name ASC
+		LEFT JOIN department_heads h ON h.department_id = d.id
+		  AND h.start_date <= CURRENT_DATE AND (h.end_date IS NULL OR h.end_date >= CURRENT_DATE)
+		LEFT JOIN teachers t ON t.id = h.teacher_id
+		ORDER BY d.name ASC
 	`
 
 	rows, err := r.DB.Query(query)
@@ -28,7 +31,7 @@ func (r *DepartmentRepository) GetAll() ([]models.Department, error) {
 	for rows.Next() {
 		var d models.Department
 		err := rows.Scan(
-			&d.ID, &d.Name, &d.Abbreviation, &d.CreatedAt,
+			&d.ID, &d.Name, &d.Abbreviation, &d.HeadID, &d.HeadName, &d.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao escanear departamento: %w", err)
@@ -64,3 +67,36 @@ func (r *DepartmentRepository) Create(d *models.Department) (int, error) {
 
 	return id, nil
 }
+
+func (r *DepartmentRepository) Exists(id int) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM departments WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("erro ao buscar departamento: %w", err)
+	}
+	return exists, nil
+}
+
+// GetHeads retorna o histórico de chefias do departamento, do mandato mais recente ao mais antigo
+func (r *DepartmentRepository) GetHeads(departmentID int) ([]models.DepartmentHead, error) {
+	list, err := listAppointments(r.DB, "department_heads", "department_id", departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make([]models.DepartmentHead, len(list))
+	for i, a := range list {
+		heads[i] = models.DepartmentHead{DepartmentID: departmentID, Appointment: a}
+	}
+	return heads, nil
+}
+
+// AppointHead nomeia o chefe do departamento, encerrando a chefia anterior
+func (r *DepartmentRepository) AppointHead(h *models.DepartmentHead) (int, error) {
+	return appoint(r.DB, "department_heads", "department_id", h.DepartmentID, &h.Appointment)
+}
+
+// HeadedBy retorna os departamentos chefiados hoje pelo professor
+func (r *DepartmentRepository) HeadedBy(teacherID int) ([]int, error) {
+	return appointedUnits(r.DB, "department_heads", "department_id", teacherID)
+}
@@ -0,0 +1,178 @@
+package data
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type DiplomaRepository struct {
+	DB *sql.DB
+}
+
+// Issue registra o diploma em uma transação: reserva o número de registro do ano da colação, gera o
+// XML com build (que recebe esse número), grava o XML no livro de registro e passa o aluno matriculado
+// para formado na data da colação. Se o código de validação sorteado já pertencer a outro diploma,
+// retorna "código de verificação já utilizado".
+func (r *DiplomaRepository) Issue(d *models.Diploma, build func(registryNumber string) ([]byte, error)) ([]byte, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM students WHERE id = $1 FOR UPDATE`, d.StudentID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("nenhum aluno encontrado com o ID %d", d.StudentID)
+		}
+		return nil, fmt.Errorf("erro ao buscar situação do aluno: %w", err)
+	}
+	if status != models.StudentEnrolled && status != models.StudentGraduated {
+		return nil, fmt.Errorf("o aluno não está matriculado nem formado")
+	}
+
+	var issued bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM diplomas WHERE student_id = $1 AND course_id = $2)`,
+		d.StudentID, d.CourseID).Scan(&issued)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar diplomas do aluno: %w", err)
+	}
+	if issued {
+		return nil, fmt.Errorf("diploma já emitido para o aluno neste curso")
+	}
+
+	// A linha da sequência fica bloqueada até o fim da transação, então os números saem em ordem e sem lacunas
+	year := d.GraduationDate.Year()
+	var seq int
+	err = tx.QueryRow(`
+		INSERT INTO registration_sequences (scope, last_value) VALUES ($1, 1)
+		ON CONFLICT (scope) DO UPDATE SET last_value = registration_sequences.last_value + 1
+		RETURNING last_value
+	`, fmt.Sprintf("diploma:%d", year)).Scan(&seq)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar número de registro: %w", err)
+	}
+	d.RegistryNumber = fmt.Sprintf("%d/%06d", year, seq)
+
+	xml, err := build(d.RegistryNumber)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(xml)
+	d.XMLHash = hex.EncodeToString(hash[:])
+
+	err = tx.QueryRow(`
+		INSERT INTO diplomas (
+			code, registry_number, student_id, course_id, graduation_date, xml, xml_hash, signature_key_id, issued_by, issued_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, d.Code, d.RegistryNumber, d.StudentID, d.CourseID, d.GraduationDate, string(xml), d.XMLHash,
+		d.SignatureKeyID, d.IssuedBy, d.IssuedAt).Scan(&d.ID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			switch pgErr.Constraint {
+			case "diplomas_code_key":
+				return nil, fmt.Errorf("código de verificação já utilizado")
+			case "diplomas_student_id_course_id_key":
+				return nil, fmt.Errorf("diploma já emitido para o aluno neste curso")
+			}
+		}
+		return nil, fmt.Errorf("erro ao registrar diploma: %w", err)
+	}
+
+	if status == models.StudentEnrolled {
+		err = changeStudentStatus(tx, &models.StudentStatusChange{
+			StudentID:     d.StudentID,
+			ToStatus:      models.StudentGraduated,
+			Reason:        "Colação de grau, diploma " + d.RegistryNumber,
+			ChangedBy:     d.IssuedBy,
+			EffectiveDate: d.GraduationDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return xml, nil
+}
+
+const diplomaSelect = `
+	SELECT d.id, d.code, d.registry_number, d.student_id, s.name, d.course_id, c.name, d.graduation_date,
+	       d.xml_hash, d.signature_key_id, d.issued_by, d.issued_at
+	FROM diplomas d
+	JOIN students s ON s.id = d.student_id
+	JOIN courses c ON c.id = d.course_id
+`
+
+func scanDiploma(row interface{ Scan(...any) error }) (*models.Diploma, error) {
+	var d models.Diploma
+	err := row.Scan(
+		&d.ID, &d.Code, &d.RegistryNumber, &d.StudentID, &d.StudentName, &d.CourseID, &d.CourseName,
+		&d.GraduationDate, &d.XMLHash, &d.SignatureKeyID, &d.IssuedBy, &d.IssuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *DiplomaRepository) GetByCode(code string) (*models.Diploma, error) {
+	d, err := scanDiploma(r.DB.QueryRow(diplomaSelect+` WHERE d.code = $1`, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar diploma: %w", err)
+	}
+	return d, nil
+}
+
+// GetXML retorna o XML assinado do diploma, ou nil se o código não existir
+func (r *DiplomaRepository) GetXML(code string) ([]byte, error) {
+	var xml string
+	err := r.DB.QueryRow(`SELECT xml FROM diplomas WHERE code = $1`, code).Scan(&xml)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar diploma: %w", err)
+	}
+	return []byte(xml), nil
+}
+
+// GetAll lista o livro de registro; courseID, studentID e year zerados não filtram
+func (r *DiplomaRepository) GetAll(courseID, studentID, year int) ([]models.Diploma, error) {
+	rows, err := r.DB.Query(diplomaSelect+`
+		WHERE ($1 = 0 OR d.course_id = $1) AND ($2 = 0 OR d.student_id = $2)
+		  AND ($3 = 0 OR EXTRACT(YEAR FROM d.graduation_date) = $3)
+		ORDER BY d.registry_number ASC
+	`, courseID, studentID, year)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar diplomas: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.Diploma{}
+	for rows.Next() {
+		d, err := scanDiploma(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear diploma: %w", err)
+		}
+		list = append(list, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os diplomas: %w", err)
+	}
+	return list, nil
+}
@@ -13,6 +13,16 @@ type DisciplineRepository struct {
 }
 
 func (r *DisciplineRepository) GetAll() ([]models.Discipline, error) {
+	var list []models.Discipline
+	err := r.Each(func(d models.Discipline) error {
+		list = append(list, d)
+		return nil
+	})
+	return list, err
+}
+
+// Each percorre as disciplinas uma por vez, na ordem do GetAll (usado pela exportação)
+func (r *DisciplineRepository) Each(fn func(models.Discipline) error) error {
 	query := `
 		SELECT d.id, d.name, d.code, d.credits, d.workload_hours, d.description,
 		       d.department_id, dep.name AS department_name,
@@ -24,12 +34,10 @@ func (r *DisciplineRepository) GetAll() ([]models.Discipline, error) {
 
 	rows, err := r.DB.Query(query)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar disciplinas: %w", err)
+		return fmt.Errorf("erro ao buscar disciplinas: %w", err)
 	}
 	defer rows.Close()
 
-	var list []models.Discipline
-
 	for rows.Next() {
 		var d models.Discipline
 		var deptName sql.NullString
@@ -39,7 +47,7 @@ func (r *DisciplineRepository) GetAll() ([]models.Discipline, error) {
 			&d.DepartmentID, &deptName, &d.CreatedAt, &d.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear disciplina: %w", err)
+			return fmt.Errorf("erro ao escanear disciplina: %w", err)
 		}
 
 		if deptName.Valid {
@@ -47,9 +55,11 @@ func (r *DisciplineRepository) GetAll() ([]models.Discipline, error) {
 		} else {
 			d.DepartmentName = "Sem Departamento"
 		}
-		list = append(list, d)
+		if err := fn(d); err != nil {
+			return err
+		}
 	}
-	return list, nil
+	return rows.Err()
 }
 
 func (r *DisciplineRepository) Create(d *models.Discipline) (int, error) {
@@ -155,3 +165,107 @@ func (r *DisciplineRepository) Delete(id int) error {
 	}
 	return nil
 }
+
+func (r *DisciplineRepository) GetPrerequisites(disciplineID int) ([]models.Discipline, error) {
+	query := `
+		SELECT d.id, d.name, d.code, d.credits, d.workload_hours
+		FROM discipline_prerequisites p
+		JOIN disciplines d ON d.id = p.prerequisite_id
+		WHERE p.discipline_id = $1
+		ORDER BY d.name
+	`
+
+	rows, err := r.DB.Query(query, disciplineID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pré-requisitos: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.Discipline
+	for rows.Next() {
+		var d models.Discipline
+		if err := rows.Scan(&d.ID, &d.Name, &d.Code, &d.Credits, &d.WorkloadHours); err != nil {
+			return nil, fmt.Errorf("erro ao escanear pré-requisito: %w", err)
+		}
+		list = append(list, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os pré-requisitos: %w", err)
+	}
+	return list, nil
+}
+
+// PrerequisiteMap retorna os pré-requisitos de todas as disciplinas (disciplina -> pré-requisitos)
+func (r *DisciplineRepository) PrerequisiteMap() (map[int][]int, error) {
+	rows, err := r.DB.Query(`SELECT discipline_id, prerequisite_id FROM discipline_prerequisites`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pré-requisitos: %w", err)
+	}
+	defer rows.Close()
+
+	prereqs := map[int][]int{}
+	for rows.Next() {
+		var disciplineID, prerequisiteID int
+		if err := rows.Scan(&disciplineID, &prerequisiteID); err != nil {
+			return nil, fmt.Errorf("erro ao escanear pré-requisito: %w", err)
+		}
+		prereqs[disciplineID] = append(prereqs[disciplineID], prerequisiteID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os pré-requisitos: %w", err)
+	}
+	return prereqs, nil
+}
+
+// AddPrerequisite cadastra um pré-requisito, recusando ciclos (A exige B que exige A)
+func (r *DisciplineRepository) AddPrerequisite(disciplineID, prerequisiteID int) error {
+	var cycle bool
+	err := r.DB.QueryRow(`
+		WITH RECURSIVE chain(id) AS (
+			SELECT prerequisite_id FROM discipline_prerequisites WHERE discipline_id = $1
+			UNION
+			SELECT p.prerequisite_id FROM discipline_prerequisites p JOIN chain c ON p.discipline_id = c.id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)
+	`, prerequisiteID, disciplineID).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar pré-requisitos: %w", err)
+	}
+	if cycle {
+		return fmt.Errorf("pré-requisito criaria uma dependência circular")
+	}
+
+	_, err = r.DB.Exec(`
+		INSERT INTO discipline_prerequisites (discipline_id, prerequisite_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, disciplineID, prerequisiteID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return fmt.Errorf("disciplina inexistente")
+		}
+		return fmt.Errorf("erro ao cadastrar pré-requisito: %w", err)
+	}
+	return nil
+}
+
+func (r *DisciplineRepository) RemovePrerequisite(disciplineID, prerequisiteID int) error {
+	result, err := r.DB.Exec(
+		`DELETE FROM discipline_prerequisites WHERE discipline_id = $1 AND prerequisite_id = $2`,
+		disciplineID, prerequisiteID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao remover pré-requisito: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("pré-requisito não encontrado")
+	}
+	return nil
+}
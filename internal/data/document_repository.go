@@ -0,0 +1,134 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type DocumentRepository struct {
+	DB *sql.DB
+}
+
+// Disciplines lista as disciplinas em que o aluno está matriculado no semestre, para as declarações
+func (r *DocumentRepository) Disciplines(studentID, semesterID int) ([]models.DocumentDiscipline, error) {
+	rows, err := r.DB.Query(`
+		SELECT d.code, d.name, o.schedule, COALESCE(o.planned_hours, d.workload_hours),
+		       COALESCE(r.absences, 0), r.frequency, r.status
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		JOIN disciplines d ON d.id = o.discipline_id
+		WHERE r.student_id = $1 AND o.semester_id = $2 AND r.status <> 'dropped'
+		ORDER BY d.name ASC
+	`, studentID, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar disciplinas do aluno: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.DocumentDiscipline
+	for rows.Next() {
+		var d models.DocumentDiscipline
+		if err := rows.Scan(&d.Code, &d.Name, &d.Schedule, &d.WorkloadHours, &d.Absences, &d.Frequency, &d.Status); err != nil {
+			return nil, fmt.Errorf("erro ao escanear disciplina: %w", err)
+		}
+		list = append(list, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as disciplinas: %w", err)
+	}
+	return list, nil
+}
+
+// Create registra o documento emitido junto com o PDF entregue. Se o código de verificação sorteado
+// já pertencer a outro documento, retorna "código de verificação já utilizado".
+func (r *DocumentRepository) Create(doc *models.IssuedDocument, pdf []byte) error {
+	err := r.DB.QueryRow(`
+		INSERT INTO issued_documents (
+			code, kind, student_id, semester_id, content, content_hash, pdf, signature_key_id, signature, issued_by, issued_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, doc.Code, doc.Kind, doc.StudentID, doc.SemesterID, []byte(doc.Content), doc.ContentHash, pdf,
+		doc.SignatureKeyID, doc.Signature, doc.IssuedBy, doc.IssuedAt).Scan(&doc.ID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" && pgErr.Constraint == "issued_documents_code_key" {
+			return fmt.Errorf("código de verificação já utilizado")
+		}
+		return fmt.Errorf("erro ao registrar documento emitido: %w", err)
+	}
+	return nil
+}
+
+const documentSelect = `
+	SELECT i.id, i.code, i.kind, i.student_id, s.name, i.semester_id, i.content, i.content_hash,
+	       i.signature_key_id, i.signature, i.issued_by, i.issued_at
+	FROM issued_documents i
+	JOIN students s ON s.id = i.student_id
+`
+
+func scanDocument(row interface{ Scan(...any) error }) (*models.IssuedDocument, error) {
+	var doc models.IssuedDocument
+	var content []byte
+	err := row.Scan(
+		&doc.ID, &doc.Code, &doc.Kind, &doc.StudentID, &doc.StudentName, &doc.SemesterID, &content,
+		&doc.ContentHash, &doc.SignatureKeyID, &doc.Signature, &doc.IssuedBy, &doc.IssuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	doc.Content = content
+	return &doc, nil
+}
+
+// GetByCode busca o documento pelo código de verificação
+func (r *DocumentRepository) GetByCode(code string) (*models.IssuedDocument, error) {
+	doc, err := scanDocument(r.DB.QueryRow(documentSelect+` WHERE i.code = $1`, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+	return doc, nil
+}
+
+// GetPDF retorna o PDF entregue na emissão do documento
+func (r *DocumentRepository) GetPDF(code string) ([]byte, error) {
+	var pdf []byte
+	err := r.DB.QueryRow(`SELECT pdf FROM issued_documents WHERE code = $1`, code).Scan(&pdf)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar PDF do documento: %w", err)
+	}
+	return pdf, nil
+}
+
+// GetByStudent lista os documentos emitidos para o aluno, do mais recente para o mais antigo
+func (r *DocumentRepository) GetByStudent(studentID int) ([]models.IssuedDocument, error) {
+	rows, err := r.DB.Query(documentSelect+` WHERE i.student_id = $1 ORDER BY i.issued_at DESC, i.id DESC`, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos emitidos: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.IssuedDocument
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear documento: %w", err)
+		}
+		doc.Content = nil
+		list = append(list, *doc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os documentos: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,246 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type EnrollmentRequestRepository struct {
+	DB *sql.DB
+}
+
+const enrollmentRequestSelect = `
+	SELECT er.id, er.student_id, s.name, er.offer_id, o.semester_id, d.id, d.name, d.credits,
+	       er.status, er.requested_at, er.reviewed_by, er.reviewed_at, er.rejection_reason,
+	       er.suggested_offer_id, er.registration_id
+	FROM enrollment_requests er
+	JOIN students s ON s.id = er.student_id
+	JOIN discipline_offers o ON o.id = er.offer_id
+	JOIN disciplines d ON d.id = o.discipline_id
+`
+
+func (r *EnrollmentRequestRepository) list(query string, args ...any) ([]models.EnrollmentRequest, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pedidos de matrícula: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.EnrollmentRequest
+	for rows.Next() {
+		var e models.EnrollmentRequest
+		err := rows.Scan(
+			&e.ID, &e.StudentID, &e.StudentName, &e.OfferID, &e.SemesterID, &e.DisciplineID, &e.DisciplineName, &e.Credits,
+			&e.Status, &e.RequestedAt, &e.ReviewedBy, &e.ReviewedAt, &e.RejectionReason,
+			&e.SuggestedOfferID, &e.RegistrationID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear pedido de matrícula: %w", err)
+		}
+		list = append(list, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os pedidos de matrícula: %w", err)
+	}
+	return list, nil
+}
+
+// GetByStudent lista os pedidos do aluno; semesterID 0 lista todos os semestres
+func (r *EnrollmentRequestRepository) GetByStudent(studentID, semesterID int) ([]models.EnrollmentRequest, error) {
+	return r.list(enrollmentRequestSelect+`
+		WHERE er.student_id = $1 AND ($2 = 0 OR o.semester_id = $2)
+		ORDER BY er.requested_at DESC
+	`, studentID, semesterID)
+}
+
+// GetAll lista os pedidos para a coordenação, agrupados por aluno; status vazio lista todos e
+// courseIDs nil não filtra por curso (com courseIDs, só os dos alunos desses cursos)
+func (r *EnrollmentRequestRepository) GetAll(status string, courseIDs []int) ([]models.EnrollmentRequest, error) {
+	return r.list(enrollmentRequestSelect+`
+		WHERE ($1 = '' OR er.status::text = $1) AND ($2::int[] IS NULL OR s.course_id = ANY($2))
+		ORDER BY s.name, er.requested_at
+	`, status, pq.Array(courseIDs))
+}
+
+func (r *EnrollmentRequestRepository) Create(studentID int, offerIDs []int) ([]int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := createEnrollmentRequests(tx, studentID, offerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// createEnrollmentRequests também é usado pela confirmação de rematrícula
+func createEnrollmentRequests(tx *sql.Tx, studentID int, offerIDs []int) ([]int, error) {
+	var ids []int
+	for _, offerID := range offerIDs {
+		var id int
+		err := tx.QueryRow(`
+			INSERT INTO enrollment_requests (student_id, offer_id)
+			VALUES ($1, $2)
+			RETURNING id
+		`, studentID, offerID).Scan(&id)
+		if err != nil {
+			if pgErr, ok := err.(*pq.Error); ok {
+				if pgErr.Code == "23505" {
+					return nil, fmt.Errorf("já existe pedido de matrícula para a oferta %d", offerID)
+				}
+				if pgErr.Code == "23503" {
+					return nil, fmt.Errorf("oferta %d não encontrada", offerID)
+				}
+			}
+			return nil, fmt.Errorf("erro ao criar pedido de matrícula: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Review analisa de uma vez todos os pedidos pendentes do aluno no semestre. Os aprovados viram
+// matrículas; o total de créditos do aluno no semestre precisa respeitar os limites do curso.
+func (r *EnrollmentRequestRepository) Review(studentID, semesterID int, decisions []models.EnrollmentDecision, reviewedBy string, course *models.Course) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT er.id, er.offer_id, d.credits
+		FROM enrollment_requests er
+		JOIN discipline_offers o ON o.id = er.offer_id
+		JOIN disciplines d ON d.id = o.discipline_id
+		WHERE er.student_id = $1 AND o.semester_id = $2 AND er.status = 'pending'
+		FOR UPDATE OF er
+	`, studentID, semesterID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar pedidos pendentes: %w", err)
+	}
+
+	type pendingRequest struct{ offerID, credits int }
+	pending := map[int]pendingRequest{}
+	for rows.Next() {
+		var id int
+		var p pendingRequest
+		if err := rows.Scan(&id, &p.offerID, &p.credits); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao escanear pedido pendente: %w", err)
+		}
+		pending[id] = p
+	}
+	rows.Close()
+
+	if len(pending) == 0 {
+		return fmt.Errorf("pedido de matrícula: nenhum pedido pendente para o aluno neste semestre")
+	}
+
+	approvedCredits := 0
+	seen := map[int]bool{}
+	for _, d := range decisions {
+		p, ok := pending[d.RequestID]
+		if !ok {
+			return fmt.Errorf("pedido de matrícula: pedido %d não está pendente para este aluno e semestre", d.RequestID)
+		}
+		if seen[d.RequestID] {
+			return fmt.Errorf("pedido de matrícula: pedido %d informado mais de uma vez", d.RequestID)
+		}
+		seen[d.RequestID] = true
+		if d.Approve {
+			approvedCredits += p.credits
+		}
+	}
+	if len(seen) != len(pending) {
+		return fmt.Errorf("pedido de matrícula: todos os pedidos pendentes do semestre devem ser analisados juntos")
+	}
+
+	var currentCredits int
+	err = tx.QueryRow(`
+		SELECT COALESCE(SUM(d.credits), 0)
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		JOIN disciplines d ON d.id = o.discipline_id
+		WHERE r.student_id = $1 AND o.semester_id = $2 AND r.status <> 'dropped'
+	`, studentID, semesterID).Scan(&currentCredits)
+	if err != nil {
+		return fmt.Errorf("erro ao somar créditos do semestre: %w", err)
+	}
+
+	if approvedCredits > 0 {
+		total := currentCredits + approvedCredits
+		if course.MaxCreditsPerSemester != nil && total > *course.MaxCreditsPerSemester {
+			return fmt.Errorf("limite de créditos: %d créditos excedem o máximo de %d por semestre", total, *course.MaxCreditsPerSemester)
+		}
+		if course.MinCreditsPerSemester != nil && total < *course.MinCreditsPerSemester {
+			return fmt.Errorf("limite de créditos: %d créditos ficam abaixo do mínimo de %d por semestre", total, *course.MinCreditsPerSemester)
+		}
+	}
+
+	var approvedOffers []int
+	for _, d := range decisions {
+		if d.Approve {
+			approvedOffers = append(approvedOffers, pending[d.RequestID].offerID)
+		}
+	}
+	if err := reserveSeats(tx, studentID, approvedOffers); err != nil {
+		return err
+	}
+
+	for _, d := range decisions {
+		if !d.Approve {
+			if d.SuggestedOfferID != nil {
+				var sem int
+				err := tx.QueryRow(`SELECT semester_id FROM discipline_offers WHERE id = $1`, *d.SuggestedOfferID).Scan(&sem)
+				if err != nil || sem != semesterID {
+					return fmt.Errorf("pedido de matrícula: oferta sugerida %d não pertence ao semestre", *d.SuggestedOfferID)
+				}
+			}
+
+			_, err := tx.Exec(`
+				UPDATE enrollment_requests
+				SET status = 'rejected', reviewed_by = $1, reviewed_at = CURRENT_TIMESTAMP,
+				    rejection_reason = $2, suggested_offer_id = $3
+				WHERE id = $4
+			`, reviewedBy, d.Reason, d.SuggestedOfferID, d.RequestID)
+			if err != nil {
+				return fmt.Errorf("erro ao rejeitar pedido %d: %w", d.RequestID, err)
+			}
+			continue
+		}
+
+		var registrationID int
+		err := tx.QueryRow(`
+			INSERT INTO registrations (student_id, offer_id)
+			VALUES ($1, $2)
+			ON CONFLICT (student_id, offer_id) DO UPDATE SET status = 'in_progress', updated_at = CURRENT_TIMESTAMP
+			RETURNING id
+		`, studentID, pending[d.RequestID].offerID).Scan(&registrationID)
+		if err != nil {
+			return fmt.Errorf("erro ao matricular pedido %d: %w", d.RequestID, err)
+		}
+
+		_, err = tx.Exec(`
+			UPDATE enrollment_requests
+			SET status = 'approved', reviewed_by = $1, reviewed_at = CURRENT_TIMESTAMP, registration_id = $2
+			WHERE id = $3
+		`, reviewedBy, registrationID, d.RequestID)
+		if err != nil {
+			return fmt.Errorf("erro ao aprovar pedido %d: %w", d.RequestID, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type EquivalenceRepository struct {
+	DB *sql.DB
+}
+
+func (r *EquivalenceRepository) GetAll() ([]models.Equivalence, error) {
+	query := `
+		SELECT e.id, e.target_discipline_id, d.name, e.notes, e.created_at,
+		       ARRAY(SELECT s.discipline_id FROM discipline_equivalence_sources s WHERE s.equivalence_id = e.id ORDER BY s.discipline_id)
+		FROM discipline_equivalences e
+		JOIN disciplines d ON d.id = e.target_discipline_id
+		ORDER BY d.name, e.id
+	`
+
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar equivalências: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.Equivalence
+	for rows.Next() {
+		var e models.Equivalence
+		var sources pq.Int64Array
+		if err := rows.Scan(&e.ID, &e.TargetDisciplineID, &e.TargetDisciplineName, &e.Notes, &e.CreatedAt, &sources); err != nil {
+			return nil, fmt.Errorf("erro ao escanear equivalência: %w", err)
+		}
+		for _, id := range sources {
+			e.SourceDisciplineIDs = append(e.SourceDisciplineIDs, int(id))
+		}
+		list = append(list, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as equivalências: %w", err)
+	}
+	return list, nil
+}
+
+func (r *EquivalenceRepository) Create(e *models.Equivalence) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(
+		`INSERT INTO discipline_equivalences (target_discipline_id, notes) VALUES ($1, $2) RETURNING id`,
+		e.TargetDisciplineID, e.Notes,
+	).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return 0, fmt.Errorf("disciplina inexistente")
+		}
+		return 0, fmt.Errorf("erro ao criar equivalência: %w", err)
+	}
+
+	for _, source := range e.SourceDisciplineIDs {
+		_, err := tx.Exec(
+			`INSERT INTO discipline_equivalence_sources (equivalence_id, discipline_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			id, source,
+		)
+		if err != nil {
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+				return 0, fmt.Errorf("disciplina inexistente")
+			}
+			return 0, fmt.Errorf("erro ao gravar disciplinas de origem: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *EquivalenceRepository) Delete(id int) error {
+	result, err := r.DB.Exec(`DELETE FROM discipline_equivalences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar equivalência: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma equivalência encontrada com o ID %d", id)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type ExternalCreditRepository struct {
+	DB *sql.DB
+}
+
+const externalCreditSelect = `
+	SELECT e.id, e.student_id, s.name, e.discipline_id, d.name, d.credits,
+	       e.institution, e.external_code, e.external_name, e.external_workload_hours, e.grade, e.completed_year,
+	       e.status, e.requested_at, e.reviewed_by, e.reviewed_at, e.review_notes
+	FROM external_credits e
+	JOIN students s ON s.id = e.student_id
+	JOIN disciplines d ON d.id = e.discipline_id
+`
+
+func (r *ExternalCreditRepository) list(query string, args ...any) ([]models.ExternalCredit, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aproveitamentos: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.ExternalCredit
+	for rows.Next() {
+		var e models.ExternalCredit
+		err := rows.Scan(
+			&e.ID, &e.StudentID, &e.StudentName, &e.DisciplineID, &e.DisciplineName, &e.Credits,
+			&e.Institution, &e.ExternalCode, &e.ExternalName, &e.ExternalWorkloadHours, &e.Grade, &e.CompletedYear,
+			&e.Status, &e.RequestedAt, &e.ReviewedBy, &e.ReviewedAt, &e.ReviewNotes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear aproveitamento: %w", err)
+		}
+		list = append(list, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os aproveitamentos: %w", err)
+	}
+	return list, nil
+}
+
+func (r *ExternalCreditRepository) GetByStudent(studentID int) ([]models.ExternalCredit, error) {
+	return r.list(externalCreditSelect+` WHERE e.student_id = $1 ORDER BY e.requested_at DESC`, studentID)
+}
+
+// GetAll lista os pedidos de aproveitamento; status vazio lista todos e courseIDs nil não filtra por
+// curso (com courseIDs, só os dos alunos desses cursos)
+func (r *ExternalCreditRepository) GetAll(status string, courseIDs []int) ([]models.ExternalCredit, error) {
+	return r.list(externalCreditSelect+` WHERE ($1 = '' OR e.status::text = $1) AND ($2::int[] IS NULL OR s.course_id = ANY($2))
+		ORDER BY e.requested_at ASC`, status, pq.Array(courseIDs))
+}
+
+func (r *ExternalCreditRepository) GetByID(id int) (*models.ExternalCredit, error) {
+	list, err := r.list(externalCreditSelect+` WHERE e.id = $1`, id)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// Create registra o pedido, recusando disciplinas que o aluno já cumpriu (aprovado ou aproveitado)
+func (r *ExternalCreditRepository) Create(e *models.ExternalCredit) (int, error) {
+	var completed bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM student_completed_disciplines
+			WHERE student_id = $1 AND discipline_id = $2
+		)
+	`, e.StudentID, e.DisciplineID).Scan(&completed)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar disciplinas cumpridas: %w", err)
+	}
+	if completed {
+		return 0, fmt.Errorf("o aluno já cumpriu esta disciplina")
+	}
+
+	query := `
+		INSERT INTO external_credits
+		  (student_id, discipline_id, institution, external_code, external_name, external_workload_hours, grade, completed_year)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var id int
+	err = r.DB.QueryRow(
+		query,
+		e.StudentID, e.DisciplineID, e.Institution, e.ExternalCode, e.ExternalName, e.ExternalWorkloadHours, e.Grade, e.CompletedYear,
+	).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return 0, fmt.Errorf("já existe pedido de aproveitamento para esta disciplina")
+			}
+			if pgErr.Code == "23503" {
+				return 0, fmt.Errorf("disciplina inexistente")
+			}
+		}
+		return 0, fmt.Errorf("erro ao registrar aproveitamento: %w", err)
+	}
+	return id, nil
+}
+
+// Review aprova ou rejeita um pedido pendente
+func (r *ExternalCreditRepository) Review(id int, status, reviewedBy, notes string) error {
+	result, err := r.DB.Exec(`
+		UPDATE external_credits
+		SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP, review_notes = NULLIF($3, '')
+		WHERE id = $4 AND status = 'pending'
+	`, status, reviewedBy, notes, id)
+	if err != nil {
+		return fmt.Errorf("erro ao analisar aproveitamento: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM external_credits WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("erro ao buscar aproveitamento: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("nenhum aproveitamento encontrado com o ID %d", id)
+	}
+	return fmt.Errorf("solicitação já analisada")
+}
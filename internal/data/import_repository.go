@@ -0,0 +1,244 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ImportRepository grava a importação em lote. Cada linha roda num savepoint: a linha que falhar
+// no banco é desfeita e relatada sem derrubar as demais, e tudo é confirmado numa única transação.
+type ImportRepository struct {
+	DB        *sql.DB
+	Numbering academic.RegistrationFormat
+}
+
+// rowImporter grava uma linha e diz se ela foi inserida (false = atualizada)
+type rowImporter func(tx *sql.Tx, i int) (bool, error)
+
+// run executa setup (carga das referências) e depois cada linha no seu savepoint
+func (r *ImportRepository) run(lines []int, dryRun bool, setup func(tx *sql.Tx) error, importRow rowImporter) (*models.ImportResult, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setup(tx); err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{DryRun: dryRun, Errors: []models.ImportRowError{}}
+	for i, line := range lines {
+		if _, err := tx.Exec(`SAVEPOINT import_row`); err != nil {
+			return nil, fmt.Errorf("erro ao importar linha %d: %w", line, err)
+		}
+
+		inserted, err := importRow(tx, i)
+		if err != nil {
+			if _, rbErr := tx.Exec(`ROLLBACK TO SAVEPOINT import_row`); rbErr != nil {
+				return nil, fmt.Errorf("erro ao importar linha %d: %w", line, rbErr)
+			}
+			result.Errors = append(result.Errors, importRowError(line, err))
+			continue
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT import_row`); err != nil {
+			return nil, fmt.Errorf("erro ao importar linha %d: %w", line, err)
+		}
+
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importRowError traduz os erros do banco para o relatório da linha
+func importRowError(line int, err error) models.ImportRowError {
+	e := models.ImportRowError{Line: line, Message: err.Error()}
+
+	var pgErr *pq.Error
+	if !errors.As(err, &pgErr) {
+		return e
+	}
+	switch pgErr.Code {
+	case "23505":
+		switch {
+		case strings.HasSuffix(pgErr.Constraint, "_email_key"):
+			e.Field, e.Message = "email", "email já cadastrado para outra pessoa"
+		case strings.HasSuffix(pgErr.Constraint, "_registration_number_key"):
+			e.Field, e.Message = "registration_number", "matrícula já cadastrada para outro aluno"
+		case pgErr.Constraint == "disciplines_name_key":
+			e.Field, e.Message = "name", "nome já usado por outra disciplina"
+		default:
+			e.Message = "valor já cadastrado: " + pgErr.Detail
+		}
+	case "23514":
+		e.Message = "valor recusado pelo banco (" + pgErr.Constraint + ")"
+	}
+	return e
+}
+
+// referenceKeys indexa cursos ou departamentos pelo ID, pela sigla e pelo nome (sem diferenciar
+// maiúsculas), como aparecem nas colunas course e department do arquivo
+func referenceKeys(tx *sql.Tx, query string) (map[string]int, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := map[string]int{}
+	for rows.Next() {
+		var id int
+		var code sql.NullString
+		var name string
+		if err := rows.Scan(&id, &code, &name); err != nil {
+			return nil, err
+		}
+		keys[strconv.Itoa(id)] = id
+		keys[strings.ToLower(name)] = id
+		if code.Valid {
+			keys[strings.ToLower(code.String)] = id
+		}
+	}
+	return keys, rows.Err()
+}
+
+func lineNumbers[T any](rows []T, line func(T) int) []int {
+	lines := make([]int, len(rows))
+	for i, row := range rows {
+		lines[i] = line(row)
+	}
+	return lines
+}
+
+// ImportStudents cadastra os alunos novos (com matrícula gerada quando não informada) e atualiza
+// os dados pessoais dos que já existem com o mesmo CPF. O curso de um aluno existente não muda:
+// isso é feito pela transferência de curso.
+func (r *ImportRepository) ImportStudents(list []models.StudentImportRow, dryRun bool, changedBy string) (*models.ImportResult, error) {
+	var courses map[string]int
+	lines := lineNumbers(list, func(row models.StudentImportRow) int { return row.Line })
+
+	setup := func(tx *sql.Tx) (err error) {
+		if courses, err = referenceKeys(tx, `SELECT id, code, name FROM courses`); err != nil {
+			return fmt.Errorf("erro ao buscar cursos: %w", err)
+		}
+		return nil
+	}
+
+	return r.run(lines, dryRun, setup, func(tx *sql.Tx, i int) (bool, error) {
+		s := list[i].Student
+		courseID, ok := courses[strings.ToLower(list[i].Course)]
+		if !ok {
+			return false, fmt.Errorf("curso não encontrado: %s", list[i].Course)
+		}
+		s.CourseID = courseID
+
+		var id, currentCourse int
+		err := tx.QueryRow(`SELECT id, course_id FROM students WHERE cpf = $1 FOR UPDATE`, s.CPF).Scan(&id, &currentCourse)
+		if err == sql.ErrNoRows {
+			_, err = createStudent(tx, r.Numbering, &s, changedBy, "Importação em lote")
+			return true, err
+		}
+		if err != nil {
+			return false, err
+		}
+		if currentCourse != courseID {
+			return false, fmt.Errorf("aluno já cadastrado em outro curso; use a transferência de curso")
+		}
+
+		if err := checkRegistrationNumber(tx, r.Numbering.OrDefault(), s.RegistrationNumber); err != nil {
+			return false, err
+		}
+		_, err = tx.Exec(`
+			UPDATE students
+			SET name = $1, email = COALESCE($2, email), gender = COALESCE(NULLIF($3, ''), gender), date_birth = $4,
+			    registration_number = COALESCE(NULLIF($5, ''), registration_number), updated_at = CURRENT_TIMESTAMP
+			WHERE id = $6
+		`, s.Name, s.Email, s.Gender, s.DateBirth, s.RegistrationNumber, id)
+		return false, err
+	})
+}
+
+// ImportTeachers cadastra os professores novos e atualiza os existentes com o mesmo CPF; mudanças
+// de regime ou horas abrem um novo registro no histórico contratual, como na edição.
+func (r *ImportRepository) ImportTeachers(list []models.TeacherImportRow, dryRun bool, changedBy string) (*models.ImportResult, error) {
+	var departments map[string]int
+	lines := lineNumbers(list, func(row models.TeacherImportRow) int { return row.Line })
+
+	setup := func(tx *sql.Tx) (err error) {
+		if departments, err = referenceKeys(tx, `SELECT id, abbreviation, name FROM departments`); err != nil {
+			return fmt.Errorf("erro ao buscar departamentos: %w", err)
+		}
+		return nil
+	}
+
+	return r.run(lines, dryRun, setup, func(tx *sql.Tx, i int) (bool, error) {
+		t := list[i].Teacher
+		departmentID, ok := departments[strings.ToLower(list[i].Department)]
+		if !ok {
+			return false, fmt.Errorf("departamento não encontrado: %s", list[i].Department)
+		}
+		t.DepartmentID = departmentID
+
+		err := tx.QueryRow(`SELECT id FROM teachers WHERE cpf = $1`, t.CPF).Scan(&t.ID)
+		if err == sql.ErrNoRows {
+			_, err = createTeacher(tx, &t, changedBy)
+			return true, err
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, updateTeacher(tx, &t, changedBy)
+	})
+}
+
+// ImportDisciplines faz o upsert das disciplinas pelo código
+func (r *ImportRepository) ImportDisciplines(list []models.DisciplineImportRow, dryRun bool) (*models.ImportResult, error) {
+	var departments map[string]int
+	lines := lineNumbers(list, func(row models.DisciplineImportRow) int { return row.Line })
+
+	setup := func(tx *sql.Tx) (err error) {
+		if departments, err = referenceKeys(tx, `SELECT id, abbreviation, name FROM departments`); err != nil {
+			return fmt.Errorf("erro ao buscar departamentos: %w", err)
+		}
+		return nil
+	}
+
+	return r.run(lines, dryRun, setup, func(tx *sql.Tx, i int) (bool, error) {
+		d := list[i].Discipline
+		departmentID, ok := departments[strings.ToLower(list[i].Department)]
+		if !ok {
+			return false, fmt.Errorf("departamento não encontrado: %s", list[i].Department)
+		}
+
+		// xmax = 0 só na linha recém-inserida; na atualização pelo ON CONFLICT ele é preenchido
+		var inserted bool
+		err := tx.QueryRow(`
+			INSERT INTO disciplines (name, code, credits, workload_hours, description, department_id)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (code) DO UPDATE
+			SET name = EXCLUDED.name, credits = EXCLUDED.credits, workload_hours = EXCLUDED.workload_hours,
+			    description = EXCLUDED.description, department_id = EXCLUDED.department_id,
+			    updated_at = CURRENT_TIMESTAMP
+			RETURNING xmax = 0
+		`, d.Name, d.Code, d.Credits, d.WorkloadHours, d.Description, departmentID).Scan(&inserted)
+		return inserted, err
+	})
+}
@@ -0,0 +1,404 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type LeaveRepository struct {
+	DB *sql.DB
+}
+
+const leaveSelect = `
+	SELECT l.id, l.student_id, s.name, l.semester_id, l.reason, l.status, l.requested_at,
+	       l.reviewed_by, l.reviewed_at, l.review_notes, l.started_at, l.return_semester_id, l.returned_at
+	FROM leave_requests l
+	JOIN students s ON s.id = l.student_id
+`
+
+func scanLeave(row interface{ Scan(...any) error }) (*models.LeaveRequest, error) {
+	var l models.LeaveRequest
+	err := row.Scan(
+		&l.ID, &l.StudentID, &l.StudentName, &l.SemesterID, &l.Reason, &l.Status, &l.RequestedAt,
+		&l.ReviewedBy, &l.ReviewedAt, &l.ReviewNotes, &l.StartedAt, &l.ReturnSemesterID, &l.ReturnedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *LeaveRepository) list(query string, args ...any) ([]models.LeaveRequest, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar solicitações de trancamento: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.LeaveRequest
+	for rows.Next() {
+		l, err := scanLeave(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear solicitação de trancamento: %w", err)
+		}
+		list = append(list, *l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as solicitações de trancamento: %w", err)
+	}
+	return list, nil
+}
+
+func (r *LeaveRepository) GetByStudent(studentID int) ([]models.LeaveRequest, error) {
+	return r.list(leaveSelect+` WHERE l.student_id = $1 ORDER BY l.requested_at DESC`, studentID)
+}
+
+// GetAll lista as solicitações; status vazio lista todas e courseIDs nil não filtra por curso
+// (com courseIDs, só as dos alunos desses cursos)
+func (r *LeaveRepository) GetAll(status string, courseIDs []int) ([]models.LeaveRequest, error) {
+	return r.list(leaveSelect+` WHERE ($1 = '' OR l.status::text = $1) AND ($2::int[] IS NULL OR s.course_id = ANY($2))
+		ORDER BY l.requested_at ASC`, status, pq.Array(courseIDs))
+}
+
+func (r *LeaveRepository) GetByID(id int) (*models.LeaveRequest, error) {
+	l, err := scanLeave(r.DB.QueryRow(leaveSelect+` WHERE l.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar solicitação de trancamento: %w", err)
+	}
+	return l, nil
+}
+
+func (r *LeaveRepository) Create(l *models.LeaveRequest) (int, error) {
+	query := `
+		INSERT INTO leave_requests (student_id, semester_id, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	var id int
+	err := r.DB.QueryRow(query, l.StudentID, l.SemesterID, l.Reason).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return 0, fmt.Errorf("já existe solicitação de trancamento para este semestre")
+		}
+		return 0, fmt.Errorf("erro ao criar solicitação de trancamento: %w", err)
+	}
+	return id, nil
+}
+
+// Approve aprova o trancamento: cancela as matrículas do semestre que ainda não têm situação final e
+// agenda o retorno para o semestre seguinte. Se o semestre já começou, o aluno passa para "trancado"
+// agora; senão, no início do semestre (ProcessStarts). Retorna quantas matrículas foram canceladas e
+// se o trancamento já começou.
+func (r *LeaveRepository) Approve(id int, reviewedBy, notes string) (int, bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	l, err := lockPendingLeave(tx, id)
+	if err != nil {
+		return 0, false, err
+	}
+
+	dropped, err := dropSemesterRegistrations(tx, l.StudentID, l.SemesterID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var started bool
+	err = tx.QueryRow(`SELECT start_date IS NULL OR start_date <= CURRENT_DATE FROM academic_semesters WHERE id = $1`,
+		l.SemesterID).Scan(&started)
+	if err != nil {
+		return 0, false, fmt.Errorf("erro ao buscar semestre do trancamento: %w", err)
+	}
+	if started {
+		err = startLeave(tx, l, reviewedBy, time.Now())
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE leave_requests
+		SET status = 'approved', reviewed_by = $1, reviewed_at = CURRENT_TIMESTAMP, review_notes = NULLIF($2, ''),
+		    return_semester_id = (`+nextSemesterQuery+`)
+		WHERE id = $4
+	`, reviewedBy, notes, l.SemesterID, id)
+	if err != nil {
+		return 0, false, fmt.Errorf("erro ao aprovar trancamento: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return dropped, started, nil
+}
+
+// dropSemesterRegistrations cancela as matrículas do aluno no semestre que ainda não têm situação
+// final (em andamento ou aguardando exame)
+func dropSemesterRegistrations(tx *sql.Tx, studentID, semesterID int) (int, error) {
+	result, err := tx.Exec(`
+		UPDATE registrations r
+		SET status = 'dropped', updated_at = CURRENT_TIMESTAMP
+		FROM discipline_offers o
+		WHERE o.id = r.offer_id
+		  AND r.student_id = $1
+		  AND o.semester_id = $2
+		  AND r.status IN ('in_progress', 'take_test')
+	`, studentID, semesterID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao cancelar matrículas do semestre: %w", err)
+	}
+	dropped, _ := result.RowsAffected()
+	return int(dropped), nil
+}
+
+// startLeave passa o aluno para "trancado" e marca o início do trancamento
+func startLeave(tx *sql.Tx, l *models.LeaveRequest, changedBy string, day time.Time) error {
+	err := changeStudentStatus(tx, &models.StudentStatusChange{
+		StudentID:     l.StudentID,
+		ToStatus:      models.StudentLeaveOfAbsence,
+		Reason:        "Trancamento aprovado: " + l.Reason,
+		ChangedBy:     changedBy,
+		EffectiveDate: day,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE leave_requests SET started_at = CURRENT_TIMESTAMP WHERE id = $1`, l.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar trancamento: %w", err)
+	}
+	return nil
+}
+
+func (r *LeaveRepository) Reject(id int, reviewedBy, notes string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockPendingLeave(tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE leave_requests
+		SET status = 'rejected', reviewed_by = $1, reviewed_at = CURRENT_TIMESTAMP, review_notes = NULLIF($2, '')
+		WHERE id = $3
+	`, reviewedBy, notes, id)
+	if err != nil {
+		return fmt.Errorf("erro ao rejeitar trancamento: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func lockPendingLeave(tx *sql.Tx, id int) (*models.LeaveRequest, error) {
+	var l models.LeaveRequest
+	err := tx.QueryRow(`
+		SELECT id, student_id, semester_id, reason, status
+		FROM leave_requests
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&l.ID, &l.StudentID, &l.SemesterID, &l.Reason, &l.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("nenhuma solicitação de trancamento encontrada com o ID %d", id)
+		}
+		return nil, fmt.Errorf("erro ao buscar solicitação de trancamento: %w", err)
+	}
+
+	if l.Status != models.RequestPending {
+		return nil, fmt.Errorf("solicitação já analisada")
+	}
+	return &l, nil
+}
+
+// nextSemesterQuery retorna o semestre regular seguinte ao semestre $3 (períodos intensivos não contam)
+const nextSemesterQuery = `
+	SELECT nxt.id
+	FROM academic_semesters cur
+	JOIN academic_semesters nxt ON (nxt.year, nxt.period) > (cur.year, cur.period) AND nxt.kind = 'semester'
+	WHERE cur.id = $3
+	ORDER BY nxt.year, nxt.period
+	LIMIT 1
+`
+
+// ProcessReturns retorna para "matriculado" os alunos cujo semestre de retorno já começou.
+// Trancamentos aprovados antes da criação do semestre seguinte recebem o semestre de retorno aqui.
+func (r *LeaveRepository) ProcessReturns(today time.Time) (int, error) {
+	_, err := r.DB.Exec(`
+		UPDATE leave_requests l
+		SET return_semester_id = (
+			SELECT nxt.id
+			FROM academic_semesters cur
+			JOIN academic_semesters nxt ON (nxt.year, nxt.period) > (cur.year, cur.period) AND nxt.kind = 'semester'
+			WHERE cur.id = l.semester_id
+			ORDER BY nxt.year, nxt.period
+			LIMIT 1
+		)
+		WHERE l.status = 'approved' AND l.returned_at IS NULL AND l.return_semester_id IS NULL
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao definir semestres de retorno: %w", err)
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT l.id, l.student_id
+		FROM leave_requests l
+		JOIN students s ON s.id = l.student_id
+		JOIN academic_semesters sem ON sem.id = l.return_semester_id
+		WHERE l.status = 'approved'
+		  AND l.started_at IS NOT NULL
+		  AND l.returned_at IS NULL
+		  AND s.status = 'leave_of_absence'
+		  AND sem.start_date <= $1
+	`, today)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar retornos de trancamento: %w", err)
+	}
+
+	type pendingReturn struct{ leaveID, studentID int }
+	var returns []pendingReturn
+	for rows.Next() {
+		var p pendingReturn
+		if err := rows.Scan(&p.leaveID, &p.studentID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("erro ao escanear retorno de trancamento: %w", err)
+		}
+		returns = append(returns, p)
+	}
+	rows.Close()
+
+	done := 0
+	for _, p := range returns {
+		tx, err := r.DB.Begin()
+		if err != nil {
+			return done, fmt.Errorf("erro ao iniciar transação: %w", err)
+		}
+
+		err = changeStudentStatus(tx, &models.StudentStatusChange{
+			StudentID:     p.studentID,
+			ToStatus:      models.StudentEnrolled,
+			Reason:        "Retorno automático de trancamento",
+			ChangedBy:     "sistema",
+			EffectiveDate: today,
+		})
+		if err == nil {
+			_, err = tx.Exec(`UPDATE leave_requests SET returned_at = CURRENT_TIMESTAMP WHERE id = $1`, p.leaveID)
+		}
+		if err != nil {
+			tx.Rollback()
+			return done, fmt.Errorf("erro no retorno do trancamento %d: %w", p.leaveID, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return done, err
+		}
+		done++
+	}
+
+	return done, nil
+}
+
+// ProcessStarts inicia os trancamentos aprovados antes do começo do semestre: quando o semestre
+// começa, cancela as matrículas feitas desde a aprovação e passa o aluno para "trancado". Deve rodar
+// depois do ProcessReturns, para que um trancamento emendado no anterior encontre o aluno matriculado.
+func (r *LeaveRepository) ProcessStarts(today time.Time) (int, error) {
+	rows, err := r.DB.Query(`
+		SELECT l.id, l.student_id, l.semester_id, l.reason
+		FROM leave_requests l
+		JOIN students s ON s.id = l.student_id
+		JOIN academic_semesters sem ON sem.id = l.semester_id
+		WHERE l.status = 'approved'
+		  AND l.started_at IS NULL
+		  AND s.status = 'enrolled'
+		  AND sem.start_date <= $1
+	`, today)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar inícios de trancamento: %w", err)
+	}
+
+	var pending []models.LeaveRequest
+	for rows.Next() {
+		var l models.LeaveRequest
+		if err := rows.Scan(&l.ID, &l.StudentID, &l.SemesterID, &l.Reason); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("erro ao escanear início de trancamento: %w", err)
+		}
+		pending = append(pending, l)
+	}
+	rows.Close()
+
+	done := 0
+	for _, l := range pending {
+		tx, err := r.DB.Begin()
+		if err != nil {
+			return done, fmt.Errorf("erro ao iniciar transação: %w", err)
+		}
+
+		_, err = dropSemesterRegistrations(tx, l.StudentID, l.SemesterID)
+		if err == nil {
+			err = startLeave(tx, &l, "sistema", today)
+		}
+		if err != nil {
+			tx.Rollback()
+			return done, fmt.Errorf("erro no início do trancamento %d: %w", l.ID, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return done, err
+		}
+		done++
+	}
+
+	return done, nil
+}
+
+// AcademicClock conta os semestres regulares cursados desde o ingresso, descontando os semestres trancados
+func (r *LeaveRepository) AcademicClock(studentID int) (*models.AcademicClock, error) {
+	clock := &models.AcademicClock{StudentID: studentID}
+
+	err := r.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM academic_semesters sem, students s
+		WHERE s.id = $1
+		  AND sem.kind = 'semester'
+		  AND sem.start_date <= CURRENT_DATE
+		  AND sem.end_date >= (
+		    SELECT COALESCE(MIN(h.effective_date), s.created_at::date)
+		    FROM student_status_history h
+		    WHERE h.student_id = s.id
+		  )
+	`, studentID).Scan(&clock.ElapsedSemesters)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar semestres do aluno: %w", err)
+	}
+
+	err = r.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM leave_requests l
+		JOIN academic_semesters sem ON sem.id = l.semester_id
+		WHERE l.student_id = $1 AND l.status = 'approved' AND sem.start_date <= CURRENT_DATE
+	`, studentID).Scan(&clock.LeaveSemesters)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar semestres trancados: %w", err)
+	}
+
+	clock.CountedSemesters = clock.ElapsedSemesters - clock.LeaveSemesters
+	if clock.CountedSemesters < 0 {
+		clock.CountedSemesters = 0
+	}
+	return clock, nil
+}
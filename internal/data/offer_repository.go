@@ -0,0 +1,537 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type OfferRepository struct {
+	DB *sql.DB
+}
+
+const offerSelect = `
+	SELECT o.id, o.discipline_id, d.name, d.code, o.semester_id,
+	       o.teacher_id, t.name, o.schedule, o.room, o.planned_hours, o.capacity, o.closed_at, o.closed_by
+	FROM discipline_offers o
+	JOIN disciplines d ON d.id = o.discipline_id
+	LEFT JOIN teachers t ON t.id = o.teacher_id
+`
+
+func scanOffers(rows *sql.Rows) ([]models.DisciplineOffer, error) {
+	var list []models.DisciplineOffer
+	err := eachOffer(rows, func(o models.DisciplineOffer) error {
+		list = append(list, o)
+		return nil
+	})
+	return list, err
+}
+
+func eachOffer(rows *sql.Rows, fn func(models.DisciplineOffer) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(*o); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao iterar sobre os resultados das ofertas: %w", err)
+	}
+	return nil
+}
+
+func scanOffer(row interface{ Scan(...any) error }) (*models.DisciplineOffer, error) {
+	var o models.DisciplineOffer
+	var teacherID sql.NullInt64
+	var teacherName, room sql.NullString
+
+	err := row.Scan(
+		&o.ID, &o.DisciplineID, &o.DisciplineName, &o.DisciplineCode, &o.SemesterID,
+		&teacherID, &teacherName, &o.Schedule, &room, &o.PlannedHours, &o.Capacity, &o.ClosedAt, &o.ClosedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	o.TeacherID = int(teacherID.Int64)
+	if teacherName.Valid {
+		o.TeacherName = teacherName.String
+	} else {
+		o.TeacherName = "Professor não definido"
+	}
+	o.Room = room.String
+
+	return &o, nil
+}
+
+// GetAll lista as ofertas. Se semesterID for 0, lista de todos os semestres.
+const offerListQuery = offerSelect + `
+	WHERE ($1 = 0 OR o.semester_id = $1)
+	ORDER BY o.semester_id DESC, d.name ASC
+`
+
+func (r *OfferRepository) GetAll(semesterID int) ([]models.DisciplineOffer, error) {
+	rows, err := r.DB.Query(offerListQuery, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas: %w", err)
+	}
+	return scanOffers(rows)
+}
+
+// Each percorre as ofertas do GetAll uma por vez (usado pela exportação)
+func (r *OfferRepository) Each(semesterID int, fn func(models.DisciplineOffer) error) error {
+	rows, err := r.DB.Query(offerListQuery, semesterID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar ofertas: %w", err)
+	}
+	return eachOffer(rows, fn)
+}
+
+func (r *OfferRepository) GetByID(id int) (*models.DisciplineOffer, error) {
+	o, err := scanOffer(r.DB.QueryRow(offerSelect+` WHERE o.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar oferta: %w", err)
+	}
+	return o, nil
+}
+
+// GetByStudent lista as ofertas em que o aluno está matriculado no semestre
+func (r *OfferRepository) GetByStudent(studentID, semesterID int) ([]models.DisciplineOffer, error) {
+	query := offerSelect + `
+		JOIN registrations reg ON reg.offer_id = o.id
+		WHERE reg.student_id = $1 AND o.semester_id = $2
+		ORDER BY d.name ASC
+	`
+
+	rows, err := r.DB.Query(query, studentID, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas do aluno: %w", err)
+	}
+	return scanOffers(rows)
+}
+
+// GetByTeacher lista as ofertas do semestre em que o professor é responsável ou faz parte da equipe
+func (r *OfferRepository) GetByTeacher(teacherID, semesterID int) ([]models.DisciplineOffer, error) {
+	query := offerSelect + `
+		WHERE o.semester_id = $2
+		  AND (o.teacher_id = $1 OR EXISTS (
+		    SELECT 1 FROM offer_teachers ot WHERE ot.offer_id = o.id AND ot.teacher_id = $1
+		  ))
+		ORDER BY d.name ASC
+	`
+
+	rows, err := r.DB.Query(query, teacherID, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas do professor: %w", err)
+	}
+	return scanOffers(rows)
+}
+
+func (r *OfferRepository) GetByRoom(room string, semesterID int) ([]models.DisciplineOffer, error) {
+	query := offerSelect + `
+		WHERE o.room = $1 AND o.semester_id = $2
+		ORDER BY d.name ASC
+	`
+
+	rows, err := r.DB.Query(query, room, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas da sala: %w", err)
+	}
+	return scanOffers(rows)
+}
+
+func (r *OfferRepository) Create(o *models.DisciplineOffer) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO discipline_offers (discipline_id, semester_id, teacher_id, schedule, room, capacity)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		RETURNING id
+	`
+
+	var id int
+	err = tx.QueryRow(
+		query,
+		o.DisciplineID, o.SemesterID, nullableID(o.TeacherID), o.Schedule, o.Room, o.Capacity,
+	).Scan(&id)
+
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return 0, fmt.Errorf("disciplina já ofertada neste semestre")
+		}
+		return 0, fmt.Errorf("erro ao criar oferta: %w", err)
+	}
+
+	if err := syncLeadTeacher(tx, id, o.TeacherID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *OfferRepository) Update(o *models.DisciplineOffer) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE discipline_offers
+		SET discipline_id = $1, semester_id = $2, teacher_id = $3, schedule = $4, room = NULLIF($5, ''), capacity = $6
+		WHERE id = $7
+	`
+
+	result, err := tx.Exec(
+		query,
+		o.DisciplineID, o.SemesterID, nullableID(o.TeacherID), o.Schedule, o.Room, o.Capacity, o.ID,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return fmt.Errorf("disciplina já ofertada neste semestre")
+		}
+		return fmt.Errorf("erro ao atualizar oferta: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma oferta encontrada com o ID %d", o.ID)
+	}
+
+	if err := syncLeadTeacher(tx, o.ID, o.TeacherID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// syncLeadTeacher mantém na equipe da oferta o professor responsável de discipline_offers.teacher_id.
+// O responsável anterior sai da equipe; se o novo já era assistente, passa a responsável.
+func syncLeadTeacher(tx *sql.Tx, offerID, teacherID int) error {
+	_, err := tx.Exec(`
+		DELETE FROM offer_teachers
+		WHERE offer_id = $1 AND role = 'lead' AND teacher_id IS DISTINCT FROM $2
+	`, offerID, nullableID(teacherID))
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar equipe da oferta: %w", err)
+	}
+	if teacherID == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO offer_teachers (offer_id, teacher_id, role, hour_share)
+		VALUES ($1, $2, 'lead', 100)
+		ON CONFLICT (offer_id, teacher_id) DO UPDATE SET role = 'lead'
+	`, offerID, teacherID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar equipe da oferta: %w", err)
+	}
+	return nil
+}
+
+func (r *OfferRepository) Delete(id int) error {
+	result, err := r.DB.Exec(`DELETE FROM discipline_offers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar oferta: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma oferta encontrada com o ID %d", id)
+	}
+	return nil
+}
+
+// SetPlannedHours grava a carga horária prevista pelo calendário, usada no cálculo da frequência
+func (r *OfferRepository) SetPlannedHours(id, hours int) error {
+	_, err := r.DB.Exec(`UPDATE discipline_offers SET planned_hours = $1 WHERE id = $2`, hours, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar carga horária prevista da oferta %d: %w", id, err)
+	}
+	return nil
+}
+
+// Close fecha o diário da oferta: recalcula nota e frequência das matrículas não trancadas e grava a
+// situação final (reprovado por falta abaixo de 75%, aprovado com nota a partir de 60, senão exame).
+// Depois do fechamento os lançamentos de notas e faltas não alteram mais as matrículas.
+func (r *OfferRepository) Close(id int, closedBy string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var closed bool
+	err = tx.QueryRow(`SELECT closed_at IS NOT NULL FROM discipline_offers WHERE id = $1 FOR UPDATE`, id).Scan(&closed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("nenhuma oferta encontrada com o ID %d", id)
+		}
+		return fmt.Errorf("erro ao buscar oferta: %w", err)
+	}
+	if closed {
+		return fmt.Errorf("diário da oferta já fechado")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE registrations r
+		SET final_grade = calculate_final_grade(r.id),
+		    (absences, frequency) = (SELECT a.absences, a.frequency FROM calculate_attendance(r.id) a)
+		WHERE r.offer_id = $1 AND r.status <> 'dropped'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao recalcular matrículas da oferta: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE registrations
+		SET status = CASE
+		      WHEN frequency < 75 THEN 'failed'
+		      WHEN final_grade >= 60 THEN 'approved'
+		      ELSE 'take_test'
+		    END::registration_status,
+		    approved = (frequency >= 75 AND final_grade >= 60)
+		WHERE offer_id = $1 AND status <> 'dropped'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar situação das matrículas: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE discipline_offers SET closed_at = CURRENT_TIMESTAMP, closed_by = $1 WHERE id = $2`, closedBy, id)
+	if err != nil {
+		return fmt.Errorf("erro ao fechar diário da oferta: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+// nullableID converte IDs opcionais (0 = não informado) para NULL no banco
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func (r *OfferRepository) GetTeachers(offerID int) ([]models.OfferTeacher, error) {
+	rows, err := r.DB.Query(`
+		SELECT ot.offer_id, ot.teacher_id, t.name, ot.role, ot.hour_share
+		FROM offer_teachers ot
+		JOIN teachers t ON t.id = ot.teacher_id
+		WHERE ot.offer_id = $1
+		ORDER BY ot.role, t.name
+	`, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar equipe da oferta: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.OfferTeacher{}
+	for rows.Next() {
+		var t models.OfferTeacher
+		if err := rows.Scan(&t.OfferID, &t.TeacherID, &t.TeacherName, &t.Role, &t.HourShare); err != nil {
+			return nil, fmt.Errorf("erro ao escanear professor da oferta: %w", err)
+		}
+		list = append(list, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre a equipe da oferta: %w", err)
+	}
+	return list, nil
+}
+
+// SetTeachers substitui a equipe da oferta. O professor com papel lead passa a ser o responsável
+// em discipline_offers.teacher_id.
+func (r *OfferRepository) SetTeachers(offerID int, team []models.OfferTeacher) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM offer_teachers WHERE offer_id = $1`, offerID); err != nil {
+		return fmt.Errorf("erro ao atualizar equipe da oferta: %w", err)
+	}
+
+	lead := 0
+	for _, t := range team {
+		_, err := tx.Exec(`
+			INSERT INTO offer_teachers (offer_id, teacher_id, role, hour_share)
+			VALUES ($1, $2, $3, $4)
+		`, offerID, t.TeacherID, t.Role, t.HourShare)
+		if err != nil {
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+				return fmt.Errorf("professor %d inexistente", t.TeacherID)
+			}
+			return fmt.Errorf("erro ao atualizar equipe da oferta: %w", err)
+		}
+		if t.Role == models.RoleLead {
+			lead = t.TeacherID
+		}
+	}
+
+	result, err := tx.Exec(`UPDATE discipline_offers SET teacher_id = $1 WHERE id = $2`, nullableID(lead), offerID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar professor responsável: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("nenhuma oferta encontrada com o ID %d", offerID)
+	}
+
+	return tx.Commit()
+}
+
+const substitutionSelect = `
+	SELECT s.id, s.offer_id, s.absent_teacher_id, a.name, s.substitute_teacher_id, b.name,
+	       s.start_date, s.end_date, s.reason, s.created_by, s.created_at
+	FROM offer_substitutions s
+	JOIN teachers a ON a.id = s.absent_teacher_id
+	JOIN teachers b ON b.id = s.substitute_teacher_id
+`
+
+func scanSubstitutions(rows *sql.Rows) ([]models.OfferSubstitution, error) {
+	defer rows.Close()
+
+	list := []models.OfferSubstitution{}
+	for rows.Next() {
+		var s models.OfferSubstitution
+		err := rows.Scan(
+			&s.ID, &s.OfferID, &s.AbsentTeacherID, &s.AbsentTeacherName, &s.SubstituteTeacherID, &s.SubstituteTeacherName,
+			&s.StartDate, &s.EndDate, &s.Reason, &s.CreatedBy, &s.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear substituição: %w", err)
+		}
+		list = append(list, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as substituições: %w", err)
+	}
+	return list, nil
+}
+
+func (r *OfferRepository) GetSubstitutions(offerID int) ([]models.OfferSubstitution, error) {
+	rows, err := r.DB.Query(substitutionSelect+` WHERE s.offer_id = $1 ORDER BY s.start_date`, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar substituições da oferta: %w", err)
+	}
+	return scanSubstitutions(rows)
+}
+
+// GetSubstitutionsBySemester lista as substituições das ofertas do semestre
+func (r *OfferRepository) GetSubstitutionsBySemester(semesterID int) ([]models.OfferSubstitution, error) {
+	rows, err := r.DB.Query(substitutionSelect+`
+		JOIN discipline_offers o ON o.id = s.offer_id
+		WHERE o.semester_id = $1
+		ORDER BY s.start_date
+	`, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar substituições do semestre: %w", err)
+	}
+	return scanSubstitutions(rows)
+}
+
+// CreateSubstitution registra a substituição. O professor substituído precisa fazer parte da equipe
+// e não pode ter outra substituição na oferta no mesmo período.
+func (r *OfferRepository) CreateSubstitution(s *models.OfferSubstitution) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serializa as substituições da oferta
+	var inTeam bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM offer_teachers WHERE offer_id = o.id AND teacher_id = $2)
+		FROM discipline_offers o
+		WHERE o.id = $1
+		FOR UPDATE
+	`, s.OfferID, s.AbsentTeacherID).Scan(&inTeam)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("nenhuma oferta encontrada com o ID %d", s.OfferID)
+		}
+		return 0, fmt.Errorf("erro ao buscar oferta: %w", err)
+	}
+	if !inTeam {
+		return 0, fmt.Errorf("substituição: o professor substituído não faz parte da equipe da oferta")
+	}
+
+	var overlapping bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM offer_substitutions
+			WHERE offer_id = $1 AND absent_teacher_id = $2 AND start_date <= $4 AND end_date >= $3
+		)
+	`, s.OfferID, s.AbsentTeacherID, s.StartDate, s.EndDate).Scan(&overlapping)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar substituições: %w", err)
+	}
+	if overlapping {
+		return 0, fmt.Errorf("substituição: já existe substituição do professor neste período")
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO offer_substitutions (offer_id, absent_teacher_id, substitute_teacher_id, start_date, end_date, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, s.OfferID, s.AbsentTeacherID, s.SubstituteTeacherID, s.StartDate, s.EndDate, s.Reason, s.CreatedBy).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return 0, fmt.Errorf("substituição: professor substituto inexistente")
+		}
+		return 0, fmt.Errorf("erro ao registrar substituição: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *OfferRepository) DeleteSubstitution(id int) error {
+	result, err := r.DB.Exec(`DELETE FROM offer_substitutions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao remover substituição: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma substituição encontrada com o ID %d", id)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type ReenrollmentRepository struct {
+	DB *sql.DB
+}
+
+// Alunos que precisam confirmar a rematrícula: matriculados que ainda não confirmaram. Os ingressantes
+// no semestre da campanha já entram matriculados e não fazem rematrícula.
+const reenrollmentPendingWhere = `
+	s.status = 'enrolled'
+	AND s.entry_semester_id IS DISTINCT FROM (SELECT rc.semester_id FROM reenrollment_campaigns rc WHERE rc.id = $1)
+	AND NOT EXISTS (SELECT 1 FROM reenrollments re WHERE re.campaign_id = $1 AND re.student_id = s.id)
+`
+
+const campaignSelect = `
+	SELECT c.id, c.semester_id, c.opens_at, c.closes_at, c.created_by, c.closed_at, c.closed_by,
+	       (SELECT COUNT(*) FROM reenrollments re WHERE re.campaign_id = c.id),
+	       (SELECT COUNT(*) FROM students s WHERE ` + reenrollmentPendingWhere + `)
+	FROM reenrollment_campaigns c
+`
+
+func scanCampaign(row interface{ Scan(...any) error }) (*models.ReenrollmentCampaign, error) {
+	var c models.ReenrollmentCampaign
+	err := row.Scan(&c.ID, &c.SemesterID, &c.OpensAt, &c.ClosesAt, &c.CreatedBy, &c.ClosedAt, &c.ClosedBy, &c.Confirmed, &c.Pending)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ReenrollmentRepository) GetByID(id int) (*models.ReenrollmentCampaign, error) {
+	c, err := scanCampaign(r.DB.QueryRow(campaignSelect+` WHERE c.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar campanha de rematrícula: %w", err)
+	}
+	return c, nil
+}
+
+func (r *ReenrollmentRepository) GetBySemester(semesterID int) (*models.ReenrollmentCampaign, error) {
+	var id int
+	err := r.DB.QueryRow(`SELECT id FROM reenrollment_campaigns WHERE semester_id = $1`, semesterID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar campanha de rematrícula: %w", err)
+	}
+	return r.GetByID(id)
+}
+
+func (r *ReenrollmentRepository) Open(c *models.ReenrollmentCampaign) (int, error) {
+	query := `
+		INSERT INTO reenrollment_campaigns (semester_id, opens_at, closes_at, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var id int
+	err := r.DB.QueryRow(query, c.SemesterID, c.OpensAt, c.ClosesAt, c.CreatedBy).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return 0, fmt.Errorf("já existe campanha de rematrícula para este semestre")
+		}
+		return 0, fmt.Errorf("erro ao abrir campanha de rematrícula: %w", err)
+	}
+	return id, nil
+}
+
+// Confirm registra a confirmação do aluno e o matricula nas ofertas escolhidas, que precisam ser do semestre da campanha.
+// Se o curso exige aprovação da coordenação, são criados pedidos de matrícula.
+func (r *ReenrollmentRepository) Confirm(c *models.ReenrollmentCampaign, re *models.Reenrollment) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O bloqueio compartilhado impede que a campanha seja encerrada no meio da confirmação
+	var closed bool
+	err = tx.QueryRow(`SELECT closed_at IS NOT NULL FROM reenrollment_campaigns WHERE id = $1 FOR SHARE`, c.ID).Scan(&closed)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar campanha de rematrícula: %w", err)
+	}
+	if closed {
+		return fmt.Errorf("campanha de rematrícula já encerrada")
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO reenrollments (campaign_id, student_id)
+		VALUES ($1, $2)
+		ON CONFLICT (campaign_id, student_id) DO UPDATE SET confirmed_at = CURRENT_TIMESTAMP
+		RETURNING confirmed_at
+	`, c.ID, re.StudentID).Scan(&re.ConfirmedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao confirmar rematrícula: %w", err)
+	}
+
+	var requiresApproval bool
+	err = tx.QueryRow(`
+		SELECT COALESCE(c.requires_enrollment_approval, FALSE)
+		FROM students s
+		LEFT JOIN courses c ON c.id = s.course_id
+		WHERE s.id = $1
+	`, re.StudentID).Scan(&requiresApproval)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar curso do aluno: %w", err)
+	}
+
+	for _, offerID := range re.OfferIDs {
+		var semesterID int
+		err := tx.QueryRow(`SELECT semester_id FROM discipline_offers WHERE id = $1`, offerID).Scan(&semesterID)
+		if err != nil || semesterID != c.SemesterID {
+			return fmt.Errorf("oferta %d não pertence ao semestre da rematrícula", offerID)
+		}
+	}
+
+	// Nos cursos com aprovação da coordenação as escolhas viram pedidos de matrícula
+	if requiresApproval {
+		if _, err := createEnrollmentRequests(tx, re.StudentID, re.OfferIDs); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := reserveSeats(tx, re.StudentID, re.OfferIDs); err != nil {
+		return err
+	}
+
+	for _, offerID := range re.OfferIDs {
+		_, err = tx.Exec(`
+			INSERT INTO registrations (student_id, offer_id)
+			VALUES ($1, $2)
+			ON CONFLICT (student_id, offer_id) DO NOTHING
+		`, re.StudentID, offerID)
+		if err != nil {
+			return fmt.Errorf("erro ao matricular na oferta %d: %w", offerID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Pending lista os alunos matriculados que ainda não confirmaram a rematrícula
+func (r *ReenrollmentRepository) Pending(campaignID int) ([]models.Student, error) {
+	return pendingReenrollments(r.DB, campaignID)
+}
+
+func pendingReenrollments(q interface {
+	Query(string, ...any) (*sql.Rows, error)
+}, campaignID int) ([]models.Student, error) {
+	query := `
+		SELECT s.id, s.name, s.email, s.registration_number, s.status, s.course_id, COALESCE(c.name, '')
+		FROM students s
+		LEFT JOIN courses c ON c.id = s.course_id
+		WHERE ` + reenrollmentPendingWhere + `
+		ORDER BY c.name, s.name
+	`
+
+	rows, err := q.Query(query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alunos sem rematrícula: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.Student
+	for rows.Next() {
+		var s models.Student
+		if err := rows.Scan(&s.ID, &s.Name, &s.Email, &s.RegistrationNumber, &s.Status, &s.CourseID, &s.CourseName); err != nil {
+			return nil, fmt.Errorf("erro ao escanear aluno: %w", err)
+		}
+		s.Active = true
+		list = append(list, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os alunos: %w", err)
+	}
+	return list, nil
+}
+
+// Close encerra a campanha e retorna os alunos que não confirmaram.
+// Com markDropped, esses alunos passam para a situação de evadido.
+func (r *ReenrollmentRepository) Close(campaignID int, closedBy string, markDropped bool) ([]models.Student, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE reenrollment_campaigns
+		SET closed_at = CURRENT_TIMESTAMP, closed_by = $1
+		WHERE id = $2 AND closed_at IS NULL
+	`, closedBy, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao encerrar campanha de rematrícula: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("campanha de rematrícula já encerrada")
+	}
+
+	// Com a campanha encerrada nesta transação, nenhuma confirmação entra depois da lista
+	pending, err := pendingReenrollments(tx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if markDropped {
+		for i := range pending {
+			err := changeStudentStatus(tx, &models.StudentStatusChange{
+				StudentID: pending[i].ID,
+				ToStatus:  models.StudentDroppedOut,
+				Reason:    "Não realizou a rematrícula",
+				ChangedBy: closedBy,
+			})
+			if err != nil {
+				return nil, err
+			}
+			pending[i].Status = models.StudentDroppedOut
+			pending[i].Active = false
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
@@ -0,0 +1,221 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+type RegistrationRepository struct {
+	DB *sql.DB
+}
+
+const registrationSelect = `
+	SELECT r.id, r.student_id, s.name, r.offer_id, o.semester_id, d.name,
+	       r.final_grade, r.frequency, COALESCE(r.absences, 0), r.status, r.approved,
+	       r.created_at, r.updated_at
+	FROM registrations r
+	JOIN students s ON s.id = r.student_id
+	JOIN discipline_offers o ON o.id = r.offer_id
+	JOIN disciplines d ON d.id = o.discipline_id
+`
+
+func scanRegistration(row interface{ Scan(...any) error }) (*models.Registration, error) {
+	var reg models.Registration
+	err := row.Scan(
+		&reg.ID, &reg.StudentID, &reg.StudentName, &reg.OfferID, &reg.SemesterID, &reg.DisciplineName,
+		&reg.FinalGrade, &reg.Frequency, &reg.Absences, &reg.Status, &reg.Approved,
+		&reg.CreatedAt, &reg.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+func (r *RegistrationRepository) GetByID(id int) (*models.Registration, error) {
+	reg, err := scanRegistration(r.DB.QueryRow(registrationSelect+` WHERE r.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar matrícula: %w", err)
+	}
+	return reg, nil
+}
+
+func (r *RegistrationRepository) GetByOffer(offerID int) ([]models.Registration, error) {
+	var list []models.Registration
+	err := r.EachByOffer(offerID, func(reg models.Registration) error {
+		list = append(list, reg)
+		return nil
+	})
+	return list, err
+}
+
+// EachByOffer percorre as matrículas da oferta uma por vez, na ordem do GetByOffer
+func (r *RegistrationRepository) EachByOffer(offerID int, fn func(models.Registration) error) error {
+	rows, err := r.DB.Query(registrationSelect+` WHERE r.offer_id = $1 ORDER BY s.name ASC`, offerID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar matrículas da oferta: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reg, err := scanRegistration(rows)
+		if err != nil {
+			return fmt.Errorf("erro ao escanear matrícula: %w", err)
+		}
+		if err := fn(*reg); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao iterar sobre as matrículas: %w", err)
+	}
+	return nil
+}
+
+// Create matricula o aluno na oferta se ela ainda tiver vagas
+func (r *RegistrationRepository) Create(reg *models.Registration) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := reserveSeats(tx, reg.StudentID, []int{reg.OfferID}); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO registrations (student_id, offer_id)
+		VALUES ($1, $2)
+		RETURNING id
+	`, reg.StudentID, reg.OfferID).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return 0, fmt.Errorf("aluno já matriculado nesta oferta")
+		}
+		return 0, fmt.Errorf("erro ao criar matrícula: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return id, nil
+}
+
+// reserveSeats confere as vagas das ofertas antes de matricular o aluno nelas. Cada oferta fica
+// bloqueada até o fim da transação, então matrículas simultâneas na mesma oferta são contadas uma de
+// cada vez; as ofertas são bloqueadas em ordem de ID para que duas transações não se esperem em
+// ciclo. A matrícula que o aluno já tiver na oferta não ocupa uma nova vaga.
+func reserveSeats(tx *sql.Tx, studentID int, offerIDs []int) error {
+	ids := slices.Clone(offerIDs)
+	slices.Sort(ids)
+
+	for _, offerID := range slices.Compact(ids) {
+		var capacity sql.NullInt64
+		err := tx.QueryRow(`SELECT capacity FROM discipline_offers WHERE id = $1 FOR UPDATE`, offerID).Scan(&capacity)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("oferta %d não encontrada", offerID)
+			}
+			return fmt.Errorf("erro ao buscar vagas da oferta %d: %w", offerID, err)
+		}
+		if !capacity.Valid {
+			continue
+		}
+
+		var taken int64
+		err = tx.QueryRow(`
+			SELECT COUNT(*) FROM registrations
+			WHERE offer_id = $1 AND status <> 'dropped' AND student_id <> $2
+		`, offerID, studentID).Scan(&taken)
+		if err != nil {
+			return fmt.Errorf("erro ao contar matrículas da oferta %d: %w", offerID, err)
+		}
+		if taken >= capacity.Int64 {
+			return fmt.Errorf("oferta %d sem vagas disponíveis", offerID)
+		}
+	}
+	return nil
+}
+
+func (r *RegistrationRepository) Delete(id int) error {
+	result, err := r.DB.Exec(`DELETE FROM registrations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar matrícula: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma matrícula encontrada com o ID %d", id)
+	}
+	return nil
+}
+
+// SaveAttendance grava as faltas de uma aula; se já houver lançamento para a aula, ele é substituído.
+// Sem aulas geradas (SessionID nil), o lançamento é único por data.
+func (r *RegistrationRepository) SaveAttendance(a *models.AttendanceRecord) (int, error) {
+	query := `
+		INSERT INTO attendance_records (registration_id, session_id, class_date, hours_absent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (registration_id, session_id)
+		DO UPDATE SET hours_absent = EXCLUDED.hours_absent
+		RETURNING id
+	`
+	if a.SessionID == nil {
+		query = `
+			INSERT INTO attendance_records (registration_id, session_id, class_date, hours_absent)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (registration_id, class_date) WHERE session_id IS NULL
+			DO UPDATE SET hours_absent = EXCLUDED.hours_absent
+			RETURNING id
+		`
+	}
+
+	var id int
+	err := r.DB.QueryRow(query, a.RegistrationID, a.SessionID, a.ClassDate, a.HoursAbsent).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao lançar faltas: %w", err)
+	}
+	return id, nil
+}
+
+func (r *RegistrationRepository) GetAttendance(registrationID int) ([]models.AttendanceRecord, error) {
+	query := `
+		SELECT id, registration_id, session_id, class_date, hours_absent, teacher_id
+		FROM attendance_records
+		WHERE registration_id = $1
+		ORDER BY class_date ASC, id ASC
+	`
+
+	rows, err := r.DB.Query(query, registrationID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar faltas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.AttendanceRecord
+	for rows.Next() {
+		var a models.AttendanceRecord
+		if err := rows.Scan(&a.ID, &a.RegistrationID, &a.SessionID, &a.ClassDate, &a.HoursAbsent, &a.TeacherID); err != nil {
+			return nil, fmt.Errorf("erro ao escanear falta: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as faltas: %w", err)
+	}
+	return list, nil
+}
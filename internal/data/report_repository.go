@@ -0,0 +1,321 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+)
+
+type ReportRepository struct {
+	DB *sql.DB
+}
+
+// DemandStudents carrega os alunos matriculados (do curso, ou todos com courseID 0) com o semestre
+// que vão cursar e as disciplinas cumpridas e em andamento de cada um
+func (r *ReportRepository) DemandStudents(courseID int) ([]academic.DemandStudent, error) {
+	// O semestre do aluno segue a mesma contagem do prazo de integralização: semestres iniciados
+	// desde o ingresso, descontados os trancamentos, mais o semestre que vai começar
+	query := `
+		SELECT s.id, s.course_id,
+		       1 + (
+		         SELECT COUNT(*) FROM academic_semesters sem
+		         WHERE sem.kind = 'semester'
+		           AND sem.start_date <= CURRENT_DATE
+		           AND sem.end_date >= (
+		             SELECT COALESCE(MIN(h.effective_date), s.created_at::date)
+		             FROM student_status_history h WHERE h.student_id = s.id
+		           )
+		       ) - (
+		         SELECT COUNT(*) FROM leave_requests l
+		         JOIN academic_semesters sem ON sem.id = l.semester_id
+		         WHERE l.student_id = s.id AND l.status = 'approved' AND sem.start_date <= CURRENT_DATE
+		       )
+		FROM students s
+		WHERE s.status = 'enrolled' AND s.course_id IS NOT NULL AND ($1 = 0 OR s.course_id = $1)
+	`
+
+	rows, err := r.DB.Query(query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alunos para previsão de demanda: %w", err)
+	}
+	defer rows.Close()
+
+	var students []academic.DemandStudent
+	index := map[int]int{}
+	for rows.Next() {
+		s := academic.DemandStudent{Fulfilled: map[int]bool{}, InProgress: map[int]bool{}}
+		if err := rows.Scan(&s.ID, &s.CourseID, &s.Level); err != nil {
+			return nil, fmt.Errorf("erro ao escanear aluno: %w", err)
+		}
+		index[s.ID] = len(students)
+		students = append(students, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os alunos: %w", err)
+	}
+	rows.Close()
+
+	pairs := []struct {
+		query string
+		set   func(s *academic.DemandStudent) map[int]bool
+	}{
+		{`SELECT student_id, discipline_id FROM student_fulfilled_disciplines`,
+			func(s *academic.DemandStudent) map[int]bool { return s.Fulfilled }},
+		{`SELECT r.student_id, o.discipline_id FROM registrations r
+		  JOIN discipline_offers o ON o.id = r.offer_id
+		  WHERE r.status IN ('in_progress', 'take_test')`,
+			func(s *academic.DemandStudent) map[int]bool { return s.InProgress }},
+	}
+
+	for _, p := range pairs {
+		rows, err := r.DB.Query(p.query)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar disciplinas dos alunos: %w", err)
+		}
+		for rows.Next() {
+			var studentID, disciplineID int
+			if err := rows.Scan(&studentID, &disciplineID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("erro ao escanear disciplina do aluno: %w", err)
+			}
+			if i, ok := index[studentID]; ok {
+				p.set(&students[i])[disciplineID] = true
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao iterar sobre as disciplinas dos alunos: %w", err)
+		}
+	}
+
+	return students, nil
+}
+
+// DemandCurriculum carrega as matrizes curriculares (do curso, ou de todos com courseID 0)
+func (r *ReportRepository) DemandCurriculum(courseID int) ([]academic.CurriculumEntry, error) {
+	rows, err := r.DB.Query(`
+		SELECT course_id, discipline_id, COALESCE(suggested_semester, 0)
+		FROM course_disciplines
+		WHERE $1 = 0 OR course_id = $1
+	`, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar matrizes curriculares: %w", err)
+	}
+	defer rows.Close()
+
+	var list []academic.CurriculumEntry
+	for rows.Next() {
+		var c academic.CurriculumEntry
+		if err := rows.Scan(&c.CourseID, &c.DisciplineID, &c.SuggestedSemester); err != nil {
+			return nil, fmt.Errorf("erro ao escanear matriz curricular: %w", err)
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as matrizes curriculares: %w", err)
+	}
+	return list, nil
+}
+
+// DisciplineStats conta as aprovações e reprovações de cada disciplina em todas as ofertas anteriores
+func (r *ReportRepository) DisciplineStats() (map[int]academic.DisciplineStats, error) {
+	rows, err := r.DB.Query(`
+		SELECT o.discipline_id,
+		       COUNT(*) FILTER (WHERE r.status = 'approved'),
+		       COUNT(*) FILTER (WHERE r.status = 'failed')
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		GROUP BY o.discipline_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular taxas de reprovação: %w", err)
+	}
+	defer rows.Close()
+
+	stats := map[int]academic.DisciplineStats{}
+	for rows.Next() {
+		var id int
+		var s academic.DisciplineStats
+		if err := rows.Scan(&id, &s.Approved, &s.Failed); err != nil {
+			return nil, fmt.Errorf("erro ao escanear taxas de reprovação: %w", err)
+		}
+		stats[id] = s
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as taxas de reprovação: %w", err)
+	}
+	return stats, nil
+}
+
+// WorkloadTeachers lista os professores ativos e os inativos que têm oferta no semestre
+// (do departamento, ou todos com departmentID 0)
+func (r *ReportRepository) WorkloadTeachers(semesterID, departmentID int) ([]models.TeacherWorkload, error) {
+	rows, err := r.DB.Query(`
+		SELECT t.id, t.name, t.active, t.department_id, COALESCE(d.name, 'Sem departamento'), t.contract_hours,
+		       t.contract_regime, t.academic_title
+		FROM teachers t
+		LEFT JOIN departments d ON d.id = t.department_id
+		WHERE ($2 = 0 OR t.department_id = $2)
+		  AND (t.active OR EXISTS (
+		    SELECT 1 FROM offer_teachers ot
+		    JOIN discipline_offers o ON o.id = ot.offer_id
+		    WHERE ot.teacher_id = t.id AND o.semester_id = $1
+		  ) OR EXISTS (
+		    SELECT 1 FROM offer_substitutions s
+		    JOIN discipline_offers o ON o.id = s.offer_id
+		    WHERE s.substitute_teacher_id = t.id AND o.semester_id = $1
+		  ))
+		ORDER BY d.name, t.name
+	`, semesterID, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar professores para carga docente: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.TeacherWorkload
+	for rows.Next() {
+		var t models.TeacherWorkload
+		if err := rows.Scan(&t.TeacherID, &t.TeacherName, &t.Active, &t.DepartmentID, &t.DepartmentName, &t.ContractHours,
+			&t.ContractRegime, &t.AcademicTitle); err != nil {
+			return nil, fmt.Errorf("erro ao escanear professor: %w", err)
+		}
+		t.Offers = []models.WorkloadOffer{}
+		list = append(list, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os professores: %w", err)
+	}
+	return list, nil
+}
+
+// WorkloadOffers lista as ofertas do semestre com a carga prevista (ou a carga da disciplina),
+// uma linha por professor da equipe com a sua fração da carga. Durante uma substituição, a fração
+// do professor substituído proporcional aos dias cobertos passa para o substituto.
+// Com departmentID, só entram as linhas dos professores do departamento.
+func (r *ReportRepository) WorkloadOffers(semesterID, departmentID int) ([]models.WorkloadOffer, error) {
+	rows, err := r.DB.Query(`
+		WITH team AS (
+			SELECT ot.offer_id, ot.teacher_id, ot.role::text AS role, ot.hour_share AS share
+			FROM offer_teachers ot
+			JOIN discipline_offers o ON o.id = ot.offer_id
+			WHERE o.semester_id = $1
+		), sem AS (
+			SELECT start_date, end_date, end_date - start_date + 1 AS days
+			FROM academic_semesters WHERE id = $1
+		), covered AS (
+			SELECT s.offer_id, s.absent_teacher_id, s.substitute_teacher_id,
+			       tm.share * (LEAST(s.end_date, sem.end_date) - GREATEST(s.start_date, sem.start_date) + 1) / sem.days AS share
+			FROM offer_substitutions s
+			JOIN team tm ON tm.offer_id = s.offer_id AND tm.teacher_id = s.absent_teacher_id
+			CROSS JOIN sem
+			WHERE sem.days > 0 AND s.start_date <= sem.end_date AND s.end_date >= sem.start_date
+		), allocation AS (
+			SELECT tm.offer_id, tm.teacher_id, tm.role,
+			       tm.share - COALESCE((SELECT SUM(c.share) FROM covered c
+			                            WHERE c.offer_id = tm.offer_id AND c.absent_teacher_id = tm.teacher_id), 0) AS share
+			FROM team tm
+			UNION ALL
+			SELECT offer_id, substitute_teacher_id, 'substitute', share FROM covered
+		)
+		SELECT o.id, a.teacher_id, COALESCE(a.role, ''), COALESCE(a.share, 100)::float8,
+		       d.code, d.name, o.schedule, COALESCE(o.planned_hours, d.workload_hours)
+		FROM discipline_offers o
+		JOIN disciplines d ON d.id = o.discipline_id
+		LEFT JOIN allocation a ON a.offer_id = o.id
+		LEFT JOIN teachers t ON t.id = a.teacher_id
+		WHERE o.semester_id = $1 AND ($2 = 0 OR t.department_id = $2)
+		ORDER BY d.name, o.id, a.role
+	`, semesterID, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas para carga docente: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.WorkloadOffer
+	for rows.Next() {
+		var o models.WorkloadOffer
+		if err := rows.Scan(&o.OfferID, &o.TeacherID, &o.Role, &o.HourShare, &o.DisciplineCode, &o.DisciplineName,
+			&o.Schedule, &o.SemesterHours); err != nil {
+			return nil, fmt.Errorf("erro ao escanear oferta: %w", err)
+		}
+		list = append(list, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as ofertas: %w", err)
+	}
+	return list, nil
+}
+
+// FacultyProfile conta os professores ativos por titulação e por regime de trabalho
+// (do departamento, ou todos com departmentID 0)
+func (r *ReportRepository) FacultyProfile(departmentID int) (*models.FacultyProfile, error) {
+	rows, err := r.DB.Query(`
+		SELECT COALESCE(academic_title::text, ''), COALESCE(contract_regime::text, ''), COUNT(*)
+		FROM teachers
+		WHERE active AND ($1 = 0 OR department_id = $1)
+		GROUP BY 1, 2
+	`, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar perfil do corpo docente: %w", err)
+	}
+	defer rows.Close()
+
+	p := &models.FacultyProfile{ByTitle: map[string]int{}, ByRegime: map[string]int{}}
+	if departmentID != 0 {
+		p.DepartmentID = &departmentID
+	}
+
+	var mastersAndDoctors, doctors, fullOrPart, fullTime int
+	for rows.Next() {
+		var title, regime string
+		var n int
+		if err := rows.Scan(&title, &regime, &n); err != nil {
+			return nil, fmt.Errorf("erro ao escanear perfil do corpo docente: %w", err)
+		}
+		p.Teachers += n
+
+		if title == "" {
+			p.MissingTitle += n
+		} else {
+			p.ByTitle[title] += n
+		}
+		if regime == "" {
+			p.MissingRegime += n
+		} else {
+			p.ByRegime[regime] += n
+		}
+
+		if title == models.TitleMaster || title == models.TitleDoctor {
+			mastersAndDoctors += n
+		}
+		if title == models.TitleDoctor {
+			doctors += n
+		}
+		if regime == models.RegimeFullTime || regime == models.RegimePartTime {
+			fullOrPart += n
+		}
+		if regime == models.RegimeFullTime {
+			fullTime += n
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre o perfil do corpo docente: %w", err)
+	}
+
+	if p.Teachers > 0 {
+		pct := func(n int) float64 { return math.Round(float64(n)/float64(p.Teachers)*1000) / 10 }
+		p.MastersAndDoctorsPct = pct(mastersAndDoctors)
+		p.DoctorsPct = pct(doctors)
+		p.FullOrPartTimePct = pct(fullOrPart)
+		p.FullTimePct = pct(fullTime)
+	}
+	return p, nil
+}
@@ -12,11 +12,25 @@ type SemesterRepository struct {
 	DB *sql.DB
 }
 
+const semesterSelect = `
+	SELECT id, year, kind, period, start_date, end_date
+	FROM academic_semesters
+`
+
+func scanSemester(row interface{ Scan(...any) error }) (*models.AcademicSemester, error) {
+	var s models.AcademicSemester
+	if err := row.Scan(&s.ID, &s.Year, &s.Kind, &s.Period, &s.StartDate, &s.EndDate); err != nil {
+		return nil, err
+	}
+	s.Label = s.String()
+	return &s, nil
+}
+
+// GetAll lista os períodos letivos do ano mais recente para o mais antigo; dentro do ano, pela data
+// de início mais recente (sem data por último), depois por tipo e pelo maior período
 func (r *SemesterRepository) GetAll() ([]models.AcademicSemester, error) {
-	query := `
-		SELECT id, year, period
-		FROM academic_semesters
-		ORDER BY year DESC, period DESC;
+	query := semesterSelect + `
+		ORDER BY year DESC, start_date DESC NULLS LAST, kind, period DESC;
 	`
 
 	rows, err := r.DB.Query(query)
@@ -28,31 +42,28 @@ func (r *SemesterRepository) GetAll() ([]models.AcademicSemester, error) {
 	var list []models.AcademicSemester
 
 	for rows.Next() {
-		var s models.AcademicSemester
-		err := rows.Scan(
-			&s.ID, &s.Year, &s.Period,
-		)
+		s, err := scanSemester(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao escanear semestre acadêmico: %w", err)
 		}
-		list = append(list, s)
+		list = append(list, *s)
 	}
 	return list, nil
 }
 
 func (r *SemesterRepository) Create(s *models.AcademicSemester) (int, error) {
 	query := `
-		INSERT INTO academic_semesters (year, period)
-		VALUES ($1, $2)
+		INSERT INTO academic_semesters (year, kind, period, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id;
 	`
 
 	var id int
-	err := r.DB.QueryRow(query, s.Year, s.Period).Scan(&id)
+	err := r.DB.QueryRow(query, s.Year, s.Kind, s.Period, s.StartDate, s.EndDate).Scan(&id)
 
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
-			return 0, fmt.Errorf("semestre %d.%d já existe", s.Year, s.Period)
+			return 0, fmt.Errorf("semestre %s já existe", s.String())
 		}
 		return 0, fmt.Errorf("erro ao criar semestre acadêmico: %w", err)
 	}
@@ -84,19 +95,203 @@ func (r *SemesterRepository) Delete(id int) error {
 }
 
 func (r *SemesterRepository) GetByID(id int) (*models.AcademicSemester, error) {
-	query := `
-		SELECT id, year, period
-		FROM academic_semesters
-		WHERE id = $1;
-	`
-
-	var s models.AcademicSemester
-	err := r.DB.QueryRow(query, id).Scan(&s.ID, &s.Year, &s.Period)
+	s, err := scanSemester(r.DB.QueryRow(semesterSelect+` WHERE id = $1`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("semestre acadêmico com ID %d não encontrado", id)
 		}
 		return nil, fmt.Errorf("erro ao buscar semestre acadêmico: %w", err)
 	}
-	return &s, nil
+	return s, nil
+}
+
+// FindByPeriod retorna o período letivo do ano, tipo e período informados, ou nil se não existir
+func (r *SemesterRepository) FindByPeriod(year int, kind string, period int) (*models.AcademicSemester, error) {
+	query := semesterSelect + ` WHERE year = $1 AND kind = $2 AND period = $3`
+
+	s, err := scanSemester(r.DB.QueryRow(query, year, kind, period))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar semestre acadêmico: %w", err)
+	}
+	return s, nil
+}
+
+// Current retorna o semestre regular em andamento na data de hoje, ou nil se não houver
+func (r *SemesterRepository) Current() (*models.AcademicSemester, error) {
+	query := semesterSelect + ` WHERE kind = 'semester' AND CURRENT_DATE BETWEEN start_date AND end_date ORDER BY start_date DESC LIMIT 1`
+
+	s, err := scanSemester(r.DB.QueryRow(query))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar semestre acadêmico: %w", err)
+	}
+	return s, nil
+}
+
+// Rollover cria o semestre e copia as ofertas do semestre de origem (disciplina, professor, horário,
+// sala e vagas). Ofertas de professores inativos ou recusados em refused (oferta de origem ->
+// professor -> motivo) não são copiadas e aparecem em Skipped; os demais professores da equipe
+// nessa situação ficam fora da equipe copiada e também aparecem em Skipped.
+func (r *SemesterRepository) Rollover(s *models.AcademicSemester, sourceID int, refused map[int]map[int]string) (*models.RolloverResult, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO academic_semesters (year, kind, period, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, s.Year, s.Kind, s.Period, s.StartDate, s.EndDate).Scan(&s.ID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("semestre %s já existe", s.String())
+		}
+		return nil, fmt.Errorf("erro ao criar semestre acadêmico: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		SELECT o.id, COALESCE(o.teacher_id, 0), d.name, COALESCE(t.name, ''), COALESCE(t.active, TRUE)
+		FROM discipline_offers o
+		JOIN disciplines d ON d.id = o.discipline_id
+		LEFT JOIN teachers t ON t.id = o.teacher_id
+		WHERE o.semester_id = $1
+		ORDER BY d.name
+	`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ofertas do semestre de origem: %w", err)
+	}
+
+	type sourceOffer struct {
+		item      models.RolloverItem
+		teacherID int
+		active    bool
+	}
+	var offers []sourceOffer
+	for rows.Next() {
+		var o sourceOffer
+		if err := rows.Scan(&o.item.SourceOfferID, &o.teacherID, &o.item.DisciplineName, &o.item.TeacherName, &o.active); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("erro ao escanear oferta: %w", err)
+		}
+		offers = append(offers, o)
+	}
+	rows.Close()
+
+	result := &models.RolloverResult{
+		SemesterID:       s.ID,
+		Semester:         s.String(),
+		SourceSemesterID: sourceID,
+		Copied:           []models.RolloverItem{},
+		Skipped:          []models.RolloverItem{},
+	}
+
+	for _, o := range offers {
+		if !o.active {
+			o.item.Reason = "professor inativo"
+			result.Skipped = append(result.Skipped, o.item)
+			continue
+		}
+		if reason, ok := refused[o.item.SourceOfferID][o.teacherID]; ok {
+			o.item.Reason = reason
+			result.Skipped = append(result.Skipped, o.item)
+			continue
+		}
+
+		var id int
+		var teacherID sql.NullInt64
+		err := tx.QueryRow(`
+			INSERT INTO discipline_offers (discipline_id, semester_id, teacher_id, schedule, room, capacity)
+			SELECT discipline_id, $1, teacher_id, schedule, room, capacity
+			FROM discipline_offers
+			WHERE id = $2
+			RETURNING id, teacher_id
+		`, s.ID, o.item.SourceOfferID).Scan(&id, &teacherID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao copiar oferta %d: %w", o.item.SourceOfferID, err)
+		}
+
+		o.item.NewOfferID = &id
+
+		// A equipe docente vem junto, sem os professores que ficaram inativos ou foram recusados
+		dropped, err := copyOfferTeam(tx, o.item.SourceOfferID, id, refused[o.item.SourceOfferID])
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range dropped {
+			item := o.item
+			item.TeacherName = member.TeacherName
+			item.Reason = member.Reason
+			result.Skipped = append(result.Skipped, item)
+		}
+		if err := syncLeadTeacher(tx, id, int(teacherID.Int64)); err != nil {
+			return nil, err
+		}
+		result.Copied = append(result.Copied, o.item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// copyOfferTeam copia a equipe da oferta de origem para a nova oferta. Devolve os professores
+// deixados de fora (inativos ou recusados), com o motivo em Reason.
+func copyOfferTeam(tx *sql.Tx, sourceID, offerID int, refused map[int]string) ([]models.RolloverItem, error) {
+	rows, err := tx.Query(`
+		SELECT ot.teacher_id, t.name, t.active, ot.role, ot.hour_share
+		FROM offer_teachers ot
+		JOIN teachers t ON t.id = ot.teacher_id
+		WHERE ot.offer_id = $1
+		ORDER BY ot.role, t.name
+	`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar equipe da oferta %d: %w", sourceID, err)
+	}
+
+	type member struct {
+		models.OfferTeacher
+		active bool
+	}
+	var team []member
+	for rows.Next() {
+		var m member
+		if err := rows.Scan(&m.TeacherID, &m.TeacherName, &m.active, &m.Role, &m.HourShare); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("erro ao escanear professor da oferta: %w", err)
+		}
+		team = append(team, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre a equipe da oferta: %w", err)
+	}
+
+	var dropped []models.RolloverItem
+	for _, m := range team {
+		reason, ok := refused[m.TeacherID]
+		if !m.active {
+			reason, ok = "professor inativo", true
+		}
+		if ok {
+			dropped = append(dropped, models.RolloverItem{TeacherName: m.TeacherName, Reason: reason + " (fora da equipe copiada)"})
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO offer_teachers (offer_id, teacher_id, role, hour_share)
+			VALUES ($1, $2, $3, $4)
+		`, offerID, m.TeacherID, m.Role, m.HourShare)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao copiar equipe da oferta %d: %w", sourceID, err)
+		}
+	}
+	return dropped, nil
 }
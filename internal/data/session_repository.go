@@ -0,0 +1,225 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"time"
+)
+
+type SessionRepository struct {
+	DB *sql.DB
+}
+
+const sessionSelect = `
+	SELECT cs.id, cs.offer_id, cs.session_date,
+	       to_char(cs.start_time, 'HH24:MI'), to_char(cs.end_time, 'HH24:MI'),
+	       COALESCE(cs.topic, ''), COALESCE(cs.notes, ''),
+	       (SELECT COUNT(*) FROM attendance_records ar WHERE ar.session_id = cs.id AND ar.hours_absent > 0),
+	       st.id, COALESCE(st.name, '')
+	FROM class_sessions cs
+	LEFT JOIN teachers st ON st.id = offer_teacher_on(cs.offer_id, cs.session_date)
+`
+
+func scanSession(row interface{ Scan(...any) error }) (*models.ClassSession, error) {
+	var s models.ClassSession
+	err := row.Scan(&s.ID, &s.OfferID, &s.Date, &s.StartTime, &s.EndTime, &s.Topic, &s.Notes, &s.Absentees, &s.TeacherID, &s.TeacherName)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SessionRepository) GetByOffer(offerID int) ([]models.ClassSession, error) {
+	rows, err := r.DB.Query(sessionSelect+` WHERE cs.offer_id = $1 ORDER BY cs.session_date, cs.start_time`, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aulas da oferta: %w", err)
+	}
+	return scanSessions(rows)
+}
+
+func scanSessions(rows *sql.Rows) ([]models.ClassSession, error) {
+	defer rows.Close()
+
+	var list []models.ClassSession
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear aula: %w", err)
+		}
+		list = append(list, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as aulas: %w", err)
+	}
+	return list, nil
+}
+
+func (r *SessionRepository) GetByID(id int) (*models.ClassSession, error) {
+	s, err := scanSession(r.DB.QueryRow(sessionSelect+` WHERE cs.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar aula: %w", err)
+	}
+	return s, nil
+}
+
+// GetByOfferDate lista as aulas da oferta na data, em ordem de horário
+func (r *SessionRepository) GetByOfferDate(offerID int, day time.Time) ([]models.ClassSession, error) {
+	query := sessionSelect + `
+		WHERE cs.offer_id = $1 AND cs.session_date = $2
+		ORDER BY cs.start_time
+	`
+
+	rows, err := r.DB.Query(query, offerID, day)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aulas: %w", err)
+	}
+	return scanSessions(rows)
+}
+
+func (r *SessionRepository) CountByOffer(offerID int) (int, error) {
+	var n int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM class_sessions WHERE offer_id = $1`, offerID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar aulas da oferta: %w", err)
+	}
+	return n, nil
+}
+
+// Sync grava as aulas geradas para a oferta. Aulas novas são inseridas; aulas antigas que não
+// fazem mais parte do horário são removidas, exceto as que já têm conteúdo ou chamada registrados.
+// Ao final a carga prevista da oferta passa a ser a soma das aulas.
+func (r *SessionRepository) Sync(offerID int, sessions []models.ClassSession) (created, removed int, err error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`CREATE TEMP TABLE generated_sessions (session_date DATE, start_time TIME, end_time TIME) ON COMMIT DROP`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao preparar geração de aulas: %w", err)
+	}
+
+	for _, s := range sessions {
+		_, err := tx.Exec(
+			`INSERT INTO generated_sessions VALUES ($1, $2, $3)`,
+			s.Date, s.StartTime, s.EndTime,
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("erro ao preparar geração de aulas: %w", err)
+		}
+	}
+
+	result, err := tx.Exec(`
+		DELETE FROM class_sessions cs
+		WHERE cs.offer_id = $1
+		  AND COALESCE(cs.topic, '') = ''
+		  AND COALESCE(cs.notes, '') = ''
+		  AND NOT EXISTS (SELECT 1 FROM attendance_records ar WHERE ar.session_id = cs.id)
+		  AND NOT EXISTS (
+		    SELECT 1 FROM generated_sessions g
+		    WHERE g.session_date = cs.session_date AND g.start_time = cs.start_time
+		  )
+	`, offerID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao remover aulas antigas: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	removed = int(n)
+
+	result, err = tx.Exec(`
+		INSERT INTO class_sessions (offer_id, session_date, start_time, end_time)
+		SELECT $1, g.session_date, g.start_time, g.end_time
+		FROM generated_sessions g
+		ON CONFLICT (offer_id, session_date, start_time) DO NOTHING
+	`, offerID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao inserir aulas: %w", err)
+	}
+	n, _ = result.RowsAffected()
+	created = int(n)
+
+	_, err = tx.Exec(sessionPlannedHoursUpdate, offerID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao atualizar carga prevista: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return created, removed, nil
+}
+
+// sessionPlannedHoursUpdate grava como carga prevista da oferta $1 a soma das suas aulas
+const sessionPlannedHoursUpdate = `
+	UPDATE discipline_offers
+	SET planned_hours = (
+	  SELECT ROUND(COALESCE(SUM(EXTRACT(EPOCH FROM (end_time - start_time))), 0) / 3600)
+	  FROM class_sessions WHERE offer_id = $1
+	)
+	WHERE id = $1
+`
+
+// RefreshPlannedHours recalcula a carga prevista da oferta a partir das aulas geradas, como no Sync
+func (r *SessionRepository) RefreshPlannedHours(offerID int) error {
+	_, err := r.DB.Exec(sessionPlannedHoursUpdate, offerID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar carga prevista da oferta %d: %w", offerID, err)
+	}
+	return nil
+}
+
+// UpdateDiary grava o conteúdo ministrado e as observações da aula
+func (r *SessionRepository) UpdateDiary(s *models.ClassSession) error {
+	result, err := r.DB.Exec(
+		`UPDATE class_sessions SET topic = $1, notes = $2 WHERE id = $3`,
+		s.Topic, s.Notes, s.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar diário: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("nenhuma aula encontrada com o ID %d", s.ID)
+	}
+	return nil
+}
+
+// SaveAttendance grava a chamada da aula em uma única transação.
+// Matrículas que não pertencem à oferta da aula são rejeitadas.
+func (r *SessionRepository) SaveAttendance(session *models.ClassSession, records []models.SessionAttendance) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rec := range records {
+		var offerID int
+		err := tx.QueryRow(`SELECT offer_id FROM registrations WHERE id = $1`, rec.RegistrationID).Scan(&offerID)
+		if err != nil || offerID != session.OfferID {
+			return fmt.Errorf("matrícula %d não pertence à oferta da aula", rec.RegistrationID)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO attendance_records (registration_id, session_id, class_date, hours_absent)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (registration_id, session_id)
+			DO UPDATE SET hours_absent = EXCLUDED.hours_absent
+		`, rec.RegistrationID, session.ID, session.Date, rec.HoursAbsent)
+		if err != nil {
+			return fmt.Errorf("erro ao lançar faltas da matrícula %d: %w", rec.RegistrationID, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -3,7 +3,10 @@ package data
 import (
 	"database/sql"
 	"fmt"
+	"sistema-faculdade/internal/academic"
 	"sistema-faculdade/internal/models"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -11,15 +14,29 @@ import (
 // Estrutura que vai fazer a conexão com o banco
 type StudentRepository struct {
 	DB *sql.DB
+	// Numbering é o formato das matrículas geradas (padrão academic.DefaultRegistrationFormat)
+	Numbering academic.RegistrationFormat
 }
 
 // GetAll recupera todos os estudantes ativos do banco de dados.
 func (r *StudentRepository) GetAll() ([]models.Student, error) {
+	// Inicializa uma slice vazia de estudantes que irá armazenar os resultados.
+	var students []models.Student
+	err := r.Each(func(s models.Student) error {
+		students = append(students, s)
+		return nil
+	})
+	return students, err
+}
+
+// Each percorre os estudantes na ordem do GetAll, um por vez, sem carregar a lista em memória
+// (usado pela exportação). Um erro de fn interrompe a leitura e é devolvido.
+func (r *StudentRepository) Each(fn func(models.Student) error) error {
 	// A query SQL para selecionar os estudantes.
 	// Lembre-se de usar parâmetros ($1, $2...) para evitar SQL Injection.
 	query := `
-		SELECT s.id, s.name, s.email, s.gender, s.date_birth, s.cpf, s.registration_number, s.active,
-		s.course_id, c.name as course_name, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.email, s.gender, s.date_birth, s.cpf, s.registration_number, s.active, s.status,
+		s.course_id, c.name as course_name, s.entry_semester_id, s.created_at, s.updated_at
 		FROM students s
 		LEFT JOIN courses c ON s.course_id = c.id
 		ORDER BY s.id DESC
@@ -30,14 +47,11 @@ func (r *StudentRepository) GetAll() ([]models.Student, error) {
 	// Ex: rows, err := r.DB.Query(query, courseID)
 	rows, err := r.DB.Query(query)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar estudantes: %w", err)
+		return fmt.Errorf("erro ao buscar estudantes: %w", err)
 	}
 	// O defer garante que rows.Close() seja chamado antes da função retornar, liberando a conexão com o banco.
 	defer rows.Close()
 
-	// Inicializa uma slice vazia de estudantes que irá armazenar os resultados.
-	var students []models.Student
-
 	// Itera sobre cada linha retornada pela consulta.
 	for rows.Next() {
 		var s models.Student
@@ -45,12 +59,12 @@ func (r *StudentRepository) GetAll() ([]models.Student, error) {
 		// rows.Scan, pega os dados do banco e coloca nas variaveis de s
 		err := rows.Scan(
 			&s.ID, &s.Name, &s.Email, &s.Gender, &s.DateBirth,
-			&s.CPF, &s.RegistrationNumber, &s.Active, &s.CourseID,
-			&courseName,
+			&s.CPF, &s.RegistrationNumber, &s.Active, &s.Status, &s.CourseID,
+			&courseName, &s.EntrySemesterID,
 			&s.CreatedAt, &s.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear estudante: %w", err)
+			return fmt.Errorf("erro ao escanear estudante: %w", err)
 		}
 
 		if courseName.Valid {
@@ -59,35 +73,73 @@ func (r *StudentRepository) GetAll() ([]models.Student, error) {
 			s.CourseName = "Curso não encontrado"
 		}
 
-		// Adiciona o estudante recém-escaneado à slice de estudantes.
-		students = append(students, s)
+		if err := fn(s); err != nil {
+			return err
+		}
 	}
 	// Após o loop, verifica se ocorreu algum erro durante a iteração.
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados dos estudantes: %w", err)
+		return fmt.Errorf("erro ao iterar sobre os resultados dos estudantes: %w", err)
 	}
 
-	return students, nil
+	return nil
 }
 
 // Create insere um novo estudante no banco de dados.
-func (r *StudentRepository) Create(s *models.Student) (int, error) {
-	// A query SQL para inserir um novo estudante.
+// Sem número de matrícula informado, o número é gerado pelo formato configurado.
+func (r *StudentRepository) Create(s *models.Student, changedBy string) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createStudent(tx, r.Numbering, s, changedBy, "Ingresso")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// createStudent também é usado pela confirmação do processo seletivo.
+// Números informados são conferidos por checkRegistrationNumber; os demais são aceitos como
+// matrículas legadas (importação de sistemas anteriores).
+func createStudent(tx *sql.Tx, numbering academic.RegistrationFormat, s *models.Student, changedBy, reason string) (int, error) {
+	numbering = numbering.OrDefault()
+	s.RegistrationNumber = strings.TrimSpace(s.RegistrationNumber)
+	if s.RegistrationNumber == "" {
+		number, err := nextRegistrationNumber(tx, numbering, s)
+		if err != nil {
+			return 0, err
+		}
+		s.RegistrationNumber = number
+	} else if err := checkRegistrationNumber(tx, numbering, s.RegistrationNumber); err != nil {
+		return 0, err
+	}
+
+	// O ingresso já fica registrado no histórico de situação do aluno.
 	query := `
-		INSERT INTO students (name, email, gender, date_birth, cpf, registration_number, course_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id
+		WITH new_student AS (
+			INSERT INTO students (name, email, gender, date_birth, cpf, registration_number, course_id, entry_semester_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		), history AS (
+			INSERT INTO student_status_history (student_id, from_status, to_status, reason, changed_by)
+			SELECT id, NULL, 'enrolled', $9, $10 FROM new_student
+		)
+		SELECT id FROM new_student
 	`
 
-	// Declara a variável que irá receber o ID retornado pelo banco de dados.
 	var id int
-	// QueryRow é usado aqui porque esperamos que a query retorne exatamente uma linha (o ID do novo estudante).
-	// Os argumentos seguintes a 'query' são os valores para os placeholders ($1, $2, ...).
-	err := r.DB.QueryRow(
+	err := tx.QueryRow(
 		query,
 		s.Name, s.Email, s.Gender, s.DateBirth,
-		s.CPF, s.RegistrationNumber, s.CourseID,
-	).Scan(&id) // O .Scan atribui o valor da coluna retornada (neste caso, 'id') para a variável 'id'.
+		s.CPF, s.RegistrationNumber, s.CourseID, s.EntrySemesterID, reason, changedBy,
+	).Scan(&id)
 
 	if err != nil {
 		// Verifica se o erro é o do tipo Postgres
@@ -112,12 +164,128 @@ func (r *StudentRepository) Create(s *models.Student) (int, error) {
 	return id, nil
 }
 
+// nextRegistrationNumber gera a próxima matrícula do escopo do aluno (ano e período de ingresso
+// e curso). O ano e o período vêm do semestre de ingresso ou, sem ele, do semestre regular em
+// andamento. Números já usados por matrículas legadas são pulados.
+func nextRegistrationNumber(tx *sql.Tx, numbering academic.RegistrationFormat, s *models.Student) (string, error) {
+	now := time.Now()
+	parts := academic.RegistrationParts{Year: now.Year(), Period: 1}
+	if now.Month() > time.June {
+		parts.Period = 2
+	}
+
+	var err error
+	if s.EntrySemesterID != nil {
+		err = tx.QueryRow(`SELECT year, period FROM academic_semesters WHERE id = $1`, *s.EntrySemesterID).
+			Scan(&parts.Year, &parts.Period)
+	} else {
+		err = tx.QueryRow(`
+			SELECT year, period FROM academic_semesters
+			WHERE kind = 'semester' AND CURRENT_DATE BETWEEN start_date AND end_date
+			ORDER BY start_date DESC
+			LIMIT 1
+		`).Scan(&parts.Year, &parts.Period)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("erro ao buscar semestre de ingresso: %w", err)
+	}
+
+	err = tx.QueryRow(`SELECT COALESCE(code, LPAD(id::text, 3, '0')) FROM courses WHERE id = $1`, s.CourseID).
+		Scan(&parts.Course)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("erro ao buscar curso do aluno: %w", err)
+	}
+
+	scope := numbering.Scope(parts)
+	for {
+		var seq int
+		err := tx.QueryRow(`
+			INSERT INTO registration_sequences (scope, last_value) VALUES ($1, 1)
+			ON CONFLICT (scope) DO UPDATE SET last_value = registration_sequences.last_value + 1
+			RETURNING last_value
+		`, scope).Scan(&seq)
+		if err != nil {
+			return "", fmt.Errorf("erro ao gerar matrícula: %w", err)
+		}
+
+		number := numbering.Build(parts, seq)
+		var used bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM students WHERE registration_number = $1)`, number).Scan(&used); err != nil {
+			return "", fmt.Errorf("erro ao gerar matrícula: %w", err)
+		}
+		if !used {
+			return number, nil
+		}
+	}
+}
+
+// checkRegistrationNumber confere o dígito verificador das matrículas emitidas pela numeração do
+// sistema: as que têm a forma do formato e caem em uma sequência já usada de registration_sequences.
+// Números legados com a mesma forma (só algarismos, por exemplo) são aceitos como informados.
+func checkRegistrationNumber(q interface {
+	Query(string, ...any) (*sql.Rows, error)
+}, numbering academic.RegistrationFormat, number string) error {
+	if !numbering.Matches(number) || numbering.Valid(number) {
+		return nil
+	}
+
+	rows, err := q.Query(`
+		SELECT scope, last_value
+		FROM registration_sequences
+		WHERE strpos(scope, '#') > 0 AND left($1, strpos(scope, '#') - 1) = split_part(scope, '#', 1)
+	`, number)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar sequências de matrícula: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var scope string
+		var last int
+		if err := rows.Scan(&scope, &last); err != nil {
+			return fmt.Errorf("erro ao escanear sequência de matrícula: %w", err)
+		}
+		if seq, ok := numbering.Sequence(number, scope); ok && seq >= 1 && seq <= last {
+			return fmt.Errorf("matrícula com dígito verificador inválido")
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao iterar sobre as sequências de matrícula: %w", err)
+	}
+	return nil
+}
+
+// GetByRegistrationNumber busca o aluno pela matrícula. Matrículas emitidas pelo sistema têm o
+// dígito verificador conferido antes da busca; matrículas legadas são buscadas como informadas.
+func (r *StudentRepository) GetByRegistrationNumber(number string) (*models.Student, error) {
+	number = strings.TrimSpace(number)
+	if err := checkRegistrationNumber(r.DB, r.Numbering.OrDefault(), number); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err := r.DB.QueryRow(`SELECT id FROM students WHERE registration_number = $1`, number).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar estudante: %w", err)
+	}
+	return r.GetByID(id)
+}
+
 func (r *StudentRepository) Update(s *models.Student) error {
+	s.RegistrationNumber = strings.TrimSpace(s.RegistrationNumber)
+	if err := checkRegistrationNumber(r.DB, r.Numbering.OrDefault(), s.RegistrationNumber); err != nil {
+		return err
+	}
 
+	// O curso só muda pela transferência de curso, que registra o aproveitamento das disciplinas
 	query := `
 		Update students
-		SET name = $1, email = $2, gender = $3, date_birth = $4, cpf = $5, registration_number = $6, course_id = $7, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $8
+		SET name = $1, email = $2, gender = $3, date_birth = $4, cpf = $5, registration_number = COALESCE(NULLIF($6, ''), registration_number), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7
 	`
 	// Executa a query de atualização e escaneia o ID retornado para a variável idReturned.
 	result, err := r.DB.Exec(
@@ -128,7 +296,6 @@ func (r *StudentRepository) Update(s *models.Student) error {
 		s.DateBirth,
 		s.CPF,
 		s.RegistrationNumber,
-		s.CourseID,
 		s.ID,
 	)
 	if err != nil {
@@ -147,65 +314,97 @@ func (r *StudentRepository) Update(s *models.Student) error {
 	return nil
 }
 
-func (r *StudentRepository) Delete(id int) error {
-	// A query SQL para inativar um estudante.
-	// Não devemos excluir o aluno, somente defini-lo como desativado
-	query := `
-		UPDATE students
-		SET active = FALSE, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-	`
+// ChangeStatus registra a transição de situação do aluno e a grava no histórico.
+// Inativar e reativar o aluno também passam por aqui.
+func (r *StudentRepository) ChangeStatus(c *models.StudentStatusChange) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
 
-	// r.DB.Exec, diferente do QueryRom, o Exec é quando damos um comando no banco
-	// mas não esperamos recever dados de volta
-	result, err := r.DB.Exec(query, id)
+	if err := changeStudentStatus(tx, c); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// changeStudentStatus é usado dentro das transações de outros fluxos (trancamento, transferência...)
+func changeStudentStatus(tx *sql.Tx, c *models.StudentStatusChange) error {
+	var current string
+	err := tx.QueryRow(`SELECT status FROM students WHERE id = $1 FOR UPDATE`, c.StudentID).Scan(&current)
 	if err != nil {
-		return fmt.Errorf("erro ao executar a inativação do estudante com ID %d: %w", id, err)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("nenhum aluno encontrado com o ID %d", c.StudentID)
+		}
+		return fmt.Errorf("erro ao buscar situação do aluno: %w", err)
 	}
 
-	// O banco avisa quantas linhas teve alteração, se voltar no 0 (primeira linha)
-	// rodou sem erros
-	rowsAffected, err := result.RowsAffected()
+	if !models.CanTransition(current, c.ToStatus) {
+		return fmt.Errorf("transição inválida: %s → %s", current, c.ToStatus)
+	}
+	c.FromStatus = &current
+
+	_, err = tx.Exec(`
+		UPDATE students
+		SET status = $1, active = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`, c.ToStatus, models.StudentIsActive(c.ToStatus), c.StudentID)
 	if err != nil {
-		return fmt.Errorf("erro ao verificar as linhas afetadas pela inativação: %w", err)
+		return fmt.Errorf("erro ao atualizar situação do aluno: %w", err)
 	}
 
-	// Se nenhuma linha foi afetada, significa que o ID do estudante não foi encontrado.
-	if rowsAffected == 0 {
-		return fmt.Errorf("nenhum aluno encontrado com o ID %d", id)
+	effective := c.EffectiveDate
+	if effective.IsZero() {
+		effective = time.Now()
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO student_status_history (student_id, from_status, to_status, reason, changed_by, effective_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, effective_date, created_at
+	`, c.StudentID, current, c.ToStatus, c.Reason, c.ChangedBy, effective).Scan(&c.ID, &c.EffectiveDate, &c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar histórico de situação: %w", err)
 	}
 
 	return nil
 }
 
-func (r *StudentRepository) Activate(id int) error {
+func (r *StudentRepository) StatusHistory(studentID int) ([]models.StudentStatusChange, error) {
 	query := `
-		UPDATE students
-		SET active = TRUE, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
+		SELECT id, student_id, from_status, to_status, reason, changed_by, effective_date, created_at
+		FROM student_status_history
+		WHERE student_id = $1
+		ORDER BY effective_date ASC, id ASC
 	`
 
-	result, err := r.DB.Exec(query, id)
+	rows, err := r.DB.Query(query, studentID)
 	if err != nil {
-		return fmt.Errorf("erro ao reativar estudante: %w", err)
+		return nil, fmt.Errorf("erro ao buscar histórico de situação: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	var list []models.StudentStatusChange
+	for rows.Next() {
+		var c models.StudentStatusChange
+		err := rows.Scan(&c.ID, &c.StudentID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.ChangedBy, &c.EffectiveDate, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear histórico de situação: %w", err)
+		}
+		list = append(list, c)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("nenhum aluno encontrado com o ID %d", id)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre o histórico de situação: %w", err)
 	}
-
-	return nil
+	return list, nil
 }
 
 func (r *StudentRepository) GetByID(id int) (*models.Student, error) {
 	query := `
-		SELECT s.id, s.name, s.email, s.gender, s.date_birth, s.cpf, s.registration_number, s.active,
-		s.course_id, c.name as course_name, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.email, s.gender, s.date_birth, s.cpf, s.registration_number, s.active, s.status,
+		s.course_id, c.name as course_name, s.entry_semester_id, s.created_at, s.updated_at
 		FROM students s
 		LEFT JOIN courses c ON s.course_id = c.id
 		WHERE s.id = $1
@@ -216,8 +415,8 @@ func (r *StudentRepository) GetByID(id int) (*models.Student, error) {
 
 	err := r.DB.QueryRow(query, id).Scan(
 		&s.ID, &s.Name, &s.Email, &s.Gender, &s.DateBirth,
-		&s.CPF, &s.RegistrationNumber, &s.Active, &s.CourseID,
-		&courseName,
+		&s.CPF, &s.RegistrationNumber, &s.Active, &s.Status, &s.CourseID,
+		&courseName, &s.EntrySemesterID,
 		&s.CreatedAt, &s.UpdatedAt,
 	)
 
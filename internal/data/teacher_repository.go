@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sistema-faculdade/internal/models"
+	"strings"
 
 	"github.com/lib/pq"
 )
@@ -13,9 +14,20 @@ type TeacherRepository struct {
 }
 
 func (r *TeacherRepository) GetAll() ([]models.Teacher, error) {
+	var teachers []models.Teacher
+	err := r.Each(func(t models.Teacher) error {
+		teachers = append(teachers, t)
+		return nil
+	})
+	return teachers, err
+}
+
+// Each percorre os professores um por vez, na ordem do GetAll (usado pela exportação)
+func (r *TeacherRepository) Each(fn func(models.Teacher) error) error {
 	query := `
 		SELECT t.id, t.name, t.email, t.cpf, t.telephone, t.active, 
-		t.department_id, d.name as department_name, t.date_contract, 
+		t.department_id, d.name as department_name, t.date_contract, t.contract_hours,
+		t.contract_regime, t.academic_title, t.expertise_areas, COALESCE(t.preferred_schedule, ''),
 		t.created_at, t.updated_at
 		FROM teachers t
 		LEFT JOIN departments d ON t.department_id = d.id
@@ -24,12 +36,10 @@ func (r *TeacherRepository) GetAll() ([]models.Teacher, error) {
 
 	rows, err := r.DB.Query(query)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar professores: %w", err)
+		return fmt.Errorf("erro ao buscar professores: %w", err)
 	}
 	defer rows.Close()
 
-	var teachers []models.Teacher
-
 	for rows.Next() {
 		var t models.Teacher
 		var departmentName sql.NullString
@@ -37,10 +47,11 @@ func (r *TeacherRepository) GetAll() ([]models.Teacher, error) {
 			&t.ID, &t.Name, &t.Email, &t.CPF,
 			&t.Telephone, &t.Active, &t.DepartmentID,
 			&departmentName,
-			&t.DateContract, &t.CreatedAt, &t.UpdatedAt,
+			&t.DateContract, &t.ContractHours, &t.ContractRegime, &t.AcademicTitle,
+			(*pq.StringArray)(&t.ExpertiseAreas), &t.PreferredSchedule, &t.CreatedAt, &t.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear professor: %w", err)
+			return fmt.Errorf("erro ao escanear professor: %w", err)
 		}
 
 		if departmentName.Valid {
@@ -49,29 +60,53 @@ func (r *TeacherRepository) GetAll() ([]models.Teacher, error) {
 			t.DepartmentName = "Departamento não encontrado"
 		}
 
-		teachers = append(teachers, t)
+		if err := fn(t); err != nil {
+			return err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados dos professores: %w", err)
+		return fmt.Errorf("erro ao iterar sobre os resultados dos professores: %w", err)
+	}
+
+	return nil
+}
+
+// Create cadastra o professor e abre o primeiro registro do histórico contratual
+func (r *TeacherRepository) Create(t *models.Teacher, createdBy string) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
+	defer tx.Rollback()
 
-	return teachers, nil
+	id, err := createTeacher(tx, t, createdBy)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
-func (r *TeacherRepository) Create(t *models.Teacher) (int, error) {
+// createTeacher também é usado pela importação em lote
+func createTeacher(tx *sql.Tx, t *models.Teacher, createdBy string) (int, error) {
 	query := `
-		INSERT INTO teachers (name, email, cpf, telephone, department_id, date_contract)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO teachers (name, email, cpf, telephone, department_id, date_contract, contract_hours,
+		                      contract_regime, academic_title, expertise_areas, preferred_schedule)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''))
 		RETURNING id
 	`
 
 	var id int
 
-	err := r.DB.QueryRow(
+	err := tx.QueryRow(
 		query,
 		t.Name, t.Email, t.CPF,
-		t.Telephone, t.DepartmentID, t.DateContract,
+		t.Telephone, t.DepartmentID, t.DateContract, t.ContractHours,
+		t.ContractRegime, t.AcademicTitle, pq.StringArray(expertiseAreas(t.ExpertiseAreas)), t.PreferredSchedule,
 	).Scan(&id)
 
 	if err != nil {
@@ -88,17 +123,52 @@ func (r *TeacherRepository) Create(t *models.Teacher) (int, error) {
 		return 0, fmt.Errorf("erro ao criar professor: %w", err)
 	}
 
+	_, err = tx.Exec(`
+		INSERT INTO teacher_contracts (teacher_id, regime, contract_hours, start_date, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`, id, t.ContractRegime, t.ContractHours, t.DateContract, createdBy)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao registrar contrato do professor: %w", err)
+	}
 	return id, nil
 }
 
-func (r *TeacherRepository) Update(t *models.Teacher) error {
+// Update atualiza o professor. Se o regime ou as horas do contrato mudarem, o contrato
+// vigente é encerrado e um novo registro é aberto no histórico.
+func (r *TeacherRepository) Update(t *models.Teacher, changedBy string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateTeacher(tx, t, changedBy); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateTeacher(tx *sql.Tx, t *models.Teacher, changedBy string) error {
+	var current models.Teacher
+	err := tx.QueryRow(
+		`SELECT contract_regime, contract_hours FROM teachers WHERE id = $1 FOR UPDATE`, t.ID,
+	).Scan(&current.ContractRegime, &current.ContractHours)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("nenhum professor encontrado com o ID %d", t.ID)
+		}
+		return fmt.Errorf("erro ao buscar professor: %w", err)
+	}
+
 	query := `
 		Update teachers
-		SET name = $1, email = $2, cpf = $3, telephone = $4, department_id = $5, date_contract = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $7
+		SET name = $1, email = $2, cpf = $3, telephone = $4, department_id = $5, date_contract = $6, contract_hours = $7,
+		    contract_regime = $8, academic_title = $9, expertise_areas = $10, preferred_schedule = NULLIF($11, ''),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $12
 	`
 
-	result, err := r.DB.Exec(
+	_, err = tx.Exec(
 		query,
 		t.Name,
 		t.Email,
@@ -106,28 +176,66 @@ func (r *TeacherRepository) Update(t *models.Teacher) error {
 		t.Telephone,
 		t.DepartmentID,
 		t.DateContract,
+		t.ContractHours,
+		t.ContractRegime,
+		t.AcademicTitle,
+		pq.StringArray(expertiseAreas(t.ExpertiseAreas)),
+		t.PreferredSchedule,
 		t.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar professor: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	if !sameValue(current.ContractRegime, t.ContractRegime) || !sameValue(current.ContractHours, t.ContractHours) {
+		_, err = tx.Exec(`
+			UPDATE teacher_contracts
+			SET end_date = GREATEST(start_date, CURRENT_DATE - 1)
+			WHERE teacher_id = $1 AND end_date IS NULL
+		`, t.ID)
+		if err != nil {
+			return fmt.Errorf("erro ao encerrar contrato vigente: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO teacher_contracts (teacher_id, regime, contract_hours, start_date, changed_by)
+			VALUES ($1, $2, $3, CURRENT_DATE, $4)
+		`, t.ID, t.ContractRegime, t.ContractHours, changedBy)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar contrato do professor: %w", err)
+		}
 	}
+	return nil
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("nenhum professor encontrado com o ID %d", t.ID)
+func sameValue[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
+	return *a == *b
+}
 
-	return nil
+// expertiseAreas remove áreas vazias e repetidas
+func expertiseAreas(areas []string) []string {
+	list := []string{}
+	seen := map[string]bool{}
+	for _, a := range areas {
+		a = strings.TrimSpace(a)
+		key := strings.ToLower(a)
+		if a == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, a)
+	}
+	return list
 }
 
 func (r *TeacherRepository) GetByID(id int) (*models.Teacher, error) {
 	query := `
 		SELECT t.id, t.name, t.email, t.cpf, t.telephone, t.active, 
-		t.department_id, d.name as department_name,t.date_contract, 
+		t.department_id, d.name as department_name,t.date_contract, t.contract_hours,
+		t.contract_regime, t.academic_title, t.expertise_areas, COALESCE(t.preferred_schedule, ''),
 		t.created_at, t.updated_at
 		FROM teachers t
 		LEFT JOIN departments d ON t.department_id = d.id
@@ -141,7 +249,8 @@ func (r *TeacherRepository) GetByID(id int) (*models.Teacher, error) {
 		&t.ID, &t.Name, &t.Email, &t.CPF,
 		&t.Telephone, &t.Active, &t.DepartmentID,
 		&departmentName,
-		&t.DateContract, &t.CreatedAt, &t.UpdatedAt,
+		&t.DateContract, &t.ContractHours, &t.ContractRegime, &t.AcademicTitle,
+		(*pq.StringArray)(&t.ExpertiseAreas), &t.PreferredSchedule, &t.CreatedAt, &t.UpdatedAt,
 	)
 
 	if err != nil {
@@ -207,3 +316,145 @@ func (r *TeacherRepository) Activate(id int) error {
 
 	return nil
 }
+
+// GetContracts retorna o histórico contratual do professor, do mais recente para o mais antigo
+func (r *TeacherRepository) GetContracts(teacherID int) ([]models.TeacherContract, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, teacher_id, regime, contract_hours, start_date, end_date, changed_by, created_at
+		FROM teacher_contracts
+		WHERE teacher_id = $1
+		ORDER BY start_date DESC, id DESC
+	`, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico contratual: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.TeacherContract{}
+	for rows.Next() {
+		var c models.TeacherContract
+		err := rows.Scan(&c.ID, &c.TeacherID, &c.Regime, &c.ContractHours, &c.StartDate, &c.EndDate, &c.ChangedBy, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear contrato: %w", err)
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre o histórico contratual: %w", err)
+	}
+	return list, nil
+}
+
+func (r *TeacherRepository) GetDisciplines(teacherID int) ([]models.TeacherDiscipline, error) {
+	rows, err := r.DB.Query(`
+		SELECT td.teacher_id, d.id, d.name, d.code
+		FROM teacher_disciplines td
+		JOIN disciplines d ON d.id = td.discipline_id
+		WHERE td.teacher_id = $1
+		ORDER BY d.name
+	`, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar disciplinas do professor: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.TeacherDiscipline{}
+	for rows.Next() {
+		var d models.TeacherDiscipline
+		if err := rows.Scan(&d.TeacherID, &d.DisciplineID, &d.DisciplineName, &d.DisciplineCode); err != nil {
+			return nil, fmt.Errorf("erro ao escanear disciplina do professor: %w", err)
+		}
+		list = append(list, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as disciplinas do professor: %w", err)
+	}
+	return list, nil
+}
+
+// AddDiscipline habilita o professor a lecionar a disciplina; repetir a habilitação não é erro
+func (r *TeacherRepository) AddDiscipline(teacherID, disciplineID int) error {
+	_, err := r.DB.Exec(`
+		INSERT INTO teacher_disciplines (teacher_id, discipline_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, teacherID, disciplineID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			return fmt.Errorf("professor ou disciplina inexistente")
+		}
+		return fmt.Errorf("erro ao habilitar professor na disciplina: %w", err)
+	}
+	return nil
+}
+
+func (r *TeacherRepository) RemoveDiscipline(teacherID, disciplineID int) error {
+	result, err := r.DB.Exec(
+		`DELETE FROM teacher_disciplines WHERE teacher_id = $1 AND discipline_id = $2`,
+		teacherID, disciplineID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao remover habilitação do professor: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("professor não habilitado na disciplina %d", disciplineID)
+	}
+	return nil
+}
+
+// IsQualified indica se o professor está habilitado a lecionar a disciplina
+func (r *TeacherRepository) IsQualified(teacherID, disciplineID int) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM teacher_disciplines WHERE teacher_id = $1 AND discipline_id = $2)
+	`, teacherID, disciplineID).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar habilitação do professor: %w", err)
+	}
+	return ok, nil
+}
+
+// Candidates lista os professores ativos habilitados a lecionar a disciplina
+func (r *TeacherRepository) Candidates(disciplineID int) ([]models.TeacherCandidate, error) {
+	rows, err := r.DB.Query(`
+		SELECT t.id, t.name, t.department_id, COALESCE(dep.name, 'Sem departamento'), t.academic_title,
+		       t.contract_hours, COALESCE(t.preferred_schedule, ''),
+		       COALESCE(t.department_id = d.department_id, FALSE)
+		FROM teacher_disciplines td
+		JOIN teachers t ON t.id = td.teacher_id
+		JOIN disciplines d ON d.id = td.discipline_id
+		LEFT JOIN departments dep ON dep.id = t.department_id
+		WHERE td.discipline_id = $1 AND t.active
+		ORDER BY t.name
+	`, disciplineID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar professores habilitados: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.TeacherCandidate{}
+	for rows.Next() {
+		var c models.TeacherCandidate
+		err := rows.Scan(
+			&c.TeacherID, &c.TeacherName, &c.DepartmentID, &c.DepartmentName, &c.AcademicTitle,
+			&c.ContractHours, &c.PreferredSchedule, &c.SameDepartment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear professor habilitado: %w", err)
+		}
+		c.Conflicts = []string{}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os professores habilitados: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+)
+
+type TranscriptRepository struct {
+	DB *sql.DB
+}
+
+// Get monta o histórico escolar do aluno. Todas as matrículas aparecem, inclusive as
+// cursadas antes de uma transferência, junto com os aproveitamentos aprovados;
+// a classificação é feita pela matriz do curso atual, considerando as equivalências.
+// Os créditos integralizados somam cada disciplina da matriz uma única vez.
+func (r *TranscriptRepository) Get(studentID int) (*models.Transcript, error) {
+	var t models.Transcript
+	var courseID sql.NullInt64
+	var courseName sql.NullString
+
+	err := r.DB.QueryRow(`
+		SELECT s.id, s.name, s.registration_number, s.course_id, c.name
+		FROM students s
+		LEFT JOIN courses c ON c.id = s.course_id
+		WHERE s.id = $1
+	`, studentID).Scan(&t.StudentID, &t.StudentName, &t.RegistrationNumber, &courseID, &courseName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar aluno: %w", err)
+	}
+	t.CourseID = int(courseID.Int64)
+	t.CourseName = courseName.String
+
+	// Disciplinas cursadas na instituição e disciplinas aproveitadas de outras instituições
+	query := `
+		SELECT r.id, semester_label(sem.kind, sem.year, sem.period), d.id, d.code, d.name, d.credits, d.workload_hours,
+		       r.final_grade, r.frequency, r.status::text, 'taken', NULL::text,
+		       counts_for_curriculum($1, d.id, $2), sem.year, sem.start_date
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		JOIN academic_semesters sem ON sem.id = o.semester_id
+		JOIN disciplines d ON d.id = o.discipline_id
+		WHERE r.student_id = $1
+		UNION ALL
+		SELECT NULL, e.completed_year::text, d.id, d.code, d.name, d.credits, d.workload_hours,
+		       e.grade, NULL, 'approved', 'transferred', e.institution,
+		       counts_for_curriculum($1, d.id, $2), e.completed_year, NULL::date
+		FROM external_credits e
+		JOIN disciplines d ON d.id = e.discipline_id
+		WHERE e.student_id = $1 AND e.status = 'approved'
+		ORDER BY 14, 15 NULLS FIRST, 5
+	`
+
+	rows, err := r.DB.Query(query, studentID, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico escolar: %w", err)
+	}
+	defer rows.Close()
+
+	earned := map[int]bool{}
+	for rows.Next() {
+		var e models.TranscriptEntry
+		var counts bool
+		var year int
+		var start sql.NullTime
+		err := rows.Scan(
+			&e.RegistrationID, &e.Semester, &e.DisciplineID, &e.DisciplineCode, &e.DisciplineName, &e.Credits, &e.WorkloadHours,
+			&e.FinalGrade, &e.Frequency, &e.Status, &e.Origin, &e.Institution, &counts, &year, &start,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear histórico escolar: %w", err)
+		}
+		e.Classification = models.CreditFreeElective
+		if counts {
+			e.Classification = models.CreditCurriculum
+		}
+		// Disciplina aprovada duas vezes, ou cursada e também aproveitada, conta uma vez só;
+		// optativas livres de um curso anterior não integralizam o curso atual
+		if e.Status == "approved" && counts && !earned[e.DisciplineID] {
+			earned[e.DisciplineID] = true
+			t.EarnedCredits += e.Credits
+			t.EarnedWorkload += e.WorkloadHours
+		}
+		t.Entries = append(t.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre o histórico escolar: %w", err)
+	}
+
+	t.Transfers, err = studentTransfers(r.DB, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FulfilledDisciplines retorna as disciplinas cumpridas pelo aluno: aprovadas, aproveitadas ou cumpridas por equivalência
+func (r *TranscriptRepository) FulfilledDisciplines(studentID int) (map[int]bool, error) {
+	return r.disciplineSet(`SELECT discipline_id FROM student_fulfilled_disciplines WHERE student_id = $1`, studentID)
+}
+
+// InProgressDisciplines retorna as disciplinas em que o aluno está matriculado e ainda não concluiu
+func (r *TranscriptRepository) InProgressDisciplines(studentID int) (map[int]bool, error) {
+	return r.disciplineSet(`
+		SELECT o.discipline_id
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		WHERE r.student_id = $1 AND r.status IN ('in_progress', 'take_test')
+	`, studentID)
+}
+
+// SemesterRegistrations retorna as ofertas do semestre em que o aluno já está matriculado, com créditos e horário
+func (r *TranscriptRepository) SemesterRegistrations(studentID, semesterID int) ([]academic.Candidate, error) {
+	rows, err := r.DB.Query(`
+		SELECT o.id, d.id, d.credits, o.schedule
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		JOIN disciplines d ON d.id = o.discipline_id
+		WHERE r.student_id = $1 AND o.semester_id = $2 AND r.status <> 'dropped'
+	`, studentID, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar matrículas do semestre: %w", err)
+	}
+	defer rows.Close()
+
+	var list []academic.Candidate
+	for rows.Next() {
+		var c academic.Candidate
+		if err := rows.Scan(&c.OfferID, &c.DisciplineID, &c.Credits, &c.Schedule); err != nil {
+			return nil, fmt.Errorf("erro ao escanear matrícula do semestre: %w", err)
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as matrículas do semestre: %w", err)
+	}
+	return list, nil
+}
+
+func (r *TranscriptRepository) disciplineSet(query string, studentID int) (map[int]bool, error) {
+	rows, err := r.DB.Query(query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar disciplinas do aluno: %w", err)
+	}
+	defer rows.Close()
+
+	set := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao escanear disciplina do aluno: %w", err)
+		}
+		set[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as disciplinas do aluno: %w", err)
+	}
+	return set, nil
+}
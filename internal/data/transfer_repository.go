@@ -0,0 +1,159 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sistema-faculdade/internal/models"
+)
+
+type TransferRepository struct {
+	DB *sql.DB
+}
+
+// Transfer troca o curso do aluno. As matrículas aprovadas continuam no histórico e são
+// aproveitadas no novo curso: as que fazem parte da matriz (diretamente ou por equivalência)
+// contam como disciplina do curso, as demais como optativa livre.
+func (r *TransferRepository) Transfer(t *models.CourseTransfer) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(
+		`SELECT course_id, status FROM students WHERE id = $1 FOR UPDATE`, t.StudentID,
+	).Scan(&t.FromCourseID, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("nenhum aluno encontrado com o ID %d", t.StudentID)
+		}
+		return fmt.Errorf("erro ao buscar aluno: %w", err)
+	}
+
+	if status != models.StudentEnrolled {
+		return fmt.Errorf("somente alunos matriculados podem trocar de curso")
+	}
+	if t.FromCourseID != nil && *t.FromCourseID == t.ToCourseID {
+		return fmt.Errorf("o aluno já pertence a este curso")
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO course_transfers (student_id, from_course_id, to_course_id, reason, transferred_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, transferred_at
+	`, t.StudentID, t.FromCourseID, t.ToCourseID, t.Reason, t.TransferredBy).Scan(&t.ID, &t.TransferredAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar transferência: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO course_transfer_credits (transfer_id, registration_id, discipline_id, classification)
+		SELECT $1, r.id, o.discipline_id,
+		       CASE WHEN counts_for_curriculum($2, o.discipline_id, $3) THEN 'curriculum' ELSE 'free_elective' END
+		FROM registrations r
+		JOIN discipline_offers o ON o.id = r.offer_id
+		WHERE r.student_id = $2 AND r.status = 'approved'
+	`, t.ID, t.StudentID, t.ToCourseID)
+	if err != nil {
+		return fmt.Errorf("erro ao aproveitar disciplinas: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`UPDATE students SET course_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		t.ToCourseID, t.StudentID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar curso do aluno: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	credits, err := transferCredits(r.DB, t.ID)
+	if err != nil {
+		return err
+	}
+	t.Credits = credits
+	return nil
+}
+
+func (r *TransferRepository) GetByStudent(studentID int) ([]models.CourseTransfer, error) {
+	return studentTransfers(r.DB, studentID)
+}
+
+// studentTransfers também é usado na montagem do histórico escolar
+func studentTransfers(db *sql.DB, studentID int) ([]models.CourseTransfer, error) {
+	query := `
+		SELECT t.id, t.student_id, t.from_course_id, fc.name, t.to_course_id, tc.name,
+		       t.reason, t.transferred_by, t.transferred_at
+		FROM course_transfers t
+		LEFT JOIN courses fc ON fc.id = t.from_course_id
+		JOIN courses tc ON tc.id = t.to_course_id
+		WHERE t.student_id = $1
+		ORDER BY t.transferred_at ASC
+	`
+
+	rows, err := db.Query(query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar transferências: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.CourseTransfer
+	for rows.Next() {
+		var t models.CourseTransfer
+		err := rows.Scan(
+			&t.ID, &t.StudentID, &t.FromCourseID, &t.FromCourseName, &t.ToCourseID, &t.ToCourseName,
+			&t.Reason, &t.TransferredBy, &t.TransferredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear transferência: %w", err)
+		}
+		list = append(list, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as transferências: %w", err)
+	}
+	rows.Close()
+
+	for i := range list {
+		credits, err := transferCredits(db, list[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		list[i].Credits = credits
+	}
+	return list, nil
+}
+
+func transferCredits(db *sql.DB, transferID int) ([]models.TransferCredit, error) {
+	query := `
+		SELECT c.registration_id, d.id, d.name, d.credits, c.classification
+		FROM course_transfer_credits c
+		JOIN disciplines d ON d.id = c.discipline_id
+		WHERE c.transfer_id = $1
+		ORDER BY d.name
+	`
+
+	rows, err := db.Query(query, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar disciplinas aproveitadas: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.TransferCredit
+	for rows.Next() {
+		var c models.TransferCredit
+		if err := rows.Scan(&c.RegistrationID, &c.DisciplineID, &c.DisciplineName, &c.Credits, &c.Classification); err != nil {
+			return nil, fmt.Errorf("erro ao escanear disciplina aproveitada: %w", err)
+		}
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as disciplinas aproveitadas: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,176 @@
+// Package diploma gera o XML do diploma digital, confere o XML contra o XSD do leiaute e o assina
+// com a assinatura XMLDSig envelopada da instituição.
+package diploma
+
+import (
+	"bytes"
+	_ "embed"
+	"sistema-faculdade/internal/models"
+	"sistema-faculdade/internal/signing"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Namespace do leiaute do diploma
+const Namespace = "urn:sistema-faculdade:diploma:1.0"
+
+//go:embed diploma.xsd
+var schemaXSD []byte
+
+// XSD é o schema do leiaute, publicado para a validação fora do sistema
+func XSD() []byte {
+	return schemaXSD
+}
+
+var schema = mustParseSchema()
+
+func mustParseSchema() *Schema {
+	s, err := ParseSchema(schemaXSD)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Data reúne os dados do diploma
+type Data struct {
+	Code           string
+	RegistryNumber string
+	Institution    string
+	VerifyURL      string
+	GraduationDate time.Time
+	IssuedAt       time.Time
+	Student        *models.Student
+	Course         *models.Course
+	Transcript     *models.Transcript
+}
+
+// Generate monta o XML do diploma, confere contra o XSD e assina. Do histórico entram as disciplinas
+// aprovadas e as aproveitadas de outras instituições.
+func Generate(d Data, signer *signing.Keyring) ([]byte, error) {
+	root := build(d)
+	if err := schema.validateTree(root); err != nil {
+		return nil, err
+	}
+
+	root.indent(0)
+	if err := sign(root, signer); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	root.canonical(&buf, "", nil)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func build(d Data) *node {
+	el := func(local string, children ...*node) *node { return element(Namespace, local, children...) }
+	text := func(local, value string) *node { return textElement(Namespace, local, value) }
+
+	student := el("Diplomado",
+		text("Matricula", d.Student.RegistrationNumber),
+		text("Nome", d.Student.Name),
+	)
+	if d.Student.Gender != "" {
+		student.Children = append(student.Children, text("Sexo", d.Student.Gender))
+	}
+	student.Children = append(student.Children,
+		text("CPF", digits(d.Student.CPF)),
+		text("DataNascimento", d.Student.DateBirth.Format("2006-01-02")),
+	)
+
+	history := el("HistoricoEscolar")
+	for _, e := range d.Transcript.Entries {
+		if e.Status != "approved" {
+			continue
+		}
+
+		status := "Aprovado"
+		if e.Origin == models.OriginTransferred {
+			status = "Aproveitado"
+		}
+		discipline := el("Disciplina",
+			text("Codigo", e.DisciplineCode),
+			text("Nome", e.DisciplineName),
+			text("Periodo", e.Semester),
+			text("CargaHoraria", strconv.Itoa(e.WorkloadHours)),
+			text("Creditos", strconv.Itoa(e.Credits)),
+		)
+		if e.FinalGrade != nil {
+			discipline.Children = append(discipline.Children, text("Nota", strconv.FormatFloat(*e.FinalGrade, 'f', 2, 64)))
+		}
+		discipline.Children = append(discipline.Children, text("Situacao", status))
+		if e.Institution != nil {
+			discipline.Children = append(discipline.Children, text("IesOrigem", *e.Institution))
+		}
+		history.Children = append(history.Children, discipline)
+	}
+
+	course := el("DadosCurso", text("NomeCurso", d.Course.Name))
+	if d.Course.Code != nil {
+		course.Children = append(course.Children, text("CodigoCurso", *d.Course.Code))
+	}
+	course.Children = append(course.Children,
+		text("CargaHorariaIntegralizada", strconv.Itoa(d.Transcript.EarnedWorkload)),
+		text("CreditosIntegralizados", strconv.Itoa(d.Transcript.EarnedCredits)),
+	)
+
+	registry := el("DadosRegistro",
+		text("CodigoValidacao", d.Code),
+		text("NumeroRegistro", d.RegistryNumber),
+		text("DataColacaoGrau", d.GraduationDate.Format("2006-01-02")),
+		text("DataExpedicao", d.IssuedAt.Format("2006-01-02")),
+	)
+	if d.VerifyURL != "" {
+		registry.Children = append(registry.Children, text("URLVerificacao", d.VerifyURL))
+	}
+
+	return el("Diploma",
+		el("infDiploma",
+			el("DadosDiploma", student, course, el("IesEmissora", text("Nome", d.Institution))),
+			registry,
+			history,
+		),
+	).attr("versao", "1.0")
+}
+
+// Info são os dados de identificação lidos de um diploma
+type Info struct {
+	Code           string
+	RegistryNumber string
+	StudentName    string
+	CourseName     string
+	// KeyID é a chave indicada na assinatura
+	KeyID string
+}
+
+// readInfo extrai os dados de um diploma já conferido contra o XSD
+func readInfo(root *node) *Info {
+	inf := root.child("infDiploma")
+	registry := inf.child("DadosRegistro")
+	diploma := inf.child("DadosDiploma")
+	return &Info{
+		Code:           registry.child("CodigoValidacao").text(),
+		RegistryNumber: registry.child("NumeroRegistro").text(),
+		StudentName:    diploma.child("Diplomado").child("Nome").text(),
+		CourseName:     diploma.child("DadosCurso").child("NomeCurso").text(),
+	}
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// Validate confere o XML contra o XSD do leiaute
+func Validate(data []byte) error {
+	return schema.Validate(data)
+}
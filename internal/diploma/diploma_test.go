@@ -0,0 +1,13 @@
+package diploma
+
+import "testing"
+
+// As notas do sistema vão de 0 a 100 com aprovação a partir de 60 (grade_items.grade); um TNota
+// em outra escala recusaria a emissão dos diplomas
+func TestSchemaAcceptsGradeScale(t *testing.T) {
+	for _, grade := range []string{"0.00", "60.00", "100.00"} {
+		if err := schema.checkValue(grade, "tns:TNota", nil); err != nil {
+			t.Errorf("TNota não aceita a nota %s: %v", grade, err)
+		}
+	}
+}
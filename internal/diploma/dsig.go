@@ -0,0 +1,187 @@
+package diploma
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"sistema-faculdade/internal/signing"
+	"strings"
+)
+
+// Assinatura XMLDSig envelopada: a referência é o documento inteiro (URI ""), sem o próprio elemento
+// Signature, na forma canônica exclusiva, e o SignedInfo é assinado com Ed25519 (RFC 9231)
+const (
+	dsigNamespace  = "http://www.w3.org/2000/09/xmldsig#"
+	algC14N        = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped   = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algEd25519     = "http://www.w3.org/2021/04/xmldsig-more#eddsa-ed25519"
+	algSHA256      = "http://www.w3.org/2001/04/xmlenc#sha256"
+	signatureLocal = "Signature"
+)
+
+// sign acrescenta a assinatura como último filho da raiz, que já deve estar com o recuo aplicado
+func sign(root *node, signer *signing.Keyring) error {
+	if !signer.CanSign() {
+		return fmt.Errorf("nenhuma chave de assinatura configurada")
+	}
+
+	// O recuo antes da assinatura faz parte do conteúdo assinado
+	last := len(root.Children) - 1
+	root.Children = append(root.Children[:last:last], &node{Text: "\n  "}, root.Children[last])
+
+	var doc bytes.Buffer
+	root.canonical(&doc, "", nil)
+	digest := sha256.Sum256(doc.Bytes())
+
+	el := func(local string, children ...*node) *node { return element(dsigNamespace, local, children...) }
+	text := func(local, value string) *node { return textElement(dsigNamespace, local, value) }
+
+	signedInfo := el("SignedInfo",
+		el("CanonicalizationMethod").attr("Algorithm", algC14N),
+		el("SignatureMethod").attr("Algorithm", algEd25519),
+		el("Reference",
+			el("Transforms",
+				el("Transform").attr("Algorithm", algEnveloped),
+				el("Transform").attr("Algorithm", algC14N),
+			),
+			el("DigestMethod").attr("Algorithm", algSHA256),
+			text("DigestValue", base64.StdEncoding.EncodeToString(digest[:])),
+		).attr("URI", ""),
+	)
+	value := text("SignatureValue", "")
+	keyInfo := el("KeyInfo", text("KeyName", signer.SigningKey()))
+	if cert := signer.Certificate(); cert != nil {
+		keyInfo.Children = append(keyInfo.Children,
+			el("X509Data", text("X509Certificate", base64.StdEncoding.EncodeToString(cert))))
+	}
+	signature := el(signatureLocal, signedInfo, value, keyInfo)
+	signature.indent(1)
+
+	var canonical bytes.Buffer
+	signedInfo.canonical(&canonical, "", nil)
+	sig, err := signer.Sign(canonical.Bytes())
+	if err != nil {
+		return err
+	}
+	value.Children[0].Text = sig.Value
+
+	last = len(root.Children) - 1
+	root.Children = append(root.Children[:last:last], signature, root.Children[last])
+	return nil
+}
+
+// Verify confere o XML contra o XSD e a assinatura do diploma com as chaves públicas ativas. Os dados
+// do diploma são retornados sempre que o XML é válido, mesmo com a assinatura rejeitada.
+func Verify(ring *signing.Keyring, data []byte) (*Info, error) {
+	root, err := parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := schema.validateTree(root); err != nil {
+		return nil, err
+	}
+	info := readInfo(root)
+
+	var signature *node
+	for _, c := range root.Children {
+		if c.Space == dsigNamespace && c.Local == signatureLocal {
+			signature = c
+		}
+	}
+	if signature == nil {
+		return info, fmt.Errorf("diploma sem assinatura")
+	}
+
+	signedInfo := signature.child("SignedInfo")
+	keyInfo := signature.child("KeyInfo")
+	if signedInfo == nil || keyInfo == nil || keyInfo.child("KeyName") == nil || signature.child("SignatureValue") == nil {
+		return info, fmt.Errorf("assinatura mal formada")
+	}
+	info.KeyID = strings.TrimSpace(keyInfo.child("KeyName").text())
+
+	if err := checkSignedInfo(signedInfo); err != nil {
+		return info, err
+	}
+
+	var doc bytes.Buffer
+	root.canonical(&doc, "", signature)
+	digest := sha256.Sum256(doc.Bytes())
+	expected, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signedInfo.child("Reference").child("DigestValue").text()))
+	if err != nil || !bytes.Equal(expected, digest[:]) {
+		return info, fmt.Errorf("o conteúdo do diploma foi alterado após a assinatura")
+	}
+
+	var canonical bytes.Buffer
+	signedInfo.canonical(&canonical, "", nil)
+	err = ring.Verify(canonical.Bytes(), signing.Signature{
+		KeyID:     info.KeyID,
+		Algorithm: signing.Algorithm,
+		Value:     strings.Join(strings.Fields(signature.child("SignatureValue").text()), ""),
+	})
+	if err != nil {
+		return info, err
+	}
+
+	// O certificado é informativo, mas precisa ser da chave que assinou
+	if x509Data := keyInfo.child("X509Data"); x509Data != nil && x509Data.child("X509Certificate") != nil {
+		der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(x509Data.child("X509Certificate").text()), ""))
+		if err != nil {
+			return info, fmt.Errorf("certificado mal formado")
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return info, fmt.Errorf("certificado mal formado")
+		}
+		if key, ok := cert.PublicKey.(ed25519.PublicKey); !ok || !key.Equal(ring.Key(info.KeyID)) {
+			return info, fmt.Errorf("o certificado não corresponde à chave %s", info.KeyID)
+		}
+	}
+	return info, nil
+}
+
+// checkSignedInfo aceita apenas os algoritmos usados na emissão
+func checkSignedInfo(signedInfo *node) error {
+	algorithm := func(n *node) string {
+		if n == nil {
+			return ""
+		}
+		return n.attrValue("Algorithm")
+	}
+
+	if algorithm(signedInfo.child("CanonicalizationMethod")) != algC14N {
+		return fmt.Errorf("método de canonicalização não suportado")
+	}
+	if algorithm(signedInfo.child("SignatureMethod")) != algEd25519 {
+		return fmt.Errorf("algoritmo de assinatura não suportado")
+	}
+
+	var references []*node
+	for _, c := range signedInfo.Children {
+		if c.Local == "Reference" {
+			references = append(references, c)
+		}
+	}
+	if len(references) != 1 || references[0].attrValue("URI") != "" || references[0].child("DigestValue") == nil {
+		return fmt.Errorf("a assinatura deve referenciar o documento inteiro")
+	}
+	reference := references[0]
+	if algorithm(reference.child("DigestMethod")) != algSHA256 {
+		return fmt.Errorf("algoritmo de resumo não suportado")
+	}
+
+	var transforms []string
+	if t := reference.child("Transforms"); t != nil {
+		for _, c := range t.Children {
+			if c.Local != "" {
+				transforms = append(transforms, algorithm(c))
+			}
+		}
+	}
+	if len(transforms) != 2 || transforms[0] != algEnveloped || transforms[1] != algC14N {
+		return fmt.Errorf("transformações da assinatura não suportadas")
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package diploma
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// node é um elemento ou, com Local vazio, um trecho de texto. Os documentos tratados aqui usam
+// apenas namespaces padrão (xmlns="..."), sem prefixos; Space é o URI do namespace do elemento.
+type node struct {
+	Space    string
+	Local    string
+	Attrs    []xml.Attr
+	Children []*node
+	Text     string
+}
+
+func element(space, local string, children ...*node) *node {
+	return &node{Space: space, Local: local, Children: children}
+}
+
+func textElement(space, local, text string) *node {
+	return &node{Space: space, Local: local, Children: []*node{{Text: text}}}
+}
+
+func (n *node) attr(name, value string) *node {
+	n.Attrs = append(n.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	return n
+}
+
+// child retorna o primeiro filho com o nome informado
+func (n *node) child(local string) *node {
+	for _, c := range n.Children {
+		if c.Local == local {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *node) attrValue(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// text é o texto contido diretamente no elemento
+func (n *node) text() string {
+	var b strings.Builder
+	for _, c := range n.Children {
+		if c.Local == "" {
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String()
+}
+
+// indent insere quebras de linha e recuo entre os filhos, para o arquivo ficar legível. O recuo faz
+// parte do conteúdo assinado, então é aplicado antes da assinatura.
+func (n *node) indent(depth int) {
+	var elements []*node
+	for _, c := range n.Children {
+		if c.Local != "" {
+			elements = append(elements, c)
+		}
+	}
+	if len(elements) == 0 {
+		return
+	}
+
+	pad := "\n" + strings.Repeat("  ", depth+1)
+	var children []*node
+	for _, c := range elements {
+		c.indent(depth + 1)
+		children = append(children, &node{Text: pad}, c)
+	}
+	n.Children = append(children, &node{Text: "\n" + strings.Repeat("  ", depth)})
+}
+
+// parse lê o documento. Comentários, instruções de processamento e a declaração XML são
+// descartados, como na canonicalização.
+func parse(r io.Reader) (*node, error) {
+	dec := xml.NewDecoder(r)
+	var stack []*node
+	var root *node
+	defaults := []string{""}
+
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("XML mal formado: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != "" {
+				return nil, fmt.Errorf("prefixo de namespace não suportado: %s:%s", t.Name.Space, t.Name.Local)
+			}
+			space := defaults[len(defaults)-1]
+			n := &node{Local: t.Name.Local}
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					space = a.Value
+				case a.Name.Space == "xmlns":
+					// Declarações de prefixo não usadas não aparecem na forma canônica
+				case a.Name.Space != "":
+					return nil, fmt.Errorf("atributo com namespace não suportado: %s:%s", a.Name.Space, a.Name.Local)
+				default:
+					n.Attrs = append(n.Attrs, xml.Attr{Name: xml.Name{Local: a.Name.Local}, Value: a.Value})
+				}
+			}
+			n.Space = space
+			defaults = append(defaults, space)
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, n)
+			} else if root != nil {
+				return nil, fmt.Errorf("XML mal formado: mais de um elemento raiz")
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			// RawToken não confere se o fechamento corresponde à abertura
+			if len(stack) == 0 || t.Name.Space != "" || t.Name.Local != stack[len(stack)-1].Local {
+				return nil, fmt.Errorf("XML mal formado: fechamento inesperado de %s", t.Name.Local)
+			}
+			stack = stack[:len(stack)-1]
+			defaults = defaults[:len(defaults)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, &node{Text: string(t)})
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("XML sem elemento raiz")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("XML mal formado: %s não foi fechado", stack[len(stack)-1].Local)
+	}
+	return root, nil
+}
+
+// canonical grava o elemento na forma canônica exclusiva (Exclusive XML Canonicalization 1.0, sem
+// comentários). parentSpace é o namespace padrão já declarado no elemento pai na saída; para
+// canonicalizar uma subárvore isolada, como o SignedInfo, usa-se "". skip é omitido da saída
+// (transformação enveloped-signature).
+func (n *node) canonical(w *bytes.Buffer, parentSpace string, skip *node) {
+	if n == skip {
+		return
+	}
+	if n.Local == "" {
+		escapeText(w, n.Text)
+		return
+	}
+
+	w.WriteByte('<')
+	w.WriteString(n.Local)
+	if n.Space != parentSpace {
+		w.WriteString(` xmlns="`)
+		escapeAttr(w, n.Space)
+		w.WriteByte('"')
+	}
+
+	attrs := append([]xml.Attr(nil), n.Attrs...)
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name.Local < attrs[j].Name.Local })
+	for _, a := range attrs {
+		w.WriteByte(' ')
+		w.WriteString(a.Name.Local)
+		w.WriteString(`="`)
+		escapeAttr(w, a.Value)
+		w.WriteByte('"')
+	}
+	w.WriteByte('>')
+
+	for _, c := range n.Children {
+		c.canonical(w, n.Space, skip)
+	}
+	w.WriteString("</")
+	w.WriteString(n.Local)
+	w.WriteByte('>')
+}
+
+func escapeText(w *bytes.Buffer, s string) {
+	for _, r := range s {
+		switch r {
+		case '&':
+			w.WriteString("&amp;")
+		case '<':
+			w.WriteString("&lt;")
+		case '>':
+			w.WriteString("&gt;")
+		case '\r':
+			w.WriteString("&#xD;")
+		default:
+			w.WriteRune(r)
+		}
+	}
+}
+
+func escapeAttr(w *bytes.Buffer, s string) {
+	for _, r := range s {
+		switch r {
+		case '&':
+			w.WriteString("&amp;")
+		case '<':
+			w.WriteString("&lt;")
+		case '"':
+			w.WriteString("&quot;")
+		case '\t':
+			w.WriteString("&#x9;")
+		case '\n':
+			w.WriteString("&#xA;")
+		case '\r':
+			w.WriteString("&#xD;")
+		default:
+			w.WriteRune(r)
+		}
+	}
+}
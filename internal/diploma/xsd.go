@@ -0,0 +1,437 @@
+package diploma
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validador do subconjunto de XML Schema usado pelo diploma.xsd: elementos globais, tipos complexos
+// com xs:sequence (incluindo xs:any com processContents="skip") e xs:attribute, e tipos simples
+// por xs:restriction com os facets pattern, enumeration, minLength, maxLength, minInclusive,
+// maxInclusive e fractionDigits. elementFormDefault é tratado como "qualified".
+
+type xsdSchema struct {
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Elements        []xsdParticle    `xml:"element"`
+	ComplexTypes    []xsdComplexType `xml:"complexType"`
+	SimpleTypes     []xsdSimpleType  `xml:"simpleType"`
+}
+
+// xsdParticle é um xs:element ou um xs:any
+type xsdParticle struct {
+	XMLName     xml.Name
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr"`
+	MinOccurs   string          `xml:"minOccurs,attr"`
+	MaxOccurs   string          `xml:"maxOccurs,attr"`
+	Namespace   string          `xml:"namespace,attr"`
+	ComplexType *xsdComplexType `xml:"complexType"`
+	SimpleType  *xsdSimpleType  `xml:"simpleType"`
+}
+
+type xsdComplexType struct {
+	Name       string         `xml:"name,attr"`
+	Sequence   *xsdSequence   `xml:"sequence"`
+	Attributes []xsdAttribute `xml:"attribute"`
+}
+
+type xsdSequence struct {
+	Items []xsdParticle `xml:",any"`
+}
+
+type xsdAttribute struct {
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr"`
+	Use        string         `xml:"use,attr"`
+	SimpleType *xsdSimpleType `xml:"simpleType"`
+}
+
+type xsdSimpleType struct {
+	Name        string         `xml:"name,attr"`
+	Restriction xsdRestriction `xml:"restriction"`
+}
+
+type xsdRestriction struct {
+	Base           string     `xml:"base,attr"`
+	Patterns       []xsdFacet `xml:"pattern"`
+	Enumerations   []xsdFacet `xml:"enumeration"`
+	MinLength      *xsdFacet  `xml:"minLength"`
+	MaxLength      *xsdFacet  `xml:"maxLength"`
+	MinInclusive   *xsdFacet  `xml:"minInclusive"`
+	MaxInclusive   *xsdFacet  `xml:"maxInclusive"`
+	FractionDigits *xsdFacet  `xml:"fractionDigits"`
+}
+
+type xsdFacet struct {
+	Value string `xml:"value,attr"`
+}
+
+// Schema é um XSD compilado
+type Schema struct {
+	target   string
+	elements map[string]*xsdParticle
+	complex  map[string]*xsdComplexType
+	simple   map[string]*xsdSimpleType
+	patterns map[string]*regexp.Regexp
+}
+
+// ParseSchema compila o XSD
+func ParseSchema(b []byte) (*Schema, error) {
+	var x xsdSchema
+	if err := xml.Unmarshal(b, &x); err != nil {
+		return nil, fmt.Errorf("XSD inválido: %w", err)
+	}
+
+	s := &Schema{
+		target:   x.TargetNamespace,
+		elements: map[string]*xsdParticle{},
+		complex:  map[string]*xsdComplexType{},
+		simple:   map[string]*xsdSimpleType{},
+		patterns: map[string]*regexp.Regexp{},
+	}
+	for i := range x.Elements {
+		s.elements[x.Elements[i].Name] = &x.Elements[i]
+	}
+	for i := range x.ComplexTypes {
+		s.complex[x.ComplexTypes[i].Name] = &x.ComplexTypes[i]
+	}
+	for i := range x.SimpleTypes {
+		if err := s.compilePatterns(&x.SimpleTypes[i]); err != nil {
+			return nil, err
+		}
+		s.simple[x.SimpleTypes[i].Name] = &x.SimpleTypes[i]
+	}
+	// Tipos simples anônimos, dentro de elementos e atributos
+	var walk func(ps []xsdParticle) error
+	walkComplex := func(c *xsdComplexType) error {
+		for _, a := range c.Attributes {
+			if a.SimpleType != nil {
+				if err := s.compilePatterns(a.SimpleType); err != nil {
+					return err
+				}
+			}
+		}
+		if c.Sequence != nil {
+			return walk(c.Sequence.Items)
+		}
+		return nil
+	}
+	walk = func(ps []xsdParticle) error {
+		for _, p := range ps {
+			if p.SimpleType != nil {
+				if err := s.compilePatterns(p.SimpleType); err != nil {
+					return err
+				}
+			}
+			if p.ComplexType != nil {
+				if err := walkComplex(p.ComplexType); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(x.Elements); err != nil {
+		return nil, err
+	}
+	for i := range x.ComplexTypes {
+		if err := walkComplex(&x.ComplexTypes[i]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Os padrões do XSD valem para o valor inteiro, sem âncoras explícitas
+func (s *Schema) compilePatterns(t *xsdSimpleType) error {
+	for _, p := range t.Restriction.Patterns {
+		if _, ok := s.patterns[p.Value]; ok {
+			continue
+		}
+		re, err := regexp.Compile(`^(?:` + p.Value + `)$`)
+		if err != nil {
+			return fmt.Errorf("XSD inválido: padrão %q: %w", p.Value, err)
+		}
+		s.patterns[p.Value] = re
+	}
+	return nil
+}
+
+// Validate confere o documento contra o schema e retorna todos os problemas encontrados
+func (s *Schema) Validate(doc []byte) error {
+	root, err := parse(bytes.NewReader(doc))
+	if err != nil {
+		return err
+	}
+	return s.validateTree(root)
+}
+
+func (s *Schema) validateTree(root *node) error {
+	var problems []string
+	decl, ok := s.elements[root.Local]
+	if !ok || root.Space != s.target {
+		problems = append(problems, fmt.Sprintf("elemento raiz inesperado: %s", root.Local))
+	} else {
+		s.validateElement(root, decl, "/"+root.Local, &problems)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("XML não confere com o XSD: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Schema) validateElement(n *node, decl *xsdParticle, path string, problems *[]string) {
+	complexType := decl.ComplexType
+	simpleType := decl.SimpleType
+	if complexType == nil && simpleType == nil && decl.Type != "" {
+		if c, ok := s.complex[localName(decl.Type)]; ok && !isBuiltin(decl.Type) {
+			complexType = c
+		}
+	}
+
+	if complexType == nil {
+		for _, c := range n.Children {
+			if c.Local != "" {
+				*problems = append(*problems, fmt.Sprintf("%s: elemento inesperado %s", path, c.Local))
+				return
+			}
+		}
+		if len(n.Attrs) > 0 {
+			*problems = append(*problems, fmt.Sprintf("%s: atributo inesperado %s", path, n.Attrs[0].Name.Local))
+		}
+		if err := s.checkValue(n.text(), decl.Type, simpleType); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: %v", path, err))
+		}
+		return
+	}
+
+	s.validateAttributes(n, complexType, path, problems)
+
+	var children []*node
+	for _, c := range n.Children {
+		if c.Local == "" {
+			if strings.TrimSpace(c.Text) != "" {
+				*problems = append(*problems, fmt.Sprintf("%s: texto não permitido", path))
+			}
+			continue
+		}
+		children = append(children, c)
+	}
+
+	var items []xsdParticle
+	if complexType.Sequence != nil {
+		items = complexType.Sequence.Items
+	}
+	i := 0
+	for idx := range items {
+		p := &items[idx]
+		minOccurs, maxOccurs := occurs(p)
+		count := 0
+		for i < len(children) && (maxOccurs < 0 || count < maxOccurs) && s.matches(p, children[i]) {
+			if p.XMLName.Local == "element" {
+				s.validateElement(children[i], p, path+"/"+children[i].Local, problems)
+			}
+			i++
+			count++
+		}
+		if count < minOccurs {
+			name := p.Name
+			if name == "" {
+				name = "{" + p.Namespace + "}"
+			}
+			*problems = append(*problems, fmt.Sprintf("%s: elemento obrigatório ausente: %s", path, name))
+		}
+	}
+	if i < len(children) {
+		*problems = append(*problems, fmt.Sprintf("%s: elemento inesperado %s", path, children[i].Local))
+	}
+}
+
+func (s *Schema) matches(p *xsdParticle, n *node) bool {
+	if p.XMLName.Local == "any" {
+		switch p.Namespace {
+		case "", "##any":
+			return true
+		case "##other":
+			return n.Space != s.target
+		default:
+			return n.Space == p.Namespace
+		}
+	}
+	return n.Local == p.Name && n.Space == s.target
+}
+
+func (s *Schema) validateAttributes(n *node, c *xsdComplexType, path string, problems *[]string) {
+	declared := map[string]bool{}
+	for _, a := range c.Attributes {
+		declared[a.Name] = true
+		value := n.attrValue(a.Name)
+		present := false
+		for _, attr := range n.Attrs {
+			present = present || attr.Name.Local == a.Name
+		}
+		if !present {
+			if a.Use == "required" {
+				*problems = append(*problems, fmt.Sprintf("%s: atributo obrigatório ausente: %s", path, a.Name))
+			}
+			continue
+		}
+		if err := s.checkValue(value, a.Type, a.SimpleType); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s/@%s: %v", path, a.Name, err))
+		}
+	}
+	for _, attr := range n.Attrs {
+		if !declared[attr.Name.Local] {
+			*problems = append(*problems, fmt.Sprintf("%s: atributo inesperado %s", path, attr.Name.Local))
+		}
+	}
+}
+
+// checkValue confere o valor contra um tipo simples (inline ou pelo nome)
+func (s *Schema) checkValue(value, typeName string, inline *xsdSimpleType) error {
+	t := inline
+	if t == nil {
+		if typeName == "" || isBuiltin(typeName) {
+			return checkBuiltin(value, localName(typeName))
+		}
+		var ok bool
+		t, ok = s.simple[localName(typeName)]
+		if !ok {
+			return fmt.Errorf("tipo desconhecido no XSD: %s", typeName)
+		}
+	}
+
+	r := t.Restriction
+	if err := s.checkValue(value, r.Base, nil); err != nil {
+		return err
+	}
+
+	if len(r.Enumerations) > 0 {
+		found := false
+		for _, e := range r.Enumerations {
+			found = found || e.Value == value
+		}
+		if !found {
+			return fmt.Errorf("valor %q fora da lista permitida", value)
+		}
+	}
+	for _, p := range r.Patterns {
+		if !s.patterns[p.Value].MatchString(value) {
+			return fmt.Errorf("valor %q não corresponde ao padrão %s", value, p.Value)
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+	if r.MinLength != nil {
+		if n, _ := strconv.Atoi(r.MinLength.Value); length < n {
+			return fmt.Errorf("valor com menos de %d caracteres", n)
+		}
+	}
+	if r.MaxLength != nil {
+		if n, _ := strconv.Atoi(r.MaxLength.Value); length > n {
+			return fmt.Errorf("valor com mais de %d caracteres", n)
+		}
+	}
+
+	if r.MinInclusive != nil || r.MaxInclusive != nil {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("valor %q não é numérico", value)
+		}
+		if r.MinInclusive != nil {
+			if limit, _ := strconv.ParseFloat(r.MinInclusive.Value, 64); v < limit {
+				return fmt.Errorf("valor %s menor que %s", value, r.MinInclusive.Value)
+			}
+		}
+		if r.MaxInclusive != nil {
+			if limit, _ := strconv.ParseFloat(r.MaxInclusive.Value, 64); v > limit {
+				return fmt.Errorf("valor %s maior que %s", value, r.MaxInclusive.Value)
+			}
+		}
+	}
+	if r.FractionDigits != nil {
+		n, _ := strconv.Atoi(r.FractionDigits.Value)
+		if dot := strings.IndexByte(value, '.'); dot >= 0 && len(strings.TrimRight(value[dot+1:], "0")) > n {
+			return fmt.Errorf("valor %s com mais de %d casas decimais", value, n)
+		}
+	}
+	return nil
+}
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+var integerPattern = regexp.MustCompile(`^[+-]?\d+$`)
+
+func checkBuiltin(value, name string) error {
+	switch name {
+	case "", "string", "normalizedString", "token":
+		return nil
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("data inválida: %q", value)
+		}
+	case "dateTime":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("data e hora inválidas: %q", value)
+		}
+	case "decimal":
+		if !decimalPattern.MatchString(value) {
+			return fmt.Errorf("decimal inválido: %q", value)
+		}
+	case "integer", "int", "positiveInteger", "nonNegativeInteger":
+		if !integerPattern.MatchString(value) {
+			return fmt.Errorf("inteiro inválido: %q", value)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("inteiro inválido: %q", value)
+		}
+		if name == "positiveInteger" && n < 1 || name == "nonNegativeInteger" && n < 0 {
+			return fmt.Errorf("inteiro fora do intervalo: %q", value)
+		}
+	case "boolean":
+		if value != "true" && value != "false" && value != "1" && value != "0" {
+			return fmt.Errorf("booleano inválido: %q", value)
+		}
+	case "anyURI":
+		if _, err := url.Parse(value); err != nil {
+			return fmt.Errorf("URI inválida: %q", value)
+		}
+	default:
+		return fmt.Errorf("tipo não suportado pelo validador: xs:%s", name)
+	}
+	return nil
+}
+
+func occurs(p *xsdParticle) (minOccurs, maxOccurs int) {
+	minOccurs, maxOccurs = 1, 1
+	if p.MinOccurs != "" {
+		minOccurs, _ = strconv.Atoi(p.MinOccurs)
+	}
+	switch p.MaxOccurs {
+	case "":
+	case "unbounded":
+		maxOccurs = -1
+	default:
+		maxOccurs, _ = strconv.Atoi(p.MaxOccurs)
+	}
+	return minOccurs, maxOccurs
+}
+
+func isBuiltin(typeName string) bool {
+	return strings.HasPrefix(typeName, "xs:") || strings.HasPrefix(typeName, "xsd:")
+}
+
+func localName(typeName string) string {
+	if i := strings.IndexByte(typeName, ':'); i >= 0 {
+		return typeName[i+1:]
+	}
+	return typeName
+}
@@ -0,0 +1,94 @@
+// Package documents monta os PDFs dos documentos oficiais (declarações e histórico escolar), com o
+// código de verificação e o QR Code que aponta para a verificação pública.
+package documents
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"strings"
+	"time"
+)
+
+// Titles são os títulos impressos de cada tipo de documento
+var Titles = map[string]string{
+	models.DocumentEnrollment: "Declaração de Matrícula",
+	models.DocumentAttendance: "Declaração de Frequência",
+	models.DocumentTranscript: "Histórico Escolar",
+}
+
+// Issue é um documento a emitir. CPF é o número completo, impresso no PDF; Content guarda a
+// versão mascarada.
+type Issue struct {
+	Kind      string
+	Code      string
+	IssuedAt  time.Time
+	VerifyURL string
+	CPF       string
+	Content   models.DocumentContent
+}
+
+// Sem 0/O, 1/I/L e U, para o código poder ser digitado sem ambiguidade
+const codeAlphabet = "23456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewCode sorteia um código de verificação no formato XXXX-XXXX-XXXX
+func NewCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("erro ao gerar código de verificação: %w", err)
+	}
+
+	var b strings.Builder
+	for i, c := range buf {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		// 256 não é múltiplo de 30; o viés de poucos valores não compromete a unicidade
+		b.WriteByte(codeAlphabet[int(c)%len(codeAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// NormalizeCode aceita o código digitado em minúsculas, com espaços ou sem os hífens
+func NormalizeCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' {
+			if b.Len() == 4 || b.Len() == 9 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// MaskCPF mostra apenas os dígitos do meio do CPF (***.982.247-**)
+func MaskCPF(cpf string) string {
+	var digits []byte
+	for i := 0; i < len(cpf); i++ {
+		if cpf[i] >= '0' && cpf[i] <= '9' {
+			digits = append(digits, cpf[i])
+		}
+	}
+	if len(digits) != 11 {
+		return "***"
+	}
+	return fmt.Sprintf("***.%s.%s-**", digits[3:6], digits[6:9])
+}
+
+// FormatCPF formata os 11 dígitos do CPF como 000.000.000-00
+func FormatCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return fmt.Sprintf("%s.%s.%s-%s", cpf[:3], cpf[3:6], cpf[6:9], cpf[9:])
+}
+
+// Hash é o SHA-256 do PDF, em hexadecimal, informado na verificação
+func Hash(pdf []byte) string {
+	sum := sha256.Sum256(pdf)
+	return hex.EncodeToString(sum[:])
+}
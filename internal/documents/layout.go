@@ -0,0 +1,260 @@
+package documents
+
+import (
+	"fmt"
+	"sistema-faculdade/internal/models"
+	"sistema-faculdade/internal/pdf"
+	"sistema-faculdade/internal/qr"
+	"strings"
+)
+
+const (
+	margin     = 56.0
+	bodyTop    = 150.0
+	bodyBottom = 740.0
+	footerTop  = 752.0
+	qrSize     = 64.0
+	lineHeight = 14.0
+)
+
+var registrationStatus = map[string]string{
+	"in_progress": "Cursando",
+	"approved":    "Aprovado",
+	"failed":      "Reprovado",
+	"take_test":   "Exame final",
+	"dropped":     "Trancada",
+}
+
+// column é uma coluna de tabela; right alinha o conteúdo à direita
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+// layout distribui o conteúdo pelas páginas, abrindo uma nova quando o corpo chega ao rodapé
+type layout struct {
+	issue Issue
+	doc   *pdf.Document
+	page  *pdf.Page
+	y     float64
+}
+
+// Render gera o PDF do documento
+func Render(issue Issue) ([]byte, error) {
+	title, ok := Titles[issue.Kind]
+	if !ok {
+		return nil, fmt.Errorf("tipo de documento inválido: %s", issue.Kind)
+	}
+	code, err := qr.Encode(issue.VerifyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &layout{issue: issue, doc: pdf.New(title, issue.Content.Institution, issue.IssuedAt)}
+	l.newPage()
+
+	c := issue.Content
+	switch issue.Kind {
+	case models.DocumentEnrollment:
+		l.paragraph(fmt.Sprintf("Declaramos, para os devidos fins, que %s, CPF %s, matrícula %s, "+
+			"está regularmente matriculado(a) no curso de %s desta instituição no período letivo %s.",
+			c.StudentName, FormatCPF(issue.CPF), c.RegistrationNumber, c.CourseName, c.Semester))
+		if len(c.Disciplines) > 0 {
+			l.y += lineHeight
+			l.paragraph("Disciplinas em que está matriculado(a) no período:")
+			var rows [][]string
+			for _, d := range c.Disciplines {
+				rows = append(rows, []string{d.Code, d.Name, d.Schedule, fmt.Sprintf("%dh", d.WorkloadHours)})
+			}
+			l.table([]column{{"Código", 70, false}, {"Disciplina", 245, false}, {"Horário", 125, false}, {"CH", 43, true}}, rows)
+		}
+	case models.DocumentAttendance:
+		l.paragraph(fmt.Sprintf("Declaramos, para os devidos fins, que %s, CPF %s, matrícula %s, aluno(a) do "+
+			"curso de %s, frequentou no período letivo %s as disciplinas abaixo, com a seguinte frequência:",
+			c.StudentName, FormatCPF(issue.CPF), c.RegistrationNumber, c.CourseName, c.Semester))
+		var rows [][]string
+		for _, d := range c.Disciplines {
+			rows = append(rows, []string{
+				d.Code, d.Name, fmt.Sprintf("%dh", d.WorkloadHours), fmt.Sprintf("%dh", d.Absences),
+				percent(d.Frequency), registrationStatus[d.Status],
+			})
+		}
+		l.table([]column{
+			{"Código", 60, false}, {"Disciplina", 195, false}, {"CH", 40, true}, {"Faltas", 45, true},
+			{"Frequência", 65, true}, {"Situação", 78, false},
+		}, rows)
+	case models.DocumentTranscript:
+		l.field("Aluno(a)", c.StudentName)
+		l.field("CPF", FormatCPF(issue.CPF))
+		l.field("Matrícula", c.RegistrationNumber)
+		l.field("Curso", c.CourseName)
+		l.y += lineHeight / 2
+
+		var rows [][]string
+		credits := 0
+		if c.Transcript != nil {
+			credits = c.Transcript.EarnedCredits
+			for _, e := range c.Transcript.Entries {
+				status := registrationStatus[e.Status]
+				if e.Origin == models.OriginTransferred {
+					status = "Aproveitada"
+				}
+				rows = append(rows, []string{
+					e.Semester, e.DisciplineCode, e.DisciplineName, fmt.Sprintf("%dh", e.WorkloadHours),
+					fmt.Sprint(e.Credits), grade(e.FinalGrade), percent(e.Frequency), status,
+				})
+			}
+		}
+		l.table([]column{
+			{"Período", 45, false}, {"Código", 55, false}, {"Disciplina", 160, false}, {"CH", 35, true},
+			{"Créd.", 32, true}, {"Nota", 35, true}, {"Freq.", 45, true}, {"Situação", 76, false},
+		}, rows)
+		l.y += lineHeight / 2
+		l.field("Créditos integralizados", fmt.Sprint(credits))
+	}
+
+	l.footers(code)
+	return l.doc.Bytes()
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.AddPage()
+	c := l.issue.Content
+	l.page.TextCenter(pdf.PageWidth/2, 70, pdf.HelveticaBold, 16, c.Institution)
+	l.page.TextCenter(pdf.PageWidth/2, 88, pdf.Helvetica, 10, "Secretaria Acadêmica")
+	l.page.Line(margin, 100, pdf.PageWidth-margin, 100, 0.8)
+	l.page.TextCenter(pdf.PageWidth/2, 128, pdf.HelveticaBold, 14, Titles[l.issue.Kind])
+	l.y = bodyTop
+}
+
+// ensure abre uma nova página se não houver h pontos livres no corpo
+func (l *layout) ensure(h float64) bool {
+	if l.y+h <= bodyBottom {
+		return false
+	}
+	l.newPage()
+	return true
+}
+
+func (l *layout) paragraph(text string) {
+	for _, line := range pdf.Wrap(pdf.Helvetica, 11, text, pdf.PageWidth-2*margin) {
+		l.ensure(lineHeight + 2)
+		l.y += lineHeight + 2
+		l.page.Text(margin, l.y, pdf.Helvetica, 11, line)
+	}
+}
+
+func (l *layout) field(label, value string) {
+	l.ensure(lineHeight)
+	l.y += lineHeight
+	label += ": "
+	l.page.Text(margin, l.y, pdf.HelveticaBold, 10, label)
+	l.page.Text(margin+pdf.TextWidth(pdf.HelveticaBold, 10, label), l.y, pdf.Helvetica, 10, value)
+}
+
+// table desenha a tabela, repetindo o cabeçalho a cada página. Textos que não cabem na coluna são
+// cortados.
+func (l *layout) table(cols []column, rows [][]string) {
+	const size = 9.0
+	header := func() {
+		l.y += 6
+		l.page.Rect(margin, l.y, pdf.PageWidth-2*margin, lineHeight+2, 0.88)
+		l.y += lineHeight - 2
+		l.cells(cols, nil, pdf.HelveticaBold, size)
+		l.y += 6
+	}
+
+	l.ensure(3 * lineHeight)
+	header()
+	for _, row := range rows {
+		if l.ensure(lineHeight) {
+			header()
+		}
+		l.y += lineHeight
+		l.cells(cols, row, pdf.Helvetica, size)
+	}
+	if len(rows) == 0 {
+		l.y += lineHeight
+		l.page.Text(margin+4, l.y, pdf.Helvetica, size, "Nenhuma disciplina registrada.")
+	}
+	l.page.Line(margin, l.y+5, pdf.PageWidth-margin, l.y+5, 0.5)
+	l.y += 5
+}
+
+// cells escreve uma linha da tabela; sem row, escreve os títulos das colunas
+func (l *layout) cells(cols []column, row []string, f pdf.Font, size float64) {
+	x := margin
+	for i, col := range cols {
+		text := col.title
+		if row != nil {
+			text = fit(f, size, row[i], col.width-8)
+		}
+		if col.right {
+			l.page.TextRight(x+col.width-4, l.y, f, size, text)
+		} else {
+			l.page.Text(x+4, l.y, f, size, text)
+		}
+		x += col.width
+	}
+}
+
+// footers escreve em cada página o código de verificação, a data de emissão, o endereço de
+// verificação, a numeração e o QR Code
+func (l *layout) footers(code *qr.Code) {
+	pages := l.doc.Pages()
+	issued := l.issue.IssuedAt.Format("02/01/2006 15:04")
+	textWidth := pdf.PageWidth - 2*margin - qrSize - 12
+	for i, p := range pages {
+		p.Line(margin, footerTop, pdf.PageWidth-margin, footerTop, 0.5)
+		p.Text(margin, footerTop+18, pdf.HelveticaBold, 9, "Código de verificação: "+l.issue.Code)
+		p.Text(margin, footerTop+31, pdf.Helvetica, 8, "Emitido em "+issued+". Confira a autenticidade em:")
+		p.Text(margin, footerTop+43, pdf.Helvetica, 8, fit(pdf.Helvetica, 8, l.issue.VerifyURL, textWidth))
+		p.Text(margin, footerTop+62, pdf.Helvetica, 8, fmt.Sprintf("Página %d de %d", i+1, len(pages)))
+		drawQR(p, code, pdf.PageWidth-margin-qrSize, footerTop+6, qrSize)
+	}
+}
+
+// drawQR desenha o QR Code no quadrado de lado size, juntando os módulos escuros vizinhos da linha
+func drawQR(p *pdf.Page, code *qr.Code, x, y, size float64) {
+	m := size / float64(code.Size)
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; {
+			if !code.Dark(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < code.Size && code.Dark(col, row) {
+				col++
+			}
+			p.Rect(x+float64(start)*m, y+float64(row)*m, float64(col-start)*m, m, 0)
+		}
+	}
+}
+
+// fit corta o texto com reticências para caber em width pontos
+func fit(f pdf.Font, size float64, s string, width float64) string {
+	if pdf.TextWidth(f, size, s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.TextWidth(f, size, string(r)+"...") > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
+
+func percent(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return strings.Replace(fmt.Sprintf("%.1f%%", *f), ".", ",", 1)
+}
+
+func grade(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return strings.Replace(fmt.Sprintf("%.2f", *f), ".", ",", 1)
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+type csvWriter struct {
+	w   *csv.Writer
+	row []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// Row grava a linha; o csv.Writer descarrega o buffer no destino à medida que ele enche
+func (c *csvWriter) Row(values ...any) error {
+	c.row = c.row[:0]
+	for _, v := range values {
+		c.row = append(c.row, csvText(toCell(v)))
+	}
+	return c.w.Write(c.row)
+}
+
+// csvText prefixa com ' os textos que a planilha interpretaria como fórmula; números negativos
+// continuam como números
+func csvText(c cell) string {
+	if !c.numeric && c.text != "" && strings.ContainsRune("=+-@", rune(c.text[0])) {
+		return "'" + c.text
+	}
+	return c.text
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,104 @@
+// Package export grava listagens em CSV ou XLSX linha a linha, sem montar a planilha em memória.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de exportação
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Tipos MIME dos formatos, usados no Accept e no Content-Type
+var ContentTypes = map[string]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Writer recebe as linhas da listagem. Close conclui o arquivo (no XLSX, fecha a planilha e o zip).
+type Writer interface {
+	Row(values ...any) error
+	Close() error
+}
+
+// New cria o writer do formato e já grava o cabeçalho
+func New(w io.Writer, format, sheet string, header []string) (Writer, error) {
+	var xw Writer
+	switch format {
+	case CSV:
+		xw = newCSVWriter(w)
+	case XLSX:
+		xw = newXLSXWriter(w, sheet)
+	default:
+		return nil, fmt.Errorf("formato de exportação desconhecido: %s", format)
+	}
+
+	values := make([]any, len(header))
+	for i, h := range header {
+		values[i] = h
+	}
+	return xw, xw.Row(values...)
+}
+
+// cell é o valor já convertido: texto ou número
+type cell struct {
+	text    string
+	numeric bool
+}
+
+// toCell converte os tipos usados nos models. Ponteiros nulos viram células vazias, datas saem
+// como AAAA-MM-DD e booleanos como sim/não.
+func toCell(v any) cell {
+	switch v := v.(type) {
+	case nil:
+		return cell{}
+	case string:
+		return cell{text: v}
+	case *string:
+		if v == nil {
+			return cell{}
+		}
+		return cell{text: *v}
+	case int:
+		return cell{text: strconv.Itoa(v), numeric: true}
+	case *int:
+		if v == nil {
+			return cell{}
+		}
+		return cell{text: strconv.Itoa(*v), numeric: true}
+	case int64:
+		return cell{text: strconv.FormatInt(v, 10), numeric: true}
+	case float64:
+		return cell{text: strconv.FormatFloat(v, 'f', -1, 64), numeric: true}
+	case *float64:
+		if v == nil {
+			return cell{}
+		}
+		return cell{text: strconv.FormatFloat(*v, 'f', -1, 64), numeric: true}
+	case bool:
+		if v {
+			return cell{text: "sim"}
+		}
+		return cell{text: "não"}
+	case time.Time:
+		if v.IsZero() {
+			return cell{}
+		}
+		return cell{text: v.Format("2006-01-02")}
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return cell{}
+		}
+		return cell{text: v.Format("2006-01-02")}
+	case []string:
+		return cell{text: strings.Join(v, ", ")}
+	default:
+		return cell{text: fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) CreateAdmissionProcessHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.AdmissionProcess
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || input.SemesterID < 1 || len(input.Seats) == 0 {
+		http.Error(w, "Informe o nome, o semestre de ingresso e as vagas por curso", http.StatusBadRequest)
+		return
+	}
+	if input.Method != models.AdmissionVestibular && input.Method != models.AdmissionEnem {
+		http.Error(w, "Forma de ingresso inválida. Use vestibular ou enem.", http.StatusBadRequest)
+		return
+	}
+	for _, s := range input.Seats {
+		if s.CourseID < 1 || s.Seats < 1 {
+			http.Error(w, "Cada curso precisa de ao menos uma vaga", http.StatusBadRequest)
+			return
+		}
+	}
+	input.CreatedBy = requestUser(r)
+
+	id, err := h.Admissions.CreateProcess(&input)
+	if err != nil {
+		switch err.Error() {
+		case "processo seletivo já cadastrado", "curso repetido nas vagas":
+			http.Error(w, err.Error(), http.StatusConflict)
+		case "curso ou semestre inexistente":
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao criar processo seletivo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Processo seletivo criado com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetAdmissionProcessesHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := h.Admissions.GetProcesses()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar processos seletivos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) GetAdmissionProcessHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// admissionProcess carrega o processo do {id} da rota, escrevendo a resposta de erro quando não encontra
+func (h *Handler) admissionProcess(w http.ResponseWriter, r *http.Request) (*models.AdmissionProcess, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	p, err := h.Admissions.GetProcess(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar processo seletivo", http.StatusInternalServerError)
+		return nil, false
+	}
+	if p == nil {
+		http.Error(w, "Processo seletivo não encontrado", http.StatusNotFound)
+		return nil, false
+	}
+	return p, true
+}
+
+func (h *Handler) CreateApplicantHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.Applicant
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.ProcessID = p.ID
+	input.Name = strings.TrimSpace(input.Name)
+	input.CPF = digitsOnly(input.CPF)
+
+	if input.Name == "" || input.CourseID < 1 || input.DateBirth.IsZero() {
+		http.Error(w, "Informe o nome, a data de nascimento e o curso pretendido", http.StatusBadRequest)
+		return
+	}
+	if len(input.CPF) != 11 {
+		http.Error(w, "CPF inválido", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.Admissions.CreateApplicant(&input)
+	if err != nil {
+		switch err.Error() {
+		case "candidato já inscrito neste processo":
+			http.Error(w, err.Error(), http.StatusConflict)
+		case "o curso não tem vagas neste processo", "dados do candidato inválidos":
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao inscrever candidato", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Candidato inscrito com sucesso",
+		"id":      id,
+	})
+}
+
+// GetApplicantsHandler lista os candidatos pela classificação. Filtros: course_id, status e call
+// (lista de convocados de uma chamada); format=csv exporta a lista.
+func (h *Handler) GetApplicantsHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	courseID, callNumber := 0, 0
+	if v := q.Get("course_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "course_id inválido", http.StatusBadRequest)
+			return
+		}
+		courseID = id
+	}
+	if v := q.Get("call"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "call inválido", http.StatusBadRequest)
+			return
+		}
+		callNumber = n
+	}
+	status := q.Get("status")
+	switch status {
+	case "", models.ApplicantRegistered, models.ApplicantCalled, models.ApplicantConfirmed,
+		models.ApplicantDeclined, models.ApplicantForfeited:
+	default:
+		http.Error(w, "Situação inválida", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Admissions.GetApplicants(p.ID, courseID, status, callNumber)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar candidatos", http.StatusInternalServerError)
+		return
+	}
+
+	if q.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="candidatos-%d.csv"`, p.ID))
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"classificacao", "nome", "cpf", "curso", "nota_final", "situacao", "chamada"})
+		for _, a := range list {
+			rank, score, call := "", "", ""
+			if a.Rank != nil {
+				rank = strconv.Itoa(*a.Rank)
+			}
+			if a.FinalScore != nil {
+				score = fmt.Sprintf("%.2f", *a.FinalScore)
+			}
+			if a.CallNumber != nil {
+				call = strconv.Itoa(*a.CallNumber)
+			}
+			cw.Write([]string{rank, a.Name, a.CPF, a.CourseName, score, a.Status, call})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Println("Erro ao exportar candidatos:", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// ImportScoresHandler importa as notas de um CSV (corpo da requisição ou campo "file" de um
+// formulário multipart). No ENEM cada linha traz cpf, linguagens, humanas, natureza, matemática
+// e redação; no vestibular, cpf e nota. Havendo qualquer erro nada é gravado.
+func (h *Handler) ImportScoresHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Arquivo não enviado no campo 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	scores, rowErrors := parseScoresCSV(body, p.Method)
+	if len(rowErrors) == 0 && len(scores) == 0 {
+		rowErrors = append(rowErrors, "arquivo sem notas")
+	}
+	if len(rowErrors) == 0 {
+		missing, err := h.Admissions.ImportScores(p.ID, scores)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "processo seletivo: ") {
+				http.Error(w, strings.TrimPrefix(err.Error(), "processo seletivo: "), http.StatusConflict)
+				return
+			}
+			log.Println(err)
+			http.Error(w, "Erro ao importar notas", http.StatusInternalServerError)
+			return
+		}
+		for _, cpf := range missing {
+			rowErrors = append(rowErrors, fmt.Sprintf("CPF %s não está inscrito no processo", cpf))
+		}
+	}
+	if len(rowErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Arquivo com erros, nada foi importado",
+			"errors":  rowErrors,
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Notas importadas com sucesso",
+		"imported": len(scores),
+	})
+}
+
+func parseScoresCSV(body io.Reader, method string) ([]models.ApplicantScore, []string) {
+	br := bufio.NewReader(body)
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _ := br.Peek(br.Size()); strings.Contains(strings.SplitN(string(first), "\n", 2)[0], ";") {
+		reader.Comma = ';'
+	}
+
+	columns := 2
+	if method == models.AdmissionEnem {
+		columns = 6
+	}
+
+	var scores []models.ApplicantScore
+	var rowErrors []string
+	seen := map[string]int{}
+
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %v", line, err))
+			continue
+		}
+
+		// Cabeçalho opcional
+		if line == 1 && strings.ToLower(strings.TrimSpace(record[0])) == "cpf" {
+			continue
+		}
+
+		if len(record) < columns {
+			if columns == 6 {
+				rowErrors = append(rowErrors, fmt.Sprintf("linha %d: esperado cpf, linguagens, humanas, natureza, matemática e redação", line))
+			} else {
+				rowErrors = append(rowErrors, fmt.Sprintf("linha %d: esperado cpf e nota", line))
+			}
+			continue
+		}
+
+		cpf := digitsOnly(record[0])
+		if len(cpf) != 11 {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: CPF inválido %q", line, record[0]))
+			continue
+		}
+		if prev, ok := seen[cpf]; ok {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: CPF repetido (linha %d)", line, prev))
+			continue
+		}
+		seen[cpf] = line
+
+		values := make([]float64, columns-1)
+		valid := true
+		for i := range values {
+			v, err := parseScore(record[i+1])
+			if err != nil {
+				rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %v", line, err))
+				valid = false
+				break
+			}
+			values[i] = v
+		}
+		if !valid {
+			continue
+		}
+
+		s := models.ApplicantScore{CPF: cpf}
+		if columns == 6 {
+			s.Enem = &models.EnemScores{
+				Languages: values[0], Humanities: values[1], Sciences: values[2], Math: values[3], Essay: values[4],
+			}
+			s.FinalScore = math.Round(s.Enem.Final()*100) / 100
+		} else {
+			s.FinalScore = values[0]
+		}
+		scores = append(scores, s)
+	}
+
+	return scores, rowErrors
+}
+
+// parseScore aceita vírgula ou ponto como separador decimal
+func parseScore(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+	if err != nil || v < 0 || v > 1000 {
+		return 0, fmt.Errorf("nota inválida %q", s)
+	}
+	return v, nil
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// RankApplicantsHandler gera a classificação por curso a partir das notas importadas
+func (h *Handler) RankApplicantsHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	ranked, err := h.Admissions.Rank(p.ID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "processo seletivo: ") {
+			http.Error(w, strings.TrimPrefix(err.Error(), "processo seletivo: "), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao classificar candidatos", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Classificação gerada com sucesso",
+		"ranked":  ranked,
+	})
+}
+
+// CreateAdmissionCallHandler publica a próxima chamada. Convocados da chamada anterior que não
+// confirmaram a matrícula perdem a vaga.
+func (h *Handler) CreateAdmissionCallHandler(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.admissionProcess(w, r)
+	if !ok {
+		return
+	}
+
+	call, err := h.Admissions.NextCall(p.ID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "processo seletivo: ") {
+			http.Error(w, strings.TrimPrefix(err.Error(), "processo seletivo: "), http.StatusUnprocessableEntity)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao publicar chamada", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(call)
+}
+
+// ConfirmApplicantHandler confirma a matrícula do convocado, criando o aluno com matrícula gerada
+func (h *Handler) ConfirmApplicantHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Admissions.Confirm(id, requestUser(r))
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case msg == fmt.Sprintf("nenhum candidato encontrado com o ID %d", id):
+			http.Error(w, "Candidato não encontrado", http.StatusNotFound)
+		case strings.HasPrefix(msg, "processo seletivo: "):
+			http.Error(w, strings.TrimPrefix(msg, "processo seletivo: "), http.StatusConflict)
+		case msg == "CPF já cadastrado", msg == "este email já cadastrado":
+			http.Error(w, "Candidato já cadastrado como aluno: "+msg, http.StatusConflict)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao confirmar matrícula", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":             "Matrícula confirmada com sucesso",
+		"student_id":          student.ID,
+		"registration_number": student.RegistrationNumber,
+	})
+}
+
+func (h *Handler) DeclineApplicantHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Admissions.Decline(id); err != nil {
+		if strings.HasPrefix(err.Error(), "processo seletivo: ") {
+			http.Error(w, strings.TrimPrefix(err.Error(), "processo seletivo: "), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao registrar desistência", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Desistência registrada"})
+}
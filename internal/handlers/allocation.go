@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"sort"
+	"strconv"
+)
+
+// checkAllocation confere o professor da oferta. Retorna refusal quando a oferta não pode ser
+// gravada (professor inativo ou não habilitado na disciplina, a menos que force seja usado)
+// e warnings para o que só precisa de atenção: outro departamento, fora dos horários de
+// preferência ou choque com outra oferta do professor no semestre. Com assigned (o professor
+// já leciona esta disciplina na oferta), a situação e a habilitação não são conferidas de novo.
+func (h *Handler) checkAllocation(o *models.DisciplineOffer, assigned, force bool) (warnings []string, refusal string, err error) {
+	warnings = []string{}
+	if o.TeacherID == 0 {
+		return warnings, "", nil
+	}
+
+	teacher, err := h.Teachers.GetByID(o.TeacherID)
+	if err != nil {
+		return nil, "", err
+	}
+	if teacher == nil {
+		return nil, "Professor não encontrado", nil
+	}
+	if !assigned && !teacher.Active {
+		return nil, "Professor inativo não pode receber ofertas", nil
+	}
+
+	discipline, err := h.Disciplines.GetByID(o.DisciplineID)
+	if err != nil {
+		return nil, "", err
+	}
+	if discipline == nil {
+		return nil, "Disciplina não encontrada", nil
+	}
+
+	if !assigned {
+		qualified, err := h.Teachers.IsQualified(o.TeacherID, o.DisciplineID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !qualified {
+			msg := fmt.Sprintf("%s não está habilitado(a) a lecionar %s", teacher.Name, discipline.Name)
+			if !force {
+				return nil, msg + " (use force=true para confirmar)", nil
+			}
+			warnings = append(warnings, msg)
+		}
+	}
+
+	if teacher.DepartmentID != discipline.DepartmentID {
+		warnings = append(warnings, fmt.Sprintf("Professor do departamento %s e disciplina do departamento %s",
+			teacher.DepartmentName, discipline.DepartmentName))
+	}
+
+	slots, _ := academic.ParseSchedule(o.Schedule)
+	if teacher.PreferredSchedule != "" {
+		preferred, err := academic.ParseSchedule(teacher.PreferredSchedule)
+		if err == nil && !academic.WithinSlots(slots, preferred) {
+			warnings = append(warnings, fmt.Sprintf("Horário fora da preferência do professor (%s)", teacher.PreferredSchedule))
+		}
+	}
+
+	others, err := h.Offers.GetByTeacher(o.TeacherID, o.SemesterID)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, other := range others {
+		if other.ID == o.ID {
+			continue
+		}
+		otherSlots, err := academic.ParseSchedule(other.Schedule)
+		if err == nil && academic.SchedulesConflict(slots, otherSlots) {
+			warnings = append(warnings, fmt.Sprintf("Choque de horário com %s (%s)", other.DisciplineCode, other.Schedule))
+		}
+	}
+
+	return warnings, "", nil
+}
+
+// GetCandidateTeachersHandler lista os professores habilitados para a disciplina. Com semester_id
+// e schedule, indica choques de horário e preferência e ordena pelos mais adequados.
+func (h *Handler) GetCandidateTeachersHandler(w http.ResponseWriter, r *http.Request) {
+	disciplineID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || disciplineID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	semesterID := 0
+	if v := q.Get("semester_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "semester_id inválido", http.StatusBadRequest)
+			return
+		}
+		semesterID = id
+	}
+
+	var slots []academic.Slot
+	if v := q.Get("schedule"); v != "" {
+		slots, err = academic.ParseSchedule(v)
+		if err != nil {
+			http.Error(w, "Horário inválido: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	candidates, err := h.Teachers.Candidates(disciplineID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar professores habilitados", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		if slots != nil && c.PreferredSchedule != "" {
+			if preferred, err := academic.ParseSchedule(c.PreferredSchedule); err == nil {
+				c.PreferredSlot = academic.WithinSlots(slots, preferred)
+			}
+		}
+		if semesterID == 0 {
+			continue
+		}
+
+		offers, err := h.Offers.GetByTeacher(c.TeacherID, semesterID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar ofertas do professor", http.StatusInternalServerError)
+			return
+		}
+		for _, o := range offers {
+			hours, _ := academic.WeeklyHours(o.Schedule, 0, 0)
+			c.WeeklyHours += hours
+			if o.DisciplineID == disciplineID {
+				continue
+			}
+			if other, err := academic.ParseSchedule(o.Schedule); err == nil && academic.SchedulesConflict(slots, other) {
+				c.Conflicts = append(c.Conflicts, fmt.Sprintf("%s (%s)", o.DisciplineCode, o.Schedule))
+			}
+		}
+	}
+
+	// Sem choque primeiro, depois dentro da preferência, do mesmo departamento e com menos horas
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (len(a.Conflicts) == 0) != (len(b.Conflicts) == 0) {
+			return len(a.Conflicts) == 0
+		}
+		if a.PreferredSlot != b.PreferredSlot {
+			return a.PreferredSlot
+		}
+		if a.SameDepartment != b.SameDepartment {
+			return a.SameDepartment
+		}
+		return a.WeeklyHours < b.WeeklyHours
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidates)
+}
+
+func (h *Handler) GetTeacherDisciplinesHandler(w http.ResponseWriter, r *http.Request) {
+	teacherID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || teacherID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Teachers.GetDisciplines(teacherID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar disciplinas do professor", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// AddTeacherDisciplineHandler habilita o professor a lecionar uma disciplina
+func (h *Handler) AddTeacherDisciplineHandler(w http.ResponseWriter, r *http.Request) {
+	teacherID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || teacherID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.TeacherDiscipline
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.DisciplineID < 1 {
+		http.Error(w, "Informe a disciplina", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Teachers.AddDiscipline(teacherID, input.DisciplineID); err != nil {
+		if err.Error() == "professor ou disciplina inexistente" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao habilitar professor na disciplina", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Professor habilitado na disciplina"})
+}
+
+func (h *Handler) RemoveTeacherDisciplineHandler(w http.ResponseWriter, r *http.Request) {
+	teacherID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || teacherID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+	disciplineID, err := strconv.Atoi(r.PathValue("discipline_id"))
+	if err != nil || disciplineID < 1 {
+		http.Error(w, "ID da disciplina inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Teachers.RemoveDiscipline(teacherID, disciplineID)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("professor não habilitado na disciplina %d", disciplineID) {
+			http.Error(w, "Professor não habilitado na disciplina", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao remover habilitação do professor", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
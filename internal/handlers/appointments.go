@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// As aprovações da coordenação exigem o cabeçalho X-Teacher-ID: o professor só vê e analisa os
+// pedidos dos cursos que coordena e fica registrado como responsável pela análise. Os relatórios
+// por departamento aceitam o cabeçalho e, quando informado, mostram só os departamentos que ele chefia.
+
+// actingTeacher lê o professor que executa a operação (X-Teacher-ID), 0 se não informado
+func actingTeacher(r *http.Request) (int, error) {
+	v := strings.TrimSpace(r.Header.Get("X-Teacher-ID"))
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id < 1 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
+
+// coordinatorScope identifica o coordenador do X-Teacher-ID e retorna os cursos que ele coordena.
+// Em caso de erro a resposta já foi escrita e ok é false.
+func (h *Handler) coordinatorScope(w http.ResponseWriter, r *http.Request) (coordinator *models.Teacher, courseIDs []int, ok bool) {
+	teacherID, err := actingTeacher(r)
+	if err != nil {
+		http.Error(w, "X-Teacher-ID inválido", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if teacherID == 0 {
+		http.Error(w, "Informe o professor da coordenação (X-Teacher-ID)", http.StatusUnauthorized)
+		return nil, nil, false
+	}
+
+	coordinator, err = h.Teachers.GetByID(teacherID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar coordenação", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if coordinator == nil {
+		http.Error(w, "Professor não encontrado", http.StatusForbidden)
+		return nil, nil, false
+	}
+
+	courseIDs, err = h.Courses.CoordinatedBy(teacherID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar coordenação", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if len(courseIDs) == 0 {
+		http.Error(w, "Somente a coordenação de curso pode realizar esta operação", http.StatusForbidden)
+		return nil, nil, false
+	}
+	return coordinator, courseIDs, true
+}
+
+// authorizeStudentCourse confere se o professor do X-Teacher-ID coordena o curso do aluno e o retorna
+func (h *Handler) authorizeStudentCourse(w http.ResponseWriter, r *http.Request, studentID int) (*models.Teacher, bool) {
+	coordinator, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return nil, false
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return nil, false
+	}
+	return coordinator, h.authorizeCourse(w, courseIDs, student.CourseID)
+}
+
+func (h *Handler) authorizeCourse(w http.ResponseWriter, courseIDs []int, courseID int) bool {
+	if !slices.Contains(courseIDs, courseID) {
+		http.Error(w, "Somente a coordenação do curso pode realizar esta operação", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// departmentScope restringe o relatório aos departamentos chefiados pelo professor do X-Teacher-ID.
+// Sem departamento informado, usa o departamento chefiado (quando é um só).
+func (h *Handler) departmentScope(w http.ResponseWriter, r *http.Request, departmentID int) (int, bool) {
+	teacherID, err := actingTeacher(r)
+	if err != nil {
+		http.Error(w, "X-Teacher-ID inválido", http.StatusBadRequest)
+		return 0, false
+	}
+	if teacherID == 0 {
+		return departmentID, true
+	}
+
+	headed, err := h.Departments.HeadedBy(teacherID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar chefia de departamento", http.StatusInternalServerError)
+		return 0, false
+	}
+	if departmentID == 0 && len(headed) == 1 {
+		return headed[0], true
+	}
+	if departmentID == 0 && len(headed) > 1 {
+		http.Error(w, "Informe o department_id", http.StatusBadRequest)
+		return 0, false
+	}
+	if !slices.Contains(headed, departmentID) {
+		http.Error(w, "Somente a chefia do departamento pode consultar este relatório", http.StatusForbidden)
+		return 0, false
+	}
+	return departmentID, true
+}
+
+func (h *Handler) GetDepartmentHeadsHandler(w http.ResponseWriter, r *http.Request) {
+	departmentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || departmentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Departments.GetHeads(departmentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar chefias do departamento", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// AppointDepartmentHeadHandler nomeia o chefe do departamento; a chefia anterior é encerrada
+// na véspera do início e permanece no histórico
+func (h *Handler) AppointDepartmentHeadHandler(w http.ResponseWriter, r *http.Request) {
+	departmentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || departmentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.DepartmentHead
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.DepartmentID = departmentID
+
+	exists, err := h.Departments.Exists(departmentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar departamento", http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "Departamento não encontrado", http.StatusNotFound)
+		return
+	}
+
+	id, ok := h.appoint(w, r, &input.Appointment, func() (int, error) { return h.Departments.AppointHead(&input) })
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Chefe de departamento nomeado com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetCourseCoordinatorsHandler(w http.ResponseWriter, r *http.Request) {
+	courseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || courseID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Courses.GetCoordinators(courseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar coordenações do curso", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// AppointCourseCoordinatorHandler nomeia o coordenador do curso; a coordenação anterior é
+// encerrada na véspera do início e permanece no histórico
+func (h *Handler) AppointCourseCoordinatorHandler(w http.ResponseWriter, r *http.Request) {
+	courseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || courseID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.CourseCoordinator
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.CourseID = courseID
+
+	course, err := h.Courses.GetByID(courseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso", http.StatusInternalServerError)
+		return
+	}
+	if course == nil {
+		http.Error(w, "Curso não encontrado", http.StatusNotFound)
+		return
+	}
+
+	id, ok := h.appoint(w, r, &input.Appointment, func() (int, error) { return h.Courses.AppointCoordinator(&input) })
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Coordenador de curso nomeado com sucesso",
+		"id":      id,
+	})
+}
+
+// appoint valida o mandato e grava com save, escrevendo a resposta de erro quando falha
+func (h *Handler) appoint(w http.ResponseWriter, r *http.Request, a *models.Appointment, save func() (int, error)) (int, bool) {
+	if a.TeacherID < 1 || a.StartDate.IsZero() {
+		http.Error(w, "Informe o professor e a data de início", http.StatusBadRequest)
+		return 0, false
+	}
+	if a.EndDate != nil && a.EndDate.Before(a.StartDate) {
+		http.Error(w, "A data de término deve ser posterior à data de início.", http.StatusBadRequest)
+		return 0, false
+	}
+
+	teacher, err := h.Teachers.GetByID(a.TeacherID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar professor", http.StatusInternalServerError)
+		return 0, false
+	}
+	if teacher == nil {
+		http.Error(w, "Professor não encontrado", http.StatusNotFound)
+		return 0, false
+	}
+	if !teacher.Active {
+		http.Error(w, "Professor inativo não pode ser nomeado", http.StatusUnprocessableEntity)
+		return 0, false
+	}
+	a.AssignedBy = requestUser(r)
+
+	id, err := save()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "nomeação: ") {
+			http.Error(w, strings.TrimPrefix(err.Error(), "nomeação: "), http.StatusUnprocessableEntity)
+			return 0, false
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao registrar nomeação", http.StatusInternalServerError)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (h *Handler) GetSemesterCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Calendar.GetBySemester(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar calendário acadêmico", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) CreateCalendarEventHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.CalendarEvent
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.SemesterID = semesterID
+
+	if msg := validateCalendarEvent(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.Calendar.Create(&input)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao criar evento do calendário", http.StatusInternalServerError)
+		return
+	}
+	h.refreshPlannedHours(semesterID)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "message": "Evento cadastrado!"})
+}
+
+func (h *Handler) UpdateCalendarEventHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.Calendar.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar evento do calendário", http.StatusInternalServerError)
+		return
+	}
+	if current == nil {
+		http.Error(w, "Evento não encontrado", http.StatusNotFound)
+		return
+	}
+
+	var input models.CalendarEvent
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.ID = id
+	input.SemesterID = current.SemesterID
+
+	if msg := validateCalendarEvent(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Calendar.Update(&input); err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno ao atualizar", http.StatusBadRequest)
+		return
+	}
+	h.refreshPlannedHours(current.SemesterID)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Evento atualizado com sucesso"})
+}
+
+func (h *Handler) DeleteCalendarEventHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.Calendar.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar evento do calendário", http.StatusInternalServerError)
+		return
+	}
+	if current == nil {
+		http.Error(w, "Evento não encontrado", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Calendar.Delete(id); err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno ao deletar evento", http.StatusBadRequest)
+		return
+	}
+	h.refreshPlannedHours(current.SemesterID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportCalendarHandler importa o calendário de um arquivo CSV (enviado no corpo ou no campo "file").
+// Colunas: tipo, início, fim, descrição. Aceita "," ou ";" como separador e datas em AAAA-MM-DD ou DD/MM/AAAA.
+// Com ?replace=true o calendário atual do semestre é substituído.
+func (h *Handler) ImportCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Arquivo não enviado no campo 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	events, rowErrors := parseCalendarCSV(body, semesterID)
+	if len(rowErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Arquivo com erros, nada foi importado",
+			"errors":  rowErrors,
+		})
+		return
+	}
+
+	replace := r.URL.Query().Get("replace") == "true"
+	if err := h.Calendar.Import(semesterID, events, replace); err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao importar calendário", http.StatusInternalServerError)
+		return
+	}
+	h.refreshPlannedHours(semesterID)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Calendário importado com sucesso",
+		"imported": len(events),
+	})
+}
+
+func parseCalendarCSV(body io.Reader, semesterID int) ([]models.CalendarEvent, []string) {
+	br := bufio.NewReader(body)
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _ := br.Peek(br.Size()); strings.Contains(strings.SplitN(string(first), "\n", 2)[0], ";") {
+		reader.Comma = ';'
+	}
+
+	var events []models.CalendarEvent
+	var rowErrors []string
+
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %v", line, err))
+			continue
+		}
+
+		// Cabeçalho opcional
+		if line == 1 {
+			if first := strings.ToLower(strings.TrimSpace(record[0])); first == "kind" || first == "tipo" {
+				continue
+			}
+		}
+
+		if len(record) < 4 {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: esperado tipo, início, fim e descrição", line))
+			continue
+		}
+
+		e := models.CalendarEvent{SemesterID: semesterID, Description: strings.TrimSpace(record[3])}
+
+		kind, ok := models.ParseCalendarKind(record[0])
+		if !ok {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: tipo inválido %q", line, record[0]))
+			continue
+		}
+		e.Kind = kind
+
+		if e.StartDate, err = parseDate(record[1]); err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %v", line, err))
+			continue
+		}
+		if e.EndDate, err = parseDate(record[2]); err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %v", line, err))
+			continue
+		}
+
+		if msg := validateCalendarEvent(&e); msg != "" {
+			rowErrors = append(rowErrors, fmt.Sprintf("linha %d: %s", line, msg))
+			continue
+		}
+		events = append(events, e)
+	}
+
+	return events, rowErrors
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q", s)
+}
+
+func validateCalendarEvent(e *models.CalendarEvent) string {
+	kind, ok := models.ParseCalendarKind(e.Kind)
+	if !ok {
+		return "Tipo inválido. Use holiday, recess ou exam_week."
+	}
+	e.Kind = kind
+
+	if e.StartDate.IsZero() || e.EndDate.IsZero() {
+		return "As datas de início e fim são obrigatórias."
+	}
+	if e.EndDate.Before(e.StartDate) {
+		return "A data de término deve ser posterior à data de início."
+	}
+	if strings.TrimSpace(e.Description) == "" {
+		return "A descrição é obrigatória."
+	}
+	return ""
+}
+
+// nonTeachingDays retorna uma função que indica se a data é feriado ou recesso no semestre
+func (h *Handler) nonTeachingDays(semesterID int) (func(time.Time) bool, error) {
+	events, err := h.Calendar.GetBySemester(semesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(day time.Time) bool {
+		for _, e := range events {
+			if e.NonTeaching() && e.Covers(day) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// checkTeachingDay valida se a data está no período do semestre e não é dia sem aula.
+// Retorna uma mensagem para o usuário quando a data não é letiva.
+func (h *Handler) checkTeachingDay(semesterID int, day time.Time) (string, error) {
+	semester, err := h.Semesters.GetByID(semesterID)
+	if err != nil {
+		return "", err
+	}
+
+	if (semester.StartDate != nil && day.Before(*semester.StartDate)) ||
+		(semester.EndDate != nil && day.After(*semester.EndDate)) {
+		return fmt.Sprintf("Data %s fora do período do semestre %s", day.Format("02/01/2006"), semester.String()), nil
+	}
+
+	events, err := h.Calendar.GetBySemester(semesterID)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range events {
+		if e.NonTeaching() && e.Covers(day) {
+			return fmt.Sprintf("Data %s não é dia letivo: %s", day.Format("02/01/2006"), e.Description), nil
+		}
+	}
+	return "", nil
+}
+
+// plannedHours soma a duração dos encontros da oferta nos dias letivos do semestre
+func plannedHours(schedule string, semester *models.AcademicSemester, skip func(time.Time) bool) (int, error) {
+	slots, err := academic.ParseSchedule(schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for _, occ := range academic.Occurrences(slots, *semester.StartDate, *semester.EndDate, skip) {
+		total += occ.End.Sub(occ.Start).Hours()
+	}
+	return int(math.Round(total)), nil
+}
+
+// refreshPlannedHours recalcula a carga horária prevista das ofertas do semestre: pela soma das aulas
+// nas ofertas com aulas geradas e, nas demais, pelo horário e pelo calendário. Falhas são apenas
+// registradas no log: sem carga prevista, a frequência usa a carga da disciplina.
+func (h *Handler) refreshPlannedHours(semesterID int) {
+	semester, err := h.Semesters.GetByID(semesterID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if semester == nil || semester.StartDate == nil || semester.EndDate == nil {
+		return
+	}
+
+	skip, err := h.nonTeachingDays(semesterID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	offers, err := h.Offers.GetAll(semesterID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, o := range offers {
+		// Ofertas com aulas geradas usam sempre a soma das aulas, a mesma conta do Sessions.Sync
+		total, err := h.Sessions.CountByOffer(o.ID)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if total > 0 {
+			if err := h.Sessions.RefreshPlannedHours(o.ID); err != nil {
+				log.Println(err)
+			}
+			continue
+		}
+
+		hours, err := plannedHours(o.Schedule, semester, skip)
+		if err != nil {
+			log.Printf("oferta %d com horário inválido %q: %v", o.ID, o.Schedule, err)
+			continue
+		}
+		if err := h.Offers.SetPlannedHours(o.ID, hours); err != nil {
+			log.Println(err)
+		}
+	}
+}
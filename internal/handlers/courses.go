@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"sistema-faculdade/internal/export"
 	"sistema-faculdade/internal/models"
+	"strconv"
 )
 
 func (h *Handler) CreateCourseHandler(w http.ResponseWriter, r *http.Request) {
@@ -18,6 +21,14 @@ func (h *Handler) CreateCourseHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.Courses.Create(&input)
 	if err != nil {
+		switch err.Error() {
+		case "sigla de curso já cadastrada":
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case "a sigla do curso deve ter apenas letras e números":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		log.Println(err)
 		http.Error(w, "Erro ao criar curso", http.StatusInternalServerError)
 		return
@@ -31,6 +42,26 @@ func (h *Handler) CreateCourseHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllCoursesHandler(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{
+			"id", "sigla", "nome", "creditos_exigidos", "duracao_semestres", "prazo_maximo_semestres",
+			"creditos_min_semestre", "creditos_max_semestre", "aprovacao_matricula", "coordenador",
+		}
+		exportList(w, format, "cursos", header, func(xw export.Writer) error {
+			return h.Courses.Each(func(c models.Course) error {
+				return xw.Row(
+					c.ID, c.Code, c.Name, c.TotalCreditsRequired, c.DurationSemesters, c.MaxDuration(),
+					c.MinCreditsPerSemester, c.MaxCreditsPerSemester, c.RequiresEnrollmentApproval, c.CoordinatorName,
+				)
+			})
+		})
+		return
+	}
+
 	courses, err := h.Courses.GetAll()
 	if err != nil {
 		log.Println(err)
@@ -41,3 +72,85 @@ func (h *Handler) GetAllCoursesHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(courses)
 }
+
+func (h *Handler) GetCurriculumHandler(w http.ResponseWriter, r *http.Request) {
+	courseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || courseID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Courses.GetCurriculum(courseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matriz curricular", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// SaveCurriculumItemHandler inclui uma disciplina na matriz do curso (ou atualiza semestre sugerido e obrigatoriedade)
+func (h *Handler) SaveCurriculumItemHandler(w http.ResponseWriter, r *http.Request) {
+	courseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || courseID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	input := models.CurriculumItem{Mandatory: true}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.CourseID = courseID
+
+	if input.DisciplineID < 1 {
+		http.Error(w, "Informe a disciplina", http.StatusBadRequest)
+		return
+	}
+	if input.SuggestedSemester != nil && *input.SuggestedSemester < 1 {
+		http.Error(w, "Semestre sugerido inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Courses.SaveCurriculumItem(&input); err != nil {
+		if err.Error() == "curso ou disciplina inexistente" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao salvar matriz curricular", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Matriz curricular atualizada com sucesso"})
+}
+
+func (h *Handler) RemoveCurriculumItemHandler(w http.ResponseWriter, r *http.Request) {
+	courseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || courseID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+	disciplineID, err := strconv.Atoi(r.PathValue("discipline_id"))
+	if err != nil || disciplineID < 1 {
+		http.Error(w, "ID da disciplina inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Courses.RemoveCurriculumItem(courseID, disciplineID)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("disciplina %d não faz parte da matriz do curso", disciplineID) {
+			http.Error(w, "Disciplina não faz parte da matriz do curso", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao remover disciplina da matriz", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) GetEquivalencesHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := h.Equivalences.GetAll()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar equivalências", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// CreateEquivalenceHandler cadastra uma equivalência; várias disciplinas de origem podem equivaler a uma só
+func (h *Handler) CreateEquivalenceHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.Equivalence
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if input.TargetDisciplineID < 1 || len(input.SourceDisciplineIDs) == 0 {
+		http.Error(w, "Informe a disciplina alvo e ao menos uma disciplina de origem", http.StatusBadRequest)
+		return
+	}
+	for _, id := range input.SourceDisciplineIDs {
+		if id == input.TargetDisciplineID {
+			http.Error(w, "A disciplina alvo não pode ser também disciplina de origem", http.StatusBadRequest)
+			return
+		}
+	}
+
+	id, err := h.Equivalences.Create(&input)
+	if err != nil {
+		if err.Error() == "disciplina inexistente" {
+			http.Error(w, "Disciplina não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao criar equivalência", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Equivalência criada com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) DeleteEquivalenceHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Equivalences.Delete(id)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma equivalência encontrada com o ID %d", id) {
+			http.Error(w, "Equivalência não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao deletar equivalência", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateExternalCreditHandler registra o pedido de aproveitamento de disciplina cursada em outra instituição
+func (h *Handler) CreateExternalCreditHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.ExternalCredit
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.StudentID = studentID
+
+	if input.DisciplineID < 1 || strings.TrimSpace(input.Institution) == "" || strings.TrimSpace(input.ExternalName) == "" {
+		http.Error(w, "Informe a disciplina, a instituição e o nome da disciplina cursada", http.StatusBadRequest)
+		return
+	}
+	if input.ExternalWorkloadHours < 1 || input.CompletedYear < 1900 {
+		http.Error(w, "Informe a carga horária e o ano de conclusão", http.StatusBadRequest)
+		return
+	}
+	if input.Grade != nil && (*input.Grade < 0 || *input.Grade > 100) {
+		http.Error(w, "Nota deve estar entre 0 e 100", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	id, err := h.ExternalCredits.Create(&input)
+	if err != nil {
+		switch err.Error() {
+		case "já existe pedido de aproveitamento para esta disciplina", "o aluno já cumpriu esta disciplina":
+			http.Error(w, err.Error(), http.StatusConflict)
+		case "disciplina inexistente":
+			http.Error(w, "Disciplina não encontrada", http.StatusNotFound)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao registrar aproveitamento", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Pedido de aproveitamento registrado com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetStudentExternalCreditsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.ExternalCredits.GetByStudent(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aproveitamentos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetExternalCreditsHandler lista os pedidos de aproveitamento para a coordenação (?status=pending)
+func (h *Handler) GetExternalCreditsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != models.RequestPending && status != models.RequestApproved && status != models.RequestRejected {
+		http.Error(w, "Situação inválida", http.StatusBadRequest)
+		return
+	}
+
+	_, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.ExternalCredits.GetAll(status, courseIDs)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aproveitamentos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// ReviewExternalCreditHandler aprova ou rejeita o pedido de aproveitamento conforme a rota
+func (h *Handler) ReviewExternalCreditHandler(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || id < 1 {
+			http.Error(w, "ID inválido", http.StatusBadRequest)
+			return
+		}
+
+		var input struct {
+			Notes string `json:"notes"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		credit, err := h.ExternalCredits.GetByID(id)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar aproveitamento", http.StatusInternalServerError)
+			return
+		}
+		if credit == nil {
+			http.Error(w, "Aproveitamento não encontrado", http.StatusNotFound)
+			return
+		}
+		coordinator, ok := h.authorizeStudentCourse(w, r, credit.StudentID)
+		if !ok {
+			return
+		}
+
+		status, message := models.RequestRejected, "Aproveitamento rejeitado"
+		if approve {
+			status, message = models.RequestApproved, "Aproveitamento aprovado"
+		}
+
+		err = h.ExternalCredits.Review(id, status, coordinator.Name, input.Notes)
+		if err != nil {
+			switch err.Error() {
+			case fmt.Sprintf("nenhum aproveitamento encontrado com o ID %d", id):
+				http.Error(w, "Aproveitamento não encontrado", http.StatusNotFound)
+			case "solicitação já analisada":
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				log.Println(err)
+				http.Error(w, "Erro ao analisar aproveitamento", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": message})
+	}
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/diploma"
+	"sistema-faculdade/internal/documents"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// graduationAudit confere os requisitos de conclusão do curso atual do aluno. Também retorna o curso
+// e o histórico usados, para a emissão do diploma.
+func (h *Handler) graduationAudit(w http.ResponseWriter, student *models.Student) (*models.GraduationAudit, *models.Course, *models.Transcript, bool) {
+	course, err := h.Courses.GetByID(student.CourseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso do aluno", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	if course == nil {
+		http.Error(w, "O aluno não está vinculado a um curso", http.StatusUnprocessableEntity)
+		return nil, nil, nil, false
+	}
+
+	curriculum, err := h.Courses.GetCurriculum(course.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matriz curricular", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	fulfilled, err := h.Transcripts.FulfilledDisciplines(student.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico do aluno", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	transcript, err := h.Transcripts.Get(student.ID)
+	if err != nil || transcript == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico do aluno", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+
+	audit := academic.AuditGraduation(course, curriculum, fulfilled, transcript.EarnedCredits)
+	audit.StudentID = student.ID
+	return &audit, course, transcript, true
+}
+
+// GetGraduationAuditHandler mostra se o aluno cumpriu os requisitos para a colação de grau
+func (h *Handler) GetGraduationAuditHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aluno", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	audit, _, _, ok := h.graduationAudit(w, student)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(audit)
+}
+
+// IssueDiplomaHandler emite o diploma digital do aluno aprovado na auditoria de conclusão: gera o
+// XML, confere contra o XSD, assina e registra no livro de diplomas. O aluno matriculado passa a
+// formado na data da colação (graduation_date, padrão hoje).
+func (h *Handler) IssueDiplomaHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		GraduationDate string `json:"graduation_date"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	graduationDate := time.Now()
+	if input.GraduationDate != "" {
+		graduationDate, err = time.Parse(time.DateOnly, input.GraduationDate)
+		if err != nil {
+			http.Error(w, "graduation_date inválida; use AAAA-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if graduationDate.After(time.Now()) {
+			http.Error(w, "A data da colação não pode estar no futuro", http.StatusBadRequest)
+			return
+		}
+	}
+	graduationDate = time.Date(graduationDate.Year(), graduationDate.Month(), graduationDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	if !h.Signer.CanSign() {
+		http.Error(w, "Assinatura digital não configurada", http.StatusServiceUnavailable)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aluno", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if student.Status != models.StudentEnrolled && student.Status != models.StudentGraduated {
+		http.Error(w, "O aluno não está matriculado nem formado", http.StatusUnprocessableEntity)
+		return
+	}
+
+	audit, course, transcript, ok := h.graduationAudit(w, student)
+	if !ok {
+		return
+	}
+	if !audit.Eligible {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(audit)
+		return
+	}
+
+	d := models.Diploma{
+		StudentID:      student.ID,
+		StudentName:    student.Name,
+		CourseID:       course.ID,
+		CourseName:     course.Name,
+		GraduationDate: graduationDate,
+		SignatureKeyID: h.Signer.SigningKey(),
+		IssuedBy:       requestUser(r),
+		IssuedAt:       time.Now().Truncate(time.Second),
+	}
+
+	// O código e o número de registro entram no XML assinado, então um novo sorteio exige gerar o XML de novo
+	var xml []byte
+	for attempt := 1; ; attempt++ {
+		d.Code, err = documents.NewCode()
+		if err == nil {
+			xml, err = h.Diplomas.Issue(&d, func(registryNumber string) ([]byte, error) {
+				return diploma.Generate(diploma.Data{
+					Code:           d.Code,
+					RegistryNumber: registryNumber,
+					Institution:    h.Institution,
+					VerifyURL:      h.verifyURL(r, "diplomas/"+d.Code),
+					GraduationDate: graduationDate,
+					IssuedAt:       d.IssuedAt,
+					Student:        student,
+					Course:         course,
+					Transcript:     transcript,
+				}, h.Signer)
+			})
+		}
+		if err == nil {
+			break
+		}
+		if err.Error() == "código de verificação já utilizado" && attempt < 3 {
+			continue
+		}
+		switch {
+		case err.Error() == "diploma já emitido para o aluno neste curso":
+			http.Error(w, "Diploma já emitido para o aluno neste curso", http.StatusConflict)
+		case err.Error() == "o aluno não está matriculado nem formado":
+			http.Error(w, "O aluno não está matriculado nem formado", http.StatusUnprocessableEntity)
+		case strings.HasPrefix(err.Error(), "XML não confere com o XSD"):
+			// Dado cadastral fora do leiaute (CPF, sexo...) impede a emissão até ser corrigido
+			http.Error(w, "Dados do aluno incompatíveis com o leiaute do diploma: "+err.Error(), http.StatusUnprocessableEntity)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao emitir diploma", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="diploma-%s.xml"`, d.Code))
+	w.Header().Set("X-Verification-Code", d.Code)
+	w.Header().Set("X-Registry-Number", d.RegistryNumber)
+	w.Header().Set("X-Content-Hash", d.XMLHash)
+	w.WriteHeader(http.StatusCreated)
+	w.Write(xml)
+}
+
+// GetDiplomasHandler lista o livro de registro de diplomas, com filtros opcionais por ?course_id=,
+// ?student_id= e ?year= (ano da colação)
+func (h *Handler) GetDiplomasHandler(w http.ResponseWriter, r *http.Request) {
+	var filters [3]int
+	for i, name := range []string{"course_id", "student_id", "year"} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				http.Error(w, name+" inválido", http.StatusBadRequest)
+				return
+			}
+			filters[i] = n
+		}
+	}
+
+	list, err := h.Diplomas.GetAll(filters[0], filters[1], filters[2])
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar diplomas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetDiplomaXMLHandler devolve o XML assinado entregue na emissão
+func (h *Handler) GetDiplomaXMLHandler(w http.ResponseWriter, r *http.Request) {
+	code := documents.NormalizeCode(r.PathValue("code"))
+	xml, err := h.Diplomas.GetXML(code)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar diploma", http.StatusInternalServerError)
+		return
+	}
+	if xml == nil {
+		http.Error(w, "Diploma não encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="diploma-%s.xml"`, code))
+	w.Write(xml)
+}
+
+// GetDiplomaSchemaHandler publica o XSD do leiaute, para a validação fora do sistema
+func (h *Handler) GetDiplomaSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(diploma.XSD())
+}
+
+// VerifyDiplomaHandler é a verificação pública do diploma pelo código de validação. A assinatura é
+// conferida de novo sobre o XML registrado, com as chaves públicas ativas.
+func (h *Handler) VerifyDiplomaHandler(w http.ResponseWriter, r *http.Request) {
+	code := documents.NormalizeCode(r.PathValue("code"))
+	result := models.DiplomaVerification{Code: code}
+
+	d, err := h.Diplomas.GetByCode(code)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar diploma", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusNotFound
+	if d != nil {
+		xml, err := h.Diplomas.GetXML(code)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao verificar diploma", http.StatusInternalServerError)
+			return
+		}
+		student, err := h.Students.GetByID(d.StudentID)
+		if err != nil || student == nil {
+			log.Println(err)
+			http.Error(w, "Erro ao verificar diploma", http.StatusInternalServerError)
+			return
+		}
+
+		status = http.StatusOK
+		result.Valid = true
+		result.RegistryNumber = d.RegistryNumber
+		result.StudentName = d.StudentName
+		result.CPF = documents.MaskCPF(student.CPF)
+		result.CourseName = d.CourseName
+		result.GraduationDate = &d.GraduationDate
+		result.IssuedAt = &d.IssuedAt
+		result.XMLHash = d.XMLHash
+
+		_, err = diploma.Verify(h.Signer, xml)
+		signatureValid := err == nil
+		result.SignatureValid = &signatureValid
+		if err != nil {
+			result.Reason = err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// checkDiplomaSignature confere um diploma XML enviado para /verify/signature
+func (h *Handler) checkDiplomaSignature(w http.ResponseWriter, body io.Reader) (models.SignatureVerification, bool) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, "Arquivo grande demais ou ilegível", http.StatusRequestEntityTooLarge)
+		return models.SignatureVerification{}, false
+	}
+	info, err := diploma.Verify(h.Signer, content)
+	if info == nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return models.SignatureVerification{}, false
+	}
+
+	result := models.SignatureVerification{KeyID: info.KeyID, Kind: models.DocumentDiploma, Valid: err == nil}
+	if err != nil {
+		result.Reason = err.Error()
+	}
+	return result, true
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sistema-faculdade/internal/export"
 	"sistema-faculdade/internal/models"
 	"strconv"
 )
@@ -29,6 +30,20 @@ func (h *Handler) CreateDisciplinesHandler(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *Handler) GetAllDisciplinesHandler(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{"id", "codigo", "nome", "creditos", "carga_horaria", "departamento", "descricao"}
+		exportList(w, format, "disciplinas", header, func(xw export.Writer) error {
+			return h.Disciplines.Each(func(d models.Discipline) error {
+				return xw.Row(d.ID, d.Code, d.Name, d.Credits, d.WorkloadHours, d.DepartmentName, d.Description)
+			})
+		})
+		return
+	}
+
 	list, err := h.Disciplines.GetAll()
 	if err != nil {
 		log.Println(err)
@@ -113,3 +128,83 @@ func (h *Handler) GetDisciplineByIDHandler(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(d)
 }
+
+func (h *Handler) GetPrerequisitesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Disciplines.GetPrerequisites(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pré-requisitos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) AddPrerequisiteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		PrerequisiteID int `json:"prerequisite_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.PrerequisiteID < 1 || input.PrerequisiteID == id {
+		http.Error(w, "Pré-requisito inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Disciplines.AddPrerequisite(id, input.PrerequisiteID)
+	if err != nil {
+		switch err.Error() {
+		case "disciplina inexistente":
+			http.Error(w, "Disciplina não encontrada", http.StatusNotFound)
+		case "pré-requisito criaria uma dependência circular":
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao cadastrar pré-requisito", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pré-requisito cadastrado com sucesso"})
+}
+
+func (h *Handler) RemovePrerequisiteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+	prerequisiteID, err := strconv.Atoi(r.PathValue("prerequisite_id"))
+	if err != nil || prerequisiteID < 1 {
+		http.Error(w, "ID do pré-requisito inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Disciplines.RemovePrerequisite(id, prerequisiteID); err != nil {
+		if err.Error() == "pré-requisito não encontrado" {
+			http.Error(w, "Pré-requisito não encontrado", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao remover pré-requisito", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
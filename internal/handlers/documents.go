@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/documents"
+	"sistema-faculdade/internal/models"
+	"sistema-faculdade/internal/signing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var verifyTemplate = template.Must(template.New("verify.html").Funcs(template.FuncMap{
+	"deref": func(b *bool) bool { return *b },
+	"title": func(kind string) string { return documents.Titles[kind] },
+}).ParseFS(templateFS, "templates/verify.html"))
+
+// documentKinds associa o tipo na URL ao tipo do documento
+var documentKinds = map[string]string{
+	"enrollment-declaration": models.DocumentEnrollment,
+	"attendance-declaration": models.DocumentAttendance,
+	"transcript":             models.DocumentTranscript,
+}
+
+// IssueDocumentHandler emite o PDF de uma declaração ou do histórico escolar do aluno e registra a
+// emissão. As declarações são do semestre informado em ?semester_id= ou, sem ele, do semestre em
+// andamento.
+func (h *Handler) IssueDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+	kind, ok := documentKinds[r.PathValue("kind")]
+	if !ok {
+		http.Error(w, "Tipo de documento inválido. Use enrollment-declaration, attendance-declaration ou transcript.", http.StatusNotFound)
+		return
+	}
+
+	if !h.Signer.CanSign() {
+		http.Error(w, "Assinatura digital não configurada", http.StatusServiceUnavailable)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aluno", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	content := models.DocumentContent{
+		Institution:        h.Institution,
+		StudentName:        student.Name,
+		CPF:                documents.MaskCPF(student.CPF),
+		RegistrationNumber: student.RegistrationNumber,
+		CourseName:         student.CourseName,
+	}
+	var semesterID *int
+
+	switch kind {
+	case models.DocumentEnrollment, models.DocumentAttendance:
+		if kind == models.DocumentEnrollment && student.Status != models.StudentEnrolled {
+			http.Error(w, "O aluno não está com a matrícula ativa", http.StatusUnprocessableEntity)
+			return
+		}
+
+		semester, ok := h.documentSemester(w, r)
+		if !ok {
+			return
+		}
+		semesterID = &semester.ID
+		content.Semester = semester.String()
+
+		content.Disciplines, err = h.Documents.Disciplines(studentID, semester.ID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar disciplinas do aluno", http.StatusInternalServerError)
+			return
+		}
+		if kind == models.DocumentAttendance && len(content.Disciplines) == 0 {
+			http.Error(w, "O aluno não cursou disciplinas no semestre "+content.Semester, http.StatusUnprocessableEntity)
+			return
+		}
+	case models.DocumentTranscript:
+		content.Transcript, err = h.Transcripts.Get(studentID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao gerar histórico escolar", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	snapshot, err := json.Marshal(content)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao emitir documento", http.StatusInternalServerError)
+		return
+	}
+
+	doc := models.IssuedDocument{
+		Kind:        kind,
+		StudentID:   studentID,
+		StudentName: student.Name,
+		SemesterID:  semesterID,
+		Content:     snapshot,
+		IssuedBy:    requestUser(r),
+		IssuedAt:    time.Now().Truncate(time.Second),
+	}
+
+	// O código entra no PDF, então um novo sorteio exige gerar o PDF de novo
+	var pdf []byte
+	for attempt := 1; ; attempt++ {
+		doc.Code, err = documents.NewCode()
+		if err == nil {
+			pdf, err = documents.Render(documents.Issue{
+				Kind:      kind,
+				Code:      doc.Code,
+				IssuedAt:  doc.IssuedAt,
+				VerifyURL: h.verifyURL(r, doc.Code),
+				CPF:       student.CPF,
+				Content:   content,
+			})
+		}
+		var sig signing.Signature
+		if err == nil {
+			sig, err = h.Signer.Sign(pdf)
+		}
+		if err == nil {
+			doc.ContentHash = documents.Hash(pdf)
+			doc.SignatureKeyID, doc.Signature = sig.KeyID, sig.Value
+			err = h.Documents.Create(&doc, pdf)
+		}
+		if err == nil {
+			break
+		}
+		if err.Error() == "código de verificação já utilizado" && attempt < 3 {
+			continue
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao emitir documento", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.pdf"`, r.PathValue("kind"), doc.Code))
+	w.Header().Set("X-Verification-Code", doc.Code)
+	w.Header().Set("X-Content-Hash", doc.ContentHash)
+	w.Header().Set("X-Signature-Key-ID", doc.SignatureKeyID)
+	w.Header().Set("X-Signature", doc.Signature)
+	w.WriteHeader(http.StatusCreated)
+	w.Write(pdf)
+}
+
+// documentSemester é o semestre de ?semester_id= ou o semestre em andamento
+func (h *Handler) documentSemester(w http.ResponseWriter, r *http.Request) (*models.AcademicSemester, bool) {
+	param := r.URL.Query().Get("semester_id")
+	if param == "" {
+		semester, err := h.Semesters.Current()
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar semestre", http.StatusInternalServerError)
+			return nil, false
+		}
+		if semester == nil {
+			http.Error(w, "Não há semestre em andamento; informe o semester_id", http.StatusUnprocessableEntity)
+			return nil, false
+		}
+		return semester, true
+	}
+
+	id, err := strconv.Atoi(param)
+	if err != nil || id < 1 {
+		http.Error(w, "semester_id inválido", http.StatusBadRequest)
+		return nil, false
+	}
+	semester, err := h.Semesters.GetByID(id)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "não encontrado") {
+			http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+			return nil, false
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao buscar semestre", http.StatusInternalServerError)
+		return nil, false
+	}
+	return semester, true
+}
+
+// verifyURL é o endereço público de verificação impresso no documento. Sem PUBLIC_BASE_URL, usa o
+// host da própria requisição.
+func (h *Handler) verifyURL(r *http.Request, code string) string {
+	base := strings.TrimSuffix(h.PublicURL, "/")
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return base + "/verify/" + code
+}
+
+func (h *Handler) GetStudentDocumentsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Documents.GetByStudent(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar documentos emitidos", http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		list = []models.IssuedDocument{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetDocumentPDFHandler devolve a cópia do PDF entregue na emissão
+func (h *Handler) GetDocumentPDFHandler(w http.ResponseWriter, r *http.Request) {
+	code := documents.NormalizeCode(r.PathValue("code"))
+	pdf, err := h.Documents.GetPDF(code)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar documento", http.StatusInternalServerError)
+		return
+	}
+	if pdf == nil {
+		http.Error(w, "Documento não encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, code))
+	w.Write(pdf)
+}
+
+// VerifyDocumentHandler é a verificação pública do documento pelo código impresso (ou lido no QR
+// Code). Com ?hash=, confere também o SHA-256 do arquivo apresentado. Responde em HTML para
+// navegadores e em JSON para os demais clientes.
+func (h *Handler) VerifyDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	code := documents.NormalizeCode(r.PathValue("code"))
+	result := models.DocumentVerification{Code: code}
+
+	doc, err := h.Documents.GetByCode(code)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar documento", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusNotFound
+	if doc != nil {
+		var content models.DocumentContent
+		if err := json.Unmarshal(doc.Content, &content); err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao verificar documento", http.StatusInternalServerError)
+			return
+		}
+		// O histórico completo fica de fora da verificação pública
+		content.Transcript = nil
+
+		status = http.StatusOK
+		result.Valid = true
+		result.Kind = doc.Kind
+		result.IssuedAt = &doc.IssuedAt
+		result.ContentHash = doc.ContentHash
+		result.Content = &content
+		if hash := strings.TrimSpace(r.URL.Query().Get("hash")); hash != "" {
+			matches := strings.EqualFold(hash, doc.ContentHash)
+			result.HashMatches = &matches
+		}
+
+		// A assinatura é conferida sobre a cópia registrada do PDF, com as chaves públicas ativas
+		pdf, err := h.Documents.GetPDF(code)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao verificar documento", http.StatusInternalServerError)
+			return
+		}
+		signatureValid := h.Signer.Verify(pdf, documentSignature(doc)) == nil
+		result.SignatureValid = &signatureValid
+		result.SignatureKeyID = doc.SignatureKeyID
+		result.Signature = doc.Signature
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		if err := verifyTemplate.Execute(w, result); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+func documentSignature(doc *models.IssuedDocument) signing.Signature {
+	return signing.Signature{KeyID: doc.SignatureKeyID, Algorithm: signing.Algorithm, Value: doc.Signature}
+}
+
+// GetDocumentSignatureHandler devolve a assinatura destacada do PDF (arquivo .sig), para a
+// verificação fora do sistema
+func (h *Handler) GetDocumentSignatureHandler(w http.ResponseWriter, r *http.Request) {
+	code := documents.NormalizeCode(r.PathValue("code"))
+	doc, err := h.Documents.GetByCode(code)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar documento", http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.Error(w, "Documento não encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf.sig"`, code))
+	json.NewEncoder(w).Encode(documentSignature(doc))
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+// CreateEnrollmentRequestsHandler registra os pedidos de matrícula do aluno nas ofertas escolhidas
+func (h *Handler) CreateEnrollmentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		OfferIDs []int `json:"offer_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(input.OfferIDs) == 0 {
+		http.Error(w, "Informe ao menos uma oferta", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if student.Status != models.StudentEnrolled {
+		http.Error(w, "Somente alunos com situação 'enrolled' podem ser matriculados", http.StatusUnprocessableEntity)
+		return
+	}
+
+	ids, err := h.EnrollmentRequests.Create(studentID, input.OfferIDs)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "já existe pedido") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if strings.HasSuffix(err.Error(), "não encontrada") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao criar pedidos de matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Pedidos de matrícula enviados para a coordenação",
+		"ids":     ids,
+	})
+}
+
+func (h *Handler) GetStudentEnrollmentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	semesterID := 0
+	if v := r.URL.Query().Get("semester_id"); v != "" {
+		semesterID, err = strconv.Atoi(v)
+		if err != nil || semesterID < 1 {
+			http.Error(w, "semester_id inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.EnrollmentRequests.GetByStudent(studentID, semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pedidos de matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetEnrollmentRequestsHandler lista os pedidos para a coordenação (?status=pending)
+func (h *Handler) GetEnrollmentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != models.RequestPending && status != models.RequestApproved && status != models.RequestRejected {
+		http.Error(w, "Situação inválida", http.StatusBadRequest)
+		return
+	}
+
+	_, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.EnrollmentRequests.GetAll(status, courseIDs)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pedidos de matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// ReviewEnrollmentRequestsHandler analisa de uma vez os pedidos pendentes do aluno no semestre.
+// Rejeições exigem motivo e podem sugerir uma oferta alternativa.
+func (h *Handler) ReviewEnrollmentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		SemesterID int                         `json:"semester_id"`
+		Decisions  []models.EnrollmentDecision `json:"decisions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.SemesterID < 1 || len(input.Decisions) == 0 {
+		http.Error(w, "Informe o semestre e as decisões", http.StatusBadRequest)
+		return
+	}
+	for _, d := range input.Decisions {
+		if !d.Approve && strings.TrimSpace(d.Reason) == "" {
+			http.Error(w, "Informe o motivo de cada rejeição", http.StatusBadRequest)
+			return
+		}
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	course, err := h.Courses.GetByID(student.CourseID)
+	if err != nil || course == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	coordinator, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok || !h.authorizeCourse(w, courseIDs, course.ID) {
+		return
+	}
+
+	err = h.EnrollmentRequests.Review(studentID, input.SemesterID, input.Decisions, coordinator.Name, course)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "pedido de matrícula: ") || strings.HasPrefix(err.Error(), "limite de créditos: ") {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if strings.HasSuffix(err.Error(), "sem vagas disponíveis") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao analisar pedidos de matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	list, err := h.EnrollmentRequests.GetByStudent(studentID, input.SemesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pedidos de matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
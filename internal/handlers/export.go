@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"sistema-faculdade/internal/export"
+	"strings"
+)
+
+// exportFormat retorna o formato pedido pela listagem: ?format=csv|xlsx ou, sem o parâmetro, o
+// Accept (text/csv ou o tipo do XLSX). Vazio mantém a resposta em JSON; formato inválido responde 400.
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if f := strings.ToLower(r.URL.Query().Get("format")); f != "" {
+		switch f {
+		case export.CSV, export.XLSX:
+			return f, true
+		case "json":
+			return "", true
+		}
+		http.Error(w, "Formato inválido. Use csv, xlsx ou json.", http.StatusBadRequest)
+		return "", false
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" {
+			return "", true
+		}
+		for format, contentType := range export.ContentTypes {
+			if ct, _, _ := mime.ParseMediaType(contentType); ct == mediaType {
+				return format, true
+			}
+		}
+	}
+	return "", true
+}
+
+// responseTracker registra se algum byte já foi enviado ao cliente
+type responseTracker struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (t *responseTracker) Write(b []byte) (int, error) {
+	t.wrote = true
+	return t.ResponseWriter.Write(b)
+}
+
+// exportList grava a listagem no formato pedido, um registro por vez: each percorre o
+// repositório e chama xw.Row para cada registro. Se a leitura falhar antes de qualquer byte ser
+// enviado a resposta vira um erro 500; depois disso o arquivo sai truncado e o erro vai para o log.
+func exportList(w http.ResponseWriter, format, filename string, header []string, each func(xw export.Writer) error) {
+	w.Header().Set("Content-Type", export.ContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	tracker := &responseTracker{ResponseWriter: w}
+	xw, err := export.New(tracker, format, filename, header)
+	if err == nil {
+		err = each(xw)
+	}
+	if err == nil {
+		err = xw.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	log.Printf("Erro ao exportar %s: %v", filename, err)
+	if !tracker.wrote {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "Erro ao exportar listagem", http.StatusInternalServerError)
+	}
+}
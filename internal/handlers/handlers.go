@@ -1,17 +1,43 @@
 package handlers
 
 import (
+	"net/http"
 	"sistema-faculdade/internal/data"
+	"sistema-faculdade/internal/signing"
+	"strings"
 )
 
 type Handler struct {
-	Students    data.StudentRepository
-	Teachers    data.TeacherRepository
-	Courses     data.CourseRepository
-	Departments data.DepartmentRepository
-	Disciplines data.DisciplineRepository
-	Semesters   data.SemesterRepository
-	Dashboard   data.DashboardRepository
+	Students           data.StudentRepository
+	Teachers           data.TeacherRepository
+	Courses            data.CourseRepository
+	Departments        data.DepartmentRepository
+	Disciplines        data.DisciplineRepository
+	Semesters          data.SemesterRepository
+	Dashboard          data.DashboardRepository
+	Offers             data.OfferRepository
+	Calendar           data.CalendarRepository
+	Registrations      data.RegistrationRepository
+	Sessions           data.SessionRepository
+	Leaves             data.LeaveRepository
+	Transfers          data.TransferRepository
+	Transcripts        data.TranscriptRepository
+	Equivalences       data.EquivalenceRepository
+	ExternalCredits    data.ExternalCreditRepository
+	Reenrollments      data.ReenrollmentRepository
+	EnrollmentRequests data.EnrollmentRequestRepository
+	Reports            data.ReportRepository
+	Admissions         data.AdmissionRepository
+	Imports            data.ImportRepository
+	Documents          data.DocumentRepository
+	Diplomas           data.DiplomaRepository
+
+	// PublicURL é o endereço público do sistema, impresso nos links de verificação dos documentos
+	PublicURL string
+	// Institution é o nome da instituição no cabeçalho dos documentos
+	Institution string
+	// Signer assina os documentos emitidos e confere as assinaturas
+	Signer *signing.Keyring
 }
 
 func NewHandler(
@@ -22,14 +48,55 @@ func NewHandler(
 	disc data.DisciplineRepository,
 	sem data.SemesterRepository,
 	dash data.DashboardRepository,
+	off data.OfferRepository,
+	cal data.CalendarRepository,
+	reg data.RegistrationRepository,
+	ses data.SessionRepository,
+	lv data.LeaveRepository,
+	tr data.TransferRepository,
+	ts data.TranscriptRepository,
+	eq data.EquivalenceRepository,
+	ext data.ExternalCreditRepository,
+	reenr data.ReenrollmentRepository,
+	enreq data.EnrollmentRequestRepository,
+	rep data.ReportRepository,
+	adm data.AdmissionRepository,
+	importRepo data.ImportRepository,
+	docs data.DocumentRepository,
+	dipl data.DiplomaRepository,
 ) *Handler {
 	return &Handler{
-		Students:    s,
-		Teachers:    t,
-		Courses:     c,
-		Departments: d,
-		Disciplines: disc,
-		Semesters:   sem,
-		Dashboard:   dash,
+		Students:           s,
+		Teachers:           t,
+		Courses:            c,
+		Departments:        d,
+		Disciplines:        disc,
+		Semesters:          sem,
+		Dashboard:          dash,
+		Offers:             off,
+		Calendar:           cal,
+		Registrations:      reg,
+		Sessions:           ses,
+		Leaves:             lv,
+		Transfers:          tr,
+		Transcripts:        ts,
+		Equivalences:       eq,
+		ExternalCredits:    ext,
+		Reenrollments:      reenr,
+		EnrollmentRequests: enreq,
+		Reports:            rep,
+		Admissions:         adm,
+		Imports:            importRepo,
+		Documents:          docs,
+		Diplomas:           dipl,
+	}
+}
+
+// requestUser identifica quem fez a alteração pelo cabeçalho X-User.
+// Enquanto não houver autenticação, alterações sem o cabeçalho são atribuídas ao "sistema".
+func requestUser(r *http.Request) string {
+	if user := strings.TrimSpace(r.Header.Get("X-User")); user != "" {
+		return user
 	}
+	return "sistema"
 }
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/importer"
+	"strings"
+)
+
+// ImportHandler importa alunos, professores ou disciplinas de um CSV (corpo da requisição ou campo
+// "file" de um formulário multipart). As linhas válidas são gravadas e as demais voltam no
+// relatório; com ?dry_run=true o arquivo só é conferido.
+func (h *Handler) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	entity := r.PathValue("entity")
+	if _, ok := importer.Columns[entity]; !ok {
+		http.Error(w, "Entidade inválida. Use students, teachers ou disciplines.", http.StatusNotFound)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Arquivo não enviado no campo 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	result, err := importer.Import(h.Imports, entity, body, dryRun, requestUser(r))
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao importar arquivo", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if !dryRun && result.Inserted > 0 {
+		status = http.StatusCreated
+	}
+	if result.Inserted+result.Updated == 0 && len(result.Errors) > 0 {
+		status = http.StatusBadRequest
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CreateLeaveRequestHandler registra a solicitação de trancamento do aluno para um semestre
+func (h *Handler) CreateLeaveRequestHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.LeaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.StudentID = studentID
+
+	if input.SemesterID < 1 || strings.TrimSpace(input.Reason) == "" {
+		http.Error(w, "Informe o semestre e o motivo do trancamento", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if student.Status != models.StudentEnrolled {
+		http.Error(w, "Somente alunos com situação 'enrolled' podem solicitar trancamento", http.StatusUnprocessableEntity)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(input.SemesterID)
+	if err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+	if !semester.IsRegular() {
+		http.Error(w, "O trancamento só pode ser solicitado para semestres regulares", http.StatusUnprocessableEntity)
+		return
+	}
+	// end_date é uma data: o semestre só terminou depois do último dia
+	if semester.EndDate != nil && semester.EndDate.AddDate(0, 0, 1).Before(time.Now()) {
+		http.Error(w, "O semestre informado já terminou", http.StatusUnprocessableEntity)
+		return
+	}
+
+	id, err := h.Leaves.Create(&input)
+	if err != nil {
+		log.Println(err)
+		if err.Error() == "já existe solicitação de trancamento para este semestre" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Erro ao registrar solicitação de trancamento", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Solicitação de trancamento registrada com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetStudentLeaveRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Leaves.GetByStudent(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar solicitações de trancamento", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetLeaveRequestsHandler lista as solicitações para a coordenação (?status=pending)
+func (h *Handler) GetLeaveRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != models.RequestPending && status != models.RequestApproved && status != models.RequestRejected {
+		http.Error(w, "Situação inválida", http.StatusBadRequest)
+		return
+	}
+
+	_, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.Leaves.GetAll(status, courseIDs)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar solicitações de trancamento", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// ReviewLeaveRequestHandler aprova ou rejeita a solicitação conforme a rota
+func (h *Handler) ReviewLeaveRequestHandler(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || id < 1 {
+			http.Error(w, "ID inválido", http.StatusBadRequest)
+			return
+		}
+
+		var input struct {
+			Notes string `json:"notes"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		leave, err := h.Leaves.GetByID(id)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar solicitação de trancamento", http.StatusInternalServerError)
+			return
+		}
+		if leave == nil {
+			http.Error(w, "Solicitação não encontrada", http.StatusNotFound)
+			return
+		}
+		coordinator, ok := h.authorizeStudentCourse(w, r, leave.StudentID)
+		if !ok {
+			return
+		}
+
+		reviewer := coordinator.Name
+		response := map[string]interface{}{}
+
+		if approve {
+			dropped, started, err := h.Leaves.Approve(id, reviewer, input.Notes)
+			if err != nil {
+				h.leaveReviewError(w, id, err)
+				return
+			}
+			response["message"] = "Trancamento aprovado"
+			if !started {
+				response["message"] = "Trancamento aprovado; o aluno passa a trancado no início do semestre"
+			}
+			response["dropped_registrations"] = dropped
+		} else {
+			if err := h.Leaves.Reject(id, reviewer, input.Notes); err != nil {
+				h.leaveReviewError(w, id, err)
+				return
+			}
+			response["message"] = "Trancamento rejeitado"
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (h *Handler) leaveReviewError(w http.ResponseWriter, id int, err error) {
+	switch {
+	case err.Error() == fmt.Sprintf("nenhuma solicitação de trancamento encontrada com o ID %d", id):
+		http.Error(w, "Solicitação não encontrada", http.StatusNotFound)
+	case err.Error() == "solicitação já analisada", strings.HasPrefix(err.Error(), "transição inválida"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Println(err)
+		http.Error(w, "Erro ao analisar solicitação de trancamento", http.StatusInternalServerError)
+	}
+}
+
+// GetAcademicClockHandler mostra quantos semestres do prazo máximo de integralização o aluno já usou
+func (h *Handler) GetAcademicClockHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	course, err := h.Courses.GetByID(student.CourseID)
+	if err != nil || course == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	clock, err := h.Leaves.AcademicClock(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao calcular prazo de integralização", http.StatusInternalServerError)
+		return
+	}
+	clock.DurationSemesters = course.DurationSemesters
+	clock.MaxDurationSemesters = course.MaxDuration()
+	clock.RemainingSemesters = clock.MaxDurationSemesters - clock.CountedSemesters
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clock)
+}
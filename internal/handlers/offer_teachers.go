@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) GetOfferTeachersHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Offers.GetTeachers(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar equipe da oferta", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// SetOfferTeachersHandler define a equipe docente da oferta: um responsável (lead) e assistentes,
+// cada um com a fração da carga horária (hour_share, em %). Professores não habilitados na
+// disciplina são recusados, a menos que force=true seja usado.
+func (h *Handler) SetOfferTeachersHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		Teachers []models.OfferTeacher `json:"teachers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	leads := 0
+	shareCents := 0
+	seen := map[int]bool{}
+	for _, t := range input.Teachers {
+		if t.TeacherID < 1 || seen[t.TeacherID] {
+			http.Error(w, "Professor inválido ou repetido na equipe", http.StatusBadRequest)
+			return
+		}
+		seen[t.TeacherID] = true
+		if t.Role != models.RoleLead && t.Role != models.RoleAssistant {
+			http.Error(w, "Papel inválido. Use 'lead' ou 'assistant'.", http.StatusBadRequest)
+			return
+		}
+		if t.Role == models.RoleLead {
+			leads++
+		}
+		if t.HourShare <= 0 || t.HourShare > 100 {
+			http.Error(w, "A fração da carga horária deve ser maior que 0 e no máximo 100", http.StatusBadRequest)
+			return
+		}
+		// hour_share tem duas casas decimais: somar em centésimos evita o erro de arredondamento
+		shareCents += int(math.Round(t.HourShare * 100))
+	}
+	if shareCents > 10000 {
+		http.Error(w, "A soma das frações da carga horária da equipe não pode passar de 100", http.StatusBadRequest)
+		return
+	}
+	if len(input.Teachers) > 0 && leads != 1 {
+		http.Error(w, "A equipe precisa de exatamente um professor responsável (lead)", http.StatusBadRequest)
+		return
+	}
+
+	force := r.URL.Query().Get("force") == "true"
+	warnings := []string{}
+	for _, t := range input.Teachers {
+		member := *offer
+		member.TeacherID = t.TeacherID
+		w2, refusal, err := h.checkAllocation(&member, false, force)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao verificar professor", http.StatusInternalServerError)
+			return
+		}
+		if refusal != "" {
+			http.Error(w, refusal, http.StatusUnprocessableEntity)
+			return
+		}
+		warnings = append(warnings, w2...)
+	}
+
+	if err := h.Offers.SetTeachers(offerID, input.Teachers); err != nil {
+		if strings.HasPrefix(err.Error(), "professor ") && strings.HasSuffix(err.Error(), " inexistente") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao atualizar equipe da oferta", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Equipe da oferta atualizada com sucesso",
+		"warnings": warnings,
+	})
+}
+
+func (h *Handler) GetOfferSubstitutionsHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Offers.GetSubstitutions(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar substituições", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// CreateOfferSubstitutionHandler registra a substituição de um professor da equipe em um período.
+// Faltas e notas lançadas no período são atribuídas ao substituto, e o relatório de carga docente
+// transfere a ele a carga proporcional.
+func (h *Handler) CreateOfferSubstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.OfferSubstitution
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.OfferID = offerID
+	input.CreatedBy = requestUser(r)
+
+	if input.AbsentTeacherID < 1 || input.SubstituteTeacherID < 1 || input.StartDate.IsZero() || input.EndDate.IsZero() ||
+		strings.TrimSpace(input.Reason) == "" {
+		http.Error(w, "Informe o professor substituído, o substituto, o período e o motivo", http.StatusBadRequest)
+		return
+	}
+	if input.AbsentTeacherID == input.SubstituteTeacherID {
+		http.Error(w, "O substituto deve ser outro professor", http.StatusBadRequest)
+		return
+	}
+	if input.EndDate.Before(input.StartDate) {
+		http.Error(w, "A data de término deve ser posterior à data de início.", http.StatusBadRequest)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(offer.SemesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+	if semester.StartDate != nil && semester.EndDate != nil &&
+		(input.StartDate.Before(*semester.StartDate) || input.EndDate.After(*semester.EndDate)) {
+		http.Error(w, "O período da substituição precisa estar dentro do semestre", http.StatusUnprocessableEntity)
+		return
+	}
+
+	substitute := *offer
+	substitute.TeacherID = input.SubstituteTeacherID
+	warnings, refusal, err := h.checkAllocation(&substitute, false, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar professor", http.StatusInternalServerError)
+		return
+	}
+	if refusal != "" {
+		http.Error(w, refusal, http.StatusUnprocessableEntity)
+		return
+	}
+
+	id, err := h.Offers.CreateSubstitution(&input)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "substituição: ") {
+			http.Error(w, strings.TrimPrefix(err.Error(), "substituição: "), http.StatusUnprocessableEntity)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao registrar substituição", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Substituição registrada com sucesso",
+		"id":       id,
+		"warnings": warnings,
+	})
+}
+
+func (h *Handler) DeleteSubstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Offers.DeleteSubstitution(id)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma substituição encontrada com o ID %d", id) {
+			http.Error(w, "Substituição não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao remover substituição", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
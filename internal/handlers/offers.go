@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/export"
+	"sistema-faculdade/internal/models"
+	"strconv"
+)
+
+func (h *Handler) CreateOfferHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.DisciplineOffer
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := academic.ParseSchedule(input.Schedule); err != nil {
+		http.Error(w, "Horário inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Capacity != nil && *input.Capacity < 1 {
+		http.Error(w, "Número de vagas inválido", http.StatusBadRequest)
+		return
+	}
+
+	warnings, refusal, err := h.checkAllocation(&input, false, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar professor da oferta", http.StatusInternalServerError)
+		return
+	}
+	if refusal != "" {
+		http.Error(w, refusal, http.StatusUnprocessableEntity)
+		return
+	}
+
+	id, err := h.Offers.Create(&input)
+	if err != nil {
+		log.Println(err)
+		if err.Error() == "disciplina já ofertada neste semestre" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Erro ao criar oferta", http.StatusInternalServerError)
+		return
+	}
+	h.refreshPlannedHours(input.SemesterID)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Oferta criada com sucesso",
+		"id":       id,
+		"warnings": warnings,
+	})
+}
+
+func (h *Handler) GetAllOffersHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID := 0
+	if v := r.URL.Query().Get("semester_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "semester_id inválido", http.StatusBadRequest)
+			return
+		}
+		semesterID = id
+	}
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{"id", "semestre", "codigo", "disciplina", "professor", "horario", "sala", "horas_previstas", "vagas"}
+		exportList(w, format, "ofertas", header, func(xw export.Writer) error {
+			return h.Offers.Each(semesterID, func(o models.DisciplineOffer) error {
+				return xw.Row(o.ID, o.SemesterID, o.DisciplineCode, o.DisciplineName, o.TeacherName, o.Schedule, o.Room, o.PlannedHours, o.Capacity)
+			})
+		})
+		return
+	}
+
+	list, err := h.Offers.GetAll(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar ofertas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) GetOfferByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	o, err := h.Offers.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if o == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(o)
+}
+
+func (h *Handler) UpdateOfferHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.DisciplineOffer
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.ID = id
+
+	if _, err := academic.ParseSchedule(input.Schedule); err != nil {
+		http.Error(w, "Horário inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Capacity != nil && *input.Capacity < 1 {
+		http.Error(w, "Número de vagas inválido", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.Offers.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if current == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	// Editar horário ou sala não volta a exigir professor ativo e habilitado
+	assigned := current.TeacherID == input.TeacherID && current.DisciplineID == input.DisciplineID
+	warnings, refusal, err := h.checkAllocation(&input, assigned, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar professor da oferta", http.StatusInternalServerError)
+		return
+	}
+	if refusal != "" {
+		http.Error(w, refusal, http.StatusUnprocessableEntity)
+		return
+	}
+
+	err = h.Offers.Update(&input)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma oferta encontrada com o ID %d", id) {
+			http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "disciplina já ofertada neste semestre" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro interno ao atualizar", http.StatusBadRequest)
+		return
+	}
+	h.refreshPlannedHours(input.SemesterID)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Oferta atualizada com sucesso",
+		"warnings": warnings,
+	})
+}
+
+// CloseOfferHandler fecha o diário da oferta e grava a situação final das matrículas
+func (h *Handler) CloseOfferHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Offers.Close(id, requestUser(r))
+	if err != nil {
+		switch err.Error() {
+		case fmt.Sprintf("nenhuma oferta encontrada com o ID %d", id):
+			http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		case "diário da oferta já fechado":
+			http.Error(w, "Diário da oferta já fechado", http.StatusConflict)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao fechar diário da oferta", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Diário fechado com sucesso"})
+}
+
+func (h *Handler) DeleteOfferHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Offers.Delete(id)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma oferta encontrada com o ID %d", id) {
+			http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro interno ao deletar oferta", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"time"
+)
+
+// GetRecommendationsHandler sugere as ofertas do semestre (?semester_id=) que o aluno deveria cursar,
+// a partir da matriz do curso, dos pré-requisitos e das disciplinas já cumpridas
+func (h *Handler) GetRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	semesterID, err := strconv.Atoi(r.URL.Query().Get("semester_id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "Informe o semestre (semester_id)", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(semesterID)
+	if err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+
+	course, err := h.Courses.GetByID(student.CourseID)
+	if err != nil || course == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	curriculum, err := h.Courses.GetCurriculum(course.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matriz curricular", http.StatusInternalServerError)
+		return
+	}
+
+	offers, err := h.Offers.GetAll(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar ofertas", http.StatusInternalServerError)
+		return
+	}
+
+	prereqs, err := h.Disciplines.PrerequisiteMap()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pré-requisitos", http.StatusInternalServerError)
+		return
+	}
+
+	fulfilled, err := h.Transcripts.FulfilledDisciplines(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico do aluno", http.StatusInternalServerError)
+		return
+	}
+	inProgress, err := h.Transcripts.InProgressDisciplines(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	registered, err := h.Transcripts.SemesterRegistrations(studentID, semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matrículas do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	clock, err := h.Leaves.AcademicClock(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao calcular semestre do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	// Para um semestre que ainda não começou, o aluno estará no semestre seguinte ao já cursado
+	level := clock.CountedSemesters
+	if semester.StartDate == nil || semester.StartDate.After(time.Now()) {
+		level++
+	}
+	if level < 1 {
+		level = 1
+	}
+
+	items := map[int]models.CurriculumItem{}
+	for _, c := range curriculum {
+		items[c.DisciplineID] = c
+	}
+
+	var candidates []academic.Candidate
+	offerByID := map[int]models.DisciplineOffer{}
+	for _, o := range offers {
+		item, ok := items[o.DisciplineID]
+		if !ok || fulfilled[o.DisciplineID] || inProgress[o.DisciplineID] {
+			continue
+		}
+		c := academic.Candidate{
+			OfferID:       o.ID,
+			DisciplineID:  o.DisciplineID,
+			Credits:       item.Credits,
+			Schedule:      o.Schedule,
+			Mandatory:     item.Mandatory,
+			Prerequisites: prereqs[o.DisciplineID],
+		}
+		if item.SuggestedSemester != nil {
+			c.SuggestedSemester = *item.SuggestedSemester
+		}
+		candidates = append(candidates, c)
+		offerByID[o.ID] = o
+	}
+
+	maxCredits := 0
+	if course.MaxCreditsPerSemester != nil {
+		maxCredits = *course.MaxCreditsPerSemester
+	}
+
+	result := models.RecommendationResult{
+		StudentID:   studentID,
+		SemesterID:  semesterID,
+		Level:       level,
+		MaxCredits:  course.MaxCreditsPerSemester,
+		Recommended: []models.Recommendation{},
+		Skipped:     []models.Recommendation{},
+	}
+	for _, c := range registered {
+		result.RegisteredCredits += c.Credits
+	}
+	result.TotalCredits = result.RegisteredCredits
+
+	for _, p := range academic.Recommend(candidates, registered, fulfilled, level, maxCredits) {
+		o := offerByID[p.OfferID]
+		rec := models.Recommendation{
+			OfferID:              p.OfferID,
+			DisciplineID:         p.DisciplineID,
+			DisciplineCode:       o.DisciplineCode,
+			DisciplineName:       o.DisciplineName,
+			Credits:              p.Credits,
+			Schedule:             p.Schedule,
+			SuggestedSemester:    items[p.DisciplineID].SuggestedSemester,
+			Mandatory:            p.Mandatory,
+			Overdue:              p.Overdue,
+			SkipReason:           p.SkipReason,
+			MissingPrerequisites: p.MissingPrerequisites,
+		}
+		if p.SkipReason == "" {
+			result.Recommended = append(result.Recommended, rec)
+			result.TotalCredits += p.Credits
+		} else {
+			result.Skipped = append(result.Skipped, rec)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
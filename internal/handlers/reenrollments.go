@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenReenrollmentHandler abre a campanha de rematrícula do semestre
+func (h *Handler) OpenReenrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.ReenrollmentCampaign
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.SemesterID = semesterID
+	input.CreatedBy = requestUser(r)
+
+	if input.OpensAt.IsZero() || input.ClosesAt.IsZero() || input.ClosesAt.Before(input.OpensAt) {
+		http.Error(w, "Informe o período da rematrícula (opens_at e closes_at)", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Semesters.GetByID(semesterID); err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+
+	id, err := h.Reenrollments.Open(&input)
+	if err != nil {
+		if err.Error() == "já existe campanha de rematrícula para este semestre" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao abrir campanha de rematrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Campanha de rematrícula aberta com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetReenrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	semesterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	campaign, err := h.Reenrollments.GetBySemester(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar campanha de rematrícula", http.StatusInternalServerError)
+		return
+	}
+	if campaign == nil {
+		http.Error(w, "Nenhuma campanha de rematrícula para este semestre", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// loadCampaign busca a campanha da rota e responde 404 se ela não existir
+func (h *Handler) loadCampaign(w http.ResponseWriter, r *http.Request) *models.ReenrollmentCampaign {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil
+	}
+
+	campaign, err := h.Reenrollments.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar campanha de rematrícula", http.StatusInternalServerError)
+		return nil
+	}
+	if campaign == nil {
+		http.Error(w, "Campanha de rematrícula não encontrada", http.StatusNotFound)
+		return nil
+	}
+	return campaign
+}
+
+// ConfirmReenrollmentHandler confirma a rematrícula do aluno e o matricula nas ofertas escolhidas
+func (h *Handler) ConfirmReenrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	campaign := h.loadCampaign(w, r)
+	if campaign == nil {
+		return
+	}
+
+	var input models.Reenrollment
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.CampaignID = campaign.ID
+
+	if !campaign.IsOpen(time.Now()) {
+		http.Error(w, "A campanha de rematrícula não está aberta", http.StatusUnprocessableEntity)
+		return
+	}
+
+	student, err := h.Students.GetByID(input.StudentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if student.Status != models.StudentEnrolled {
+		http.Error(w, "Somente alunos com situação 'enrolled' podem confirmar a rematrícula", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := h.Reenrollments.Confirm(campaign, &input); err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "campanha de rematrícula já encerrada", strings.HasPrefix(msg, "já existe pedido de matrícula"),
+			strings.HasSuffix(msg, "sem vagas disponíveis"):
+			http.Error(w, msg, http.StatusConflict)
+		case strings.HasSuffix(msg, "não pertence ao semestre da rematrícula"):
+			http.Error(w, msg, http.StatusUnprocessableEntity)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao confirmar rematrícula", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(input)
+}
+
+// GetPendingReenrollmentsHandler lista os alunos que ainda não confirmaram a rematrícula
+func (h *Handler) GetPendingReenrollmentsHandler(w http.ResponseWriter, r *http.Request) {
+	campaign := h.loadCampaign(w, r)
+	if campaign == nil {
+		return
+	}
+
+	list, err := h.Reenrollments.Pending(campaign.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar alunos sem rematrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// CloseReenrollmentHandler encerra a campanha e devolve os alunos que não confirmaram.
+// Com "mark_dropped_out": true esses alunos passam a evadidos.
+func (h *Handler) CloseReenrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	campaign := h.loadCampaign(w, r)
+	if campaign == nil {
+		return
+	}
+
+	var input struct {
+		MarkDroppedOut bool `json:"mark_dropped_out"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.Reenrollments.Close(campaign.ID, requestUser(r), input.MarkDroppedOut)
+	if err != nil {
+		if err.Error() == "campanha de rematrícula já encerrada" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao encerrar campanha de rematrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":       "Campanha de rematrícula encerrada",
+		"not_confirmed": list,
+	})
+}
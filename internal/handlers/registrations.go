@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/export"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) CreateRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.Registration
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(input.StudentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if student.Status != models.StudentEnrolled {
+		http.Error(w, "Somente alunos com situação 'enrolled' podem ser matriculados", http.StatusUnprocessableEntity)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(input.OfferID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	// Cursos com aprovação da coordenação recebem um pedido de matrícula
+	course, err := h.Courses.GetByID(student.CourseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso do aluno", http.StatusInternalServerError)
+		return
+	}
+	if course != nil && course.RequiresEnrollmentApproval {
+		ids, err := h.EnrollmentRequests.Create(input.StudentID, []int{input.OfferID})
+		if err != nil {
+			log.Println(err)
+			if strings.HasPrefix(err.Error(), "já existe pedido") {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			http.Error(w, "Erro ao criar pedido de matrícula", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":    "Pedido de matrícula enviado para aprovação da coordenação",
+			"request_id": ids[0],
+		})
+		return
+	}
+
+	id, err := h.Registrations.Create(&input)
+	if err != nil {
+		log.Println(err)
+		if err.Error() == "aluno já matriculado nesta oferta" || strings.HasSuffix(err.Error(), "sem vagas disponíveis") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Erro ao criar matrícula", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Matrícula realizada com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetOfferRegistrationsHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{"id", "aluno_id", "aluno", "disciplina", "semestre", "nota_final", "frequencia_%", "faltas", "situacao", "aprovado"}
+		exportList(w, format, fmt.Sprintf("matriculas-oferta-%d", offerID), header, func(xw export.Writer) error {
+			return h.Registrations.EachByOffer(offerID, func(reg models.Registration) error {
+				return xw.Row(
+					reg.ID, reg.StudentID, reg.StudentName, reg.DisciplineName, reg.SemesterID, reg.FinalGrade,
+					reg.Frequency, reg.Absences, reg.Status, reg.Approved,
+				)
+			})
+		})
+		return
+	}
+
+	list, err := h.Registrations.GetByOffer(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matrículas", http.StatusInternalServerError)
+		return
+	}
+
+	// Com ?signed=true a lista sai como pauta de notas assinada
+	if r.URL.Query().Get("signed") == "true" {
+		h.writeSigned(w, models.DocumentGradeSheet, models.GradeSheet{OfferID: offerID, Registrations: list})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) DeleteRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Registrations.Delete(id)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma matrícula encontrada com o ID %d", id) {
+			http.Error(w, "Matrícula não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro interno ao deletar matrícula", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateAttendanceHandler lança as faltas de uma aula.
+// A data precisa estar dentro do semestre da oferta, não pode ser feriado ou recesso
+// e, se as aulas da oferta já foram geradas, precisa ser dia de aula (session_id escolhe a
+// aula quando há mais de uma na data).
+func (h *Handler) CreateAttendanceHandler(w http.ResponseWriter, r *http.Request) {
+	regID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || regID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.AttendanceRecord
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.RegistrationID = regID
+
+	if input.ClassDate.IsZero() || input.HoursAbsent < 0 {
+		http.Error(w, "Informe a data da aula e as horas de falta", http.StatusBadRequest)
+		return
+	}
+
+	reg, err := h.Registrations.GetByID(regID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matrícula", http.StatusInternalServerError)
+		return
+	}
+	if reg == nil {
+		http.Error(w, "Matrícula não encontrada", http.StatusNotFound)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(reg.OfferID)
+	if err != nil || offer == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer.ClosedAt != nil {
+		http.Error(w, "Diário da oferta já fechado", http.StatusConflict)
+		return
+	}
+
+	msg, err := h.checkTeachingDay(reg.SemesterID, input.ClassDate)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao consultar calendário acadêmico", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Com as aulas geradas, a falta precisa corresponder a uma aula da oferta. Em dias com mais de
+	// uma aula, session_id indica qual delas.
+	total, err := h.Sessions.CountByOffer(reg.OfferID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aulas", http.StatusInternalServerError)
+		return
+	}
+	if total == 0 {
+		input.SessionID = nil
+	} else {
+		sessions, err := h.Sessions.GetByOfferDate(reg.OfferID, input.ClassDate)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar aulas", http.StatusInternalServerError)
+			return
+		}
+		day := input.ClassDate.Format("02/01/2006")
+		if len(sessions) == 0 {
+			http.Error(w, fmt.Sprintf("Não há aula desta oferta em %s", day), http.StatusUnprocessableEntity)
+			return
+		}
+
+		if input.SessionID == nil {
+			if len(sessions) > 1 {
+				http.Error(w, fmt.Sprintf("Há %d aulas desta oferta em %s; informe session_id", len(sessions), day), http.StatusUnprocessableEntity)
+				return
+			}
+			input.SessionID = &sessions[0].ID
+		} else {
+			found := false
+			for _, s := range sessions {
+				found = found || s.ID == *input.SessionID
+			}
+			if !found {
+				http.Error(w, fmt.Sprintf("A aula %d não é desta oferta em %s", *input.SessionID, day), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+	}
+
+	id, err := h.Registrations.SaveAttendance(&input)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao lançar faltas", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Faltas lançadas com sucesso",
+		"id":      id,
+	})
+}
+
+func (h *Handler) GetAttendanceHandler(w http.ResponseWriter, r *http.Request) {
+	regID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || regID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Registrations.GetAttendance(regID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar faltas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"strconv"
+)
+
+// DefaultSectionSize é o número de alunos por turma usado quando o relatório não informa section_size
+const DefaultSectionSize = 40
+
+// DemandForecastHandler estima quantos alunos vão precisar de cada disciplina no próximo semestre.
+// Parâmetros: course_id (vazio = instituição inteira), section_size e format=csv para exportar.
+func (h *Handler) DemandForecastHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	courseID := 0
+	if v := q.Get("course_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "course_id inválido", http.StatusBadRequest)
+			return
+		}
+		courseID = id
+	}
+
+	sectionSize := DefaultSectionSize
+	if v := q.Get("section_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "section_size inválido", http.StatusBadRequest)
+			return
+		}
+		sectionSize = n
+	}
+
+	students, err := h.Reports.DemandStudents(courseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar previsão de demanda", http.StatusInternalServerError)
+		return
+	}
+	curriculum, err := h.Reports.DemandCurriculum(courseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar previsão de demanda", http.StatusInternalServerError)
+		return
+	}
+	stats, err := h.Reports.DisciplineStats()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar previsão de demanda", http.StatusInternalServerError)
+		return
+	}
+	prereqs, err := h.Disciplines.PrerequisiteMap()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar pré-requisitos", http.StatusInternalServerError)
+		return
+	}
+	disciplines, err := h.Disciplines.GetAll()
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar disciplinas", http.StatusInternalServerError)
+		return
+	}
+
+	byID := map[int]models.Discipline{}
+	for _, d := range disciplines {
+		byID[d.ID] = d
+	}
+
+	report := []models.DisciplineDemand{}
+	for _, d := range academic.ForecastDemand(students, curriculum, prereqs, stats, sectionSize) {
+		report = append(report, models.DisciplineDemand{
+			DisciplineID:          d.DisciplineID,
+			DisciplineCode:        byID[d.DisciplineID].Code,
+			DisciplineName:        byID[d.DisciplineID].Name,
+			Eligible:              d.Eligible,
+			AwaitingPrerequisites: d.AwaitingPrerequisites,
+			OnTrack:               d.OnTrack,
+			Overdue:               d.Overdue,
+			ExpectedRetakes:       d.ExpectedRetakes,
+			FailureRate:           math.Round(d.FailureRate*1000) / 10,
+			Estimated:             d.Estimated,
+			Sections:              d.Sections,
+		})
+	}
+
+	if q.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="previsao-demanda.csv"`)
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{
+			"codigo", "disciplina", "elegiveis", "aguardando_pre_requisito", "no_periodo", "atrasados",
+			"reprovacoes_previstas", "taxa_reprovacao_%", "demanda_estimada", "turmas",
+		})
+		for _, d := range report {
+			cw.Write([]string{
+				d.DisciplineCode, d.DisciplineName, strconv.Itoa(d.Eligible), strconv.Itoa(d.AwaitingPrerequisites),
+				strconv.Itoa(d.OnTrack), strconv.Itoa(d.Overdue), strconv.Itoa(d.ExpectedRetakes),
+				fmt.Sprintf("%.1f", d.FailureRate), strconv.Itoa(d.Estimated), strconv.Itoa(d.Sections),
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Println("Erro ao exportar previsão de demanda:", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// DefaultMinAllocation é a fração mínima das horas do contrato em sala de aula;
+// abaixo dela o professor aparece como subalocado
+const DefaultMinAllocation = 0.5
+
+// TeacherWorkloadHandler mostra, por professor e por departamento, as ofertas do semestre,
+// as horas semanais de aula e a carga total comparadas às horas do contrato.
+// Parâmetros: semester_id (obrigatório), department_id, min_ratio e format=csv para exportar.
+func (h *Handler) TeacherWorkloadHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	semesterID, err := strconv.Atoi(q.Get("semester_id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "Informe o semester_id", http.StatusBadRequest)
+		return
+	}
+
+	departmentID := 0
+	if v := q.Get("department_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "department_id inválido", http.StatusBadRequest)
+			return
+		}
+		departmentID = id
+	}
+	departmentID, ok := h.departmentScope(w, r, departmentID)
+	if !ok {
+		return
+	}
+
+	minRatio := DefaultMinAllocation
+	if v := q.Get("min_ratio"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			http.Error(w, "min_ratio deve estar entre 0 e 1", http.StatusBadRequest)
+			return
+		}
+		minRatio = f
+	}
+
+	semester, err := h.Semesters.GetByID(semesterID)
+	if err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+	weeks := 0.0
+	if semester.StartDate != nil && semester.EndDate != nil {
+		weeks = semester.EndDate.Sub(*semester.StartDate).Hours() / 24 / 7
+	}
+
+	teachers, err := h.Reports.WorkloadTeachers(semesterID, departmentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar relatório de carga docente", http.StatusInternalServerError)
+		return
+	}
+	offers, err := h.Reports.WorkloadOffers(semesterID, departmentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar relatório de carga docente", http.StatusInternalServerError)
+		return
+	}
+
+	report := models.WorkloadReport{
+		SemesterID:       semester.ID,
+		Semester:         semester.String(),
+		Teachers:         teachers,
+		Departments:      []models.DepartmentWorkload{},
+		UnassignedOffers: []models.WorkloadOffer{},
+	}
+	if report.Teachers == nil {
+		report.Teachers = []models.TeacherWorkload{}
+	}
+
+	byTeacher := map[int]int{}
+	for i, t := range report.Teachers {
+		byTeacher[t.TeacherID] = i
+	}
+	for _, o := range offers {
+		o.WeeklyHours, o.Estimated = academic.WeeklyHours(o.Schedule, o.SemesterHours, weeks)
+		o.WeeklyHours = math.Round(o.WeeklyHours*o.HourShare/10) / 10
+		o.SemesterHours = int(math.Round(float64(o.SemesterHours) * o.HourShare / 100))
+		o.HourShare = math.Round(o.HourShare*10) / 10
+		i, ok := 0, false
+		if o.TeacherID != nil {
+			i, ok = byTeacher[*o.TeacherID]
+		}
+		if !ok {
+			report.UnassignedOffers = append(report.UnassignedOffers, o)
+			continue
+		}
+		t := &report.Teachers[i]
+		t.Offers = append(t.Offers, o)
+		t.OfferCount++
+		t.WeeklyHours += o.WeeklyHours
+		t.SemesterHours += o.SemesterHours
+	}
+
+	byDepartment := map[string]int{}
+	for i := range report.Teachers {
+		t := &report.Teachers[i]
+		t.WeeklyHours = math.Round(t.WeeklyHours*10) / 10
+		contract := 0
+		if t.ContractHours != nil {
+			contract = *t.ContractHours
+			u := math.Round(t.WeeklyHours/float64(contract)*1000) / 10
+			t.Utilization = &u
+		}
+		fullTime := t.ContractRegime != nil && *t.ContractRegime == models.RegimeFullTime
+		t.Status = academic.ClassifyWorkload(t.WeeklyHours, contract, fullTime, minRatio)
+
+		key := t.DepartmentName
+		j, ok := byDepartment[key]
+		if !ok {
+			j = len(report.Departments)
+			byDepartment[key] = j
+			report.Departments = append(report.Departments, models.DepartmentWorkload{
+				DepartmentID:   t.DepartmentID,
+				DepartmentName: t.DepartmentName,
+			})
+		}
+		d := &report.Departments[j]
+		d.Teachers++
+		d.OfferCount += t.OfferCount
+		d.WeeklyHours += t.WeeklyHours
+		d.SemesterHours += t.SemesterHours
+		d.ContractHours += contract
+		switch t.Status {
+		case academic.WorkloadOverloaded:
+			d.Overloaded++
+		case academic.WorkloadUnderallocated:
+			d.Underallocated++
+		}
+		d.WeeklyHours = math.Round(d.WeeklyHours*10) / 10
+	}
+
+	if q.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="carga-docente-%s.csv"`, semester.String()))
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{
+			"professor", "departamento", "titulacao", "regime", "ofertas", "horas_semanais", "horas_semestre", "horas_contrato", "ocupacao_%", "situacao",
+		})
+		for _, t := range report.Teachers {
+			contract, utilization, title, regime := "", "", "", ""
+			if t.AcademicTitle != nil {
+				title = *t.AcademicTitle
+			}
+			if t.ContractRegime != nil {
+				regime = *t.ContractRegime
+			}
+			if t.ContractHours != nil {
+				contract = strconv.Itoa(*t.ContractHours)
+				utilization = fmt.Sprintf("%.1f", *t.Utilization)
+			}
+			cw.Write([]string{
+				t.TeacherName, t.DepartmentName, title, regime, strconv.Itoa(t.OfferCount), fmt.Sprintf("%.1f", t.WeeklyHours),
+				strconv.Itoa(t.SemesterHours), contract, utilization, t.Status,
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Println("Erro ao exportar carga docente:", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// FacultyProfileHandler mostra a titulação e o regime de trabalho do corpo docente ativo (?department_id=)
+func (h *Handler) FacultyProfileHandler(w http.ResponseWriter, r *http.Request) {
+	departmentID := 0
+	if v := r.URL.Query().Get("department_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "department_id inválido", http.StatusBadRequest)
+			return
+		}
+		departmentID = id
+	}
+	departmentID, ok := h.departmentScope(w, r, departmentID)
+	if !ok {
+		return
+	}
+
+	profile, err := h.Reports.FacultyProfile(departmentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar perfil do corpo docente", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/ical"
+	"sistema-faculdade/internal/models"
+	"strconv"
+)
+
+func (h *Handler) StudentScheduleICSHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	h.writeScheduleICS(w, r, "Aulas - "+student.Name, func(semesterID int) ([]models.DisciplineOffer, error) {
+		return h.Offers.GetByStudent(id, semesterID)
+	})
+}
+
+func (h *Handler) TeacherScheduleICSHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	teacher, err := h.Teachers.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
+		return
+	}
+	if teacher == nil {
+		http.Error(w, "Professor não encontrado", http.StatusNotFound)
+		return
+	}
+
+	h.writeScheduleICS(w, r, "Aulas - "+teacher.Name, func(semesterID int) ([]models.DisciplineOffer, error) {
+		return h.Offers.GetByTeacher(id, semesterID)
+	})
+}
+
+func (h *Handler) RoomScheduleICSHandler(w http.ResponseWriter, r *http.Request) {
+	room := r.PathValue("room")
+	if room == "" {
+		http.Error(w, "Sala inválida", http.StatusBadRequest)
+		return
+	}
+
+	h.writeScheduleICS(w, r, "Ocupação - "+room, func(semesterID int) ([]models.DisciplineOffer, error) {
+		return h.Offers.GetByRoom(room, semesterID)
+	})
+}
+
+// writeScheduleICS monta o calendário semanal das ofertas do semestre informado em ?semester_id,
+// expandindo cada encontro entre o início e o fim do semestre e pulando feriados e recessos.
+func (h *Handler) writeScheduleICS(w http.ResponseWriter, r *http.Request, name string, load func(semesterID int) ([]models.DisciplineOffer, error)) {
+	semesterID, err := strconv.Atoi(r.URL.Query().Get("semester_id"))
+	if err != nil || semesterID < 1 {
+		http.Error(w, "semester_id inválido", http.StatusBadRequest)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(semesterID)
+	if err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+	if semester.StartDate == nil || semester.EndDate == nil {
+		http.Error(w, "Semestre sem datas de início e fim definidas", http.StatusUnprocessableEntity)
+		return
+	}
+
+	offers, err := load(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar ofertas", http.StatusInternalServerError)
+		return
+	}
+
+	skip, err := h.nonTeachingDays(semesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar calendário acadêmico", http.StatusInternalServerError)
+		return
+	}
+
+	cal := ical.Calendar{Name: fmt.Sprintf("%s (%s)", name, semester.String())}
+	for _, o := range offers {
+		slots, err := academic.ParseSchedule(o.Schedule)
+		if err != nil {
+			log.Printf("oferta %d com horário inválido %q: %v", o.ID, o.Schedule, err)
+			continue
+		}
+
+		for _, occ := range academic.Occurrences(slots, *semester.StartDate, *semester.EndDate, skip) {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:         fmt.Sprintf("oferta-%d-%s@unisystem", o.ID, occ.Start.Format("20060102T1504")),
+				Summary:     fmt.Sprintf("%s - %s", o.DisciplineCode, o.DisciplineName),
+				Location:    o.Room,
+				Description: "Professor: " + o.TeacherName,
+				Start:       occ.Start,
+				End:         occ.End,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="horario-%s.ics"`, semester.String()))
+	if err := cal.Write(w); err != nil {
+		log.Println("Erro ao gerar iCalendar:", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"sistema-faculdade/internal/models"
 	"strconv"
+	"time"
 )
 
 func (h *Handler) CreateSemesterHandler(w http.ResponseWriter, r *http.Request) {
@@ -18,15 +19,26 @@ func (h *Handler) CreateSemesterHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if input.Period != 1 && input.Period != 2 {
-		http.Error(w, "Período inválido. Deve ser 1 ou 2.", http.StatusBadRequest)
+	// Sem "kind", o período é um semestre regular (1 ou 2)
+	if err := input.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if input.StartDate != nil && input.EndDate != nil && input.EndDate.Before(*input.StartDate) {
+		http.Error(w, "A data de término deve ser posterior à data de início.", http.StatusBadRequest)
 		return
 	}
 
 	id, err := h.Semesters.Create(&input)
 	if err != nil {
+		if err.Error() == fmt.Sprintf("semestre %s já existe", input.String()) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		log.Println(err)
 		http.Error(w, "Erro interno ao criar semestre", http.StatusInternalServerError)
+		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
@@ -65,3 +77,121 @@ func (h *Handler) DeleteSemesterHandler(w http.ResponseWriter, r *http.Request)
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RolloverSemesterHandler cria o período seguinte (do mesmo tipo) ao período informado e copia as ofertas
+// do mesmo período do ano anterior (ou de source_semester_id), para a coordenação só ajustar as diferenças
+func (h *Handler) RolloverSemesterHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		StartDate        *time.Time `json:"start_date"`
+		EndDate          *time.Time `json:"end_date"`
+		SourceSemesterID int        `json:"source_semester_id"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if input.StartDate != nil && input.EndDate != nil && input.EndDate.Before(*input.StartDate) {
+		http.Error(w, "A data de término deve ser posterior à data de início.", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.Semesters.GetByID(id)
+	if err != nil {
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+
+	next := models.AcademicSemester{Kind: current.Kind, StartDate: input.StartDate, EndDate: input.EndDate}
+	next.Year, next.Period = current.Next()
+
+	sourceID := input.SourceSemesterID
+	if sourceID == 0 {
+		source, err := h.Semesters.FindByPeriod(next.Year-1, next.Kind, next.Period)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Erro ao buscar semestre de origem", http.StatusInternalServerError)
+			return
+		}
+		if source == nil {
+			previous := models.AcademicSemester{Year: next.Year - 1, Kind: next.Kind, Period: next.Period}
+			http.Error(w, fmt.Sprintf("Não há semestre %s para copiar as ofertas", previous.String()), http.StatusUnprocessableEntity)
+			return
+		}
+		sourceID = source.ID
+	} else if _, err := h.Semesters.GetByID(sourceID); err != nil {
+		http.Error(w, "Semestre de origem não encontrado", http.StatusNotFound)
+		return
+	}
+
+	refused, warnings, err := h.checkRolloverTeams(sourceID, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao verificar professores das ofertas", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := h.Semesters.Rollover(&next, sourceID, refused)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("semestre %s já existe", next.String()) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao criar semestre", http.StatusInternalServerError)
+		return
+	}
+	h.refreshPlannedHours(result.SemesterID)
+	for i := range result.Copied {
+		result.Copied[i].Warnings = warnings[result.Copied[i].SourceOfferID]
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// checkRolloverTeams passa cada professor das ofertas de origem por checkAllocation. Devolve os
+// professores recusados por oferta (com o motivo) e os avisos de cada oferta.
+func (h *Handler) checkRolloverTeams(sourceID int, force bool) (map[int]map[int]string, map[int][]string, error) {
+	offers, err := h.Offers.GetAll(sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refused := map[int]map[int]string{}
+	warnings := map[int][]string{}
+	for _, o := range offers {
+		team, err := h.Offers.GetTeachers(o.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(team) == 0 && o.TeacherID != 0 {
+			team = []models.OfferTeacher{{TeacherID: o.TeacherID}}
+		}
+
+		for _, t := range team {
+			member := o
+			member.TeacherID = t.TeacherID
+			w, refusal, err := h.checkAllocation(&member, false, force)
+			if err != nil {
+				return nil, nil, err
+			}
+			if refusal != "" {
+				if refused[o.ID] == nil {
+					refused[o.ID] = map[int]string{}
+				}
+				refused[o.ID][t.TeacherID] = refusal
+				continue
+			}
+			warnings[o.ID] = append(warnings[o.ID], w...)
+		}
+	}
+	return refused, warnings, nil
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var diaryTemplate = template.Must(template.New("diary.html").Funcs(template.FuncMap{
+	"inc":   func(i int) int { return i + 1 },
+	"deref": func(f *float64) float64 { return *f },
+}).ParseFS(templateFS, "templates/diary.html"))
+
+// GenerateSessionsHandler gera as aulas da oferta a partir do horário e do calendário acadêmico
+func (h *Handler) GenerateSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(offer.SemesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+	if semester.StartDate == nil || semester.EndDate == nil {
+		http.Error(w, "Semestre sem datas de início e fim definidas", http.StatusUnprocessableEntity)
+		return
+	}
+
+	slots, err := academic.ParseSchedule(offer.Schedule)
+	if err != nil {
+		http.Error(w, "Horário da oferta inválido: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	skip, err := h.nonTeachingDays(offer.SemesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar calendário acadêmico", http.StatusInternalServerError)
+		return
+	}
+
+	var sessions []models.ClassSession
+	for _, occ := range academic.Occurrences(slots, *semester.StartDate, *semester.EndDate, skip) {
+		sessions = append(sessions, models.ClassSession{
+			OfferID:   offerID,
+			Date:      occ.Date,
+			StartTime: occ.Start.Format("15:04"),
+			EndTime:   occ.End.Format("15:04"),
+		})
+	}
+
+	created, removed, err := h.Sessions.Sync(offerID, sessions)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar aulas", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Aulas geradas com sucesso",
+		"total":   len(sessions),
+		"created": created,
+		"removed": removed,
+	})
+}
+
+func (h *Handler) GetOfferSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Sessions.GetByOffer(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aulas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// UpdateSessionHandler registra o conteúdo ministrado e as observações da aula no diário
+func (h *Handler) UpdateSessionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input models.ClassSession
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.ID = id
+
+	err = h.Sessions.UpdateDiary(&input)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhuma aula encontrada com o ID %d", id) {
+			http.Error(w, "Aula não encontrada", http.StatusNotFound)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro interno ao atualizar", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Diário atualizado com sucesso"})
+}
+
+// SaveSessionAttendanceHandler registra a chamada da aula
+func (h *Handler) SaveSessionAttendanceHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		Records []models.SessionAttendance `json:"records"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.Sessions.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aula", http.StatusInternalServerError)
+		return
+	}
+	if session == nil {
+		http.Error(w, "Aula não encontrada", http.StatusNotFound)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(session.OfferID)
+	if err != nil || offer == nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer.ClosedAt != nil {
+		http.Error(w, "Diário da oferta já fechado", http.StatusConflict)
+		return
+	}
+
+	// O calendário pode ter mudado depois da geração das aulas
+	msg, err := h.checkTeachingDay(offer.SemesterID, session.Date)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao consultar calendário acadêmico", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusUnprocessableEntity)
+		return
+	}
+
+	for _, rec := range input.Records {
+		if rec.HoursAbsent < 0 {
+			http.Error(w, "Horas de falta não podem ser negativas", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.Sessions.SaveAttendance(session, input.Records); err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Chamada registrada com sucesso"})
+}
+
+// ExportDiaryHandler gera o diário de classe da oferta como documento HTML pronto para impressão
+func (h *Handler) ExportDiaryHandler(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || offerID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	offer, err := h.Offers.GetByID(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar oferta", http.StatusInternalServerError)
+		return
+	}
+	if offer == nil {
+		http.Error(w, "Oferta não encontrada", http.StatusNotFound)
+		return
+	}
+
+	semester, err := h.Semesters.GetByID(offer.SemesterID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Semestre não encontrado", http.StatusNotFound)
+		return
+	}
+
+	sessions, err := h.Sessions.GetByOffer(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar aulas", http.StatusInternalServerError)
+		return
+	}
+
+	registrations, err := h.Registrations.GetByOffer(offerID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar matrículas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="diario-%s-%s.html"`, offer.DisciplineCode, semester.String()))
+
+	err = diaryTemplate.Execute(w, map[string]interface{}{
+		"Offer":         offer,
+		"Semester":      semester.String(),
+		"Sessions":      sessions,
+		"Registrations": registrations,
+		"IssuedAt":      time.Now(),
+	})
+	if err != nil {
+		log.Println("Erro ao gerar diário de classe:", err)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"sistema-faculdade/internal/signing"
+	"strings"
+	"time"
+)
+
+// Tamanho máximo do arquivo enviado para conferência da assinatura
+const maxVerifyUpload = 20 << 20
+
+// writeSigned responde com o documento JSON assinado pela chave da instituição
+func (h *Handler) writeSigned(w http.ResponseWriter, kind string, data any) {
+	if !h.Signer.CanSign() {
+		http.Error(w, "Assinatura digital não configurada", http.StatusServiceUnavailable)
+		return
+	}
+	env, err := h.Signer.SignJSON(kind, time.Now().Truncate(time.Second), data)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao assinar documento", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Signature-Key-ID", env.Signature.KeyID)
+	json.NewEncoder(w).Encode(env)
+}
+
+// GetSigningKeysHandler publica as chaves públicas ativas, para a verificação fora do sistema
+func (h *Handler) GetSigningKeysHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Signer.PublicKeys())
+}
+
+// VerifySignatureHandler confere a assinatura de um arquivo. Um documento JSON assinado é enviado
+// como corpo (application/json) e um diploma XML como corpo application/xml. Um PDF vai no corpo
+// com ?key_id= e ?signature=, ou no campo "file" de um formulário multipart com o arquivo .sig no
+// campo "signature".
+func (h *Handler) VerifySignatureHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxVerifyUpload)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var result models.SignatureVerification
+	switch mediaType {
+	case "application/json":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Arquivo grande demais ou ilegível", http.StatusRequestEntityTooLarge)
+			return
+		}
+		env, doc, err := h.Signer.VerifyJSON(body)
+		if env == nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result.KeyID = env.Signature.KeyID
+		result.Kind = doc.Kind
+		result.IssuedAt = &doc.IssuedAt
+		result.Valid = err == nil
+		if err != nil {
+			result.Reason = err.Error()
+		}
+
+	case "application/xml", "text/xml":
+		var ok bool
+		if result, ok = h.checkDiplomaSignature(w, r.Body); !ok {
+			return
+		}
+
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Arquivo não enviado no campo 'file'", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, "Erro ao ler o arquivo", http.StatusBadRequest)
+			return
+		}
+
+		var sig signing.Signature
+		sigFile, _, err := r.FormFile("signature")
+		if err == nil {
+			defer sigFile.Close()
+			err = json.NewDecoder(sigFile).Decode(&sig)
+		} else {
+			err = json.Unmarshal([]byte(r.FormValue("signature")), &sig)
+		}
+		if err != nil {
+			http.Error(w, "Assinatura (.sig) não enviada ou inválida no campo 'signature'", http.StatusBadRequest)
+			return
+		}
+		result = h.checkSignature(content, sig)
+
+	default:
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Arquivo grande demais ou ilegível", http.StatusRequestEntityTooLarge)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("key_id") == "" || q.Get("signature") == "" {
+			http.Error(w, "Informe key_id e signature", http.StatusBadRequest)
+			return
+		}
+		// Na query string o "+" do base64 chega como espaço
+		value := strings.ReplaceAll(q.Get("signature"), " ", "+")
+		result = h.checkSignature(content, signing.Signature{KeyID: q.Get("key_id"), Algorithm: signing.Algorithm, Value: value})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) checkSignature(content []byte, sig signing.Signature) models.SignatureVerification {
+	if sig.Algorithm == "" {
+		sig.Algorithm = signing.Algorithm
+	}
+	result := models.SignatureVerification{KeyID: sig.KeyID}
+	if err := h.Signer.Verify(content, sig); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	result.Valid = true
+	return result
+}
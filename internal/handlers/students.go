@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sistema-faculdade/internal/export"
 	"sistema-faculdade/internal/models"
 	"strconv"
 	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -24,11 +26,15 @@ func (h *Handler) CreateStudentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Chama o banco
-	id, err := h.Students.Create(&input)
+	id, err := h.Students.Create(&input, requestUser(r))
 	if err != nil {
 		log.Println(err)
 
 		msg := err.Error()
+		if msg == "matrícula com dígito verificador inválido" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
 		if strings.Contains(msg, "CPF já cadastrado") ||
 			strings.Contains(msg, "email já cadastrado") ||
 			strings.Contains(msg, "matrpicula já está cadastrado") {
@@ -48,6 +54,20 @@ func (h *Handler) CreateStudentHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllStudentsHandler(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{"id", "matricula", "nome", "cpf", "email", "sexo", "nascimento", "curso", "situacao", "ativo"}
+		exportList(w, format, "alunos", header, func(xw export.Writer) error {
+			return h.Students.Each(func(s models.Student) error {
+				return xw.Row(s.ID, s.RegistrationNumber, s.Name, s.CPF, s.Email, s.Gender, s.DateBirth, s.CourseName, s.Status, s.Active)
+			})
+		})
+		return
+	}
+
 	// Chama o banco
 	list, err := h.Students.GetAll()
 	if err != nil {
@@ -72,14 +92,23 @@ func (h *Handler) DeleteStudentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Chama o banco
-	err = h.Students.Delete(id)
+	// Inativar o aluno é registrado como evasão no histórico de situação
+	err = h.Students.ChangeStatus(&models.StudentStatusChange{
+		StudentID: id,
+		ToStatus:  models.StudentDroppedOut,
+		Reason:    "Inativado pelo cadastro de alunos",
+		ChangedBy: requestUser(r),
+	})
 	if err != nil {
 		// Se nenhum aluno foi encontrado retorna 404 (Not Found)
 		if err.Error() == fmt.Sprintf("nenhum aluno encontrado com o ID %d", id) {
 			http.Error(w, "Aluno não encontrado", http.StatusNotFound)
 			return
 		}
+		if strings.HasPrefix(err.Error(), "transição inválida") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 
 		http.Error(w, "Erro interno ao deletar", http.StatusBadRequest)
 		return
@@ -109,9 +138,28 @@ func (h *Handler) UpdateStudentHandler(w http.ResponseWriter, r *http.Request) {
 	// Garantir que o ID da struct seja o da URL
 	input.ID = id
 
+	current, err := h.Students.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+	if current == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+	if input.CourseID != 0 && input.CourseID != current.CourseID {
+		http.Error(w, "Para trocar o curso use POST /api/students/{id}/transfer", http.StatusConflict)
+		return
+	}
+
 	// Chama o banco
 	err = h.Students.Update(&input)
 	if err != nil {
+		if err.Error() == "matrícula com dígito verificador inválido" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err.Error() == fmt.Sprintf("nenhum aluno encontrado com o ID %d", id) {
 			http.Error(w, "Aluno não encontrado", http.StatusNotFound)
 			return
@@ -149,6 +197,34 @@ func (h *Handler) GetStudentByIDHandler(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(student)
 }
 
+// GetStudentByRegistrationHandler busca o aluno pelo número de matrícula, conferindo o dígito verificador
+func (h *Handler) GetStudentByRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	number := strings.TrimSpace(r.URL.Query().Get("number"))
+	if number == "" {
+		http.Error(w, "Informe a matrícula em ?number=", http.StatusBadRequest)
+		return
+	}
+
+	student, err := h.Students.GetByRegistrationNumber(number)
+	if err != nil {
+		if err.Error() == "matrícula com dígito verificador inválido" {
+			http.Error(w, "Matrícula inválida: dígito verificador não confere", http.StatusBadRequest)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro interno de servidor", http.StatusInternalServerError)
+		return
+	}
+
+	if student == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(student)
+}
+
 func (h *Handler) ActivateStudentHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 
@@ -158,8 +234,22 @@ func (h *Handler) ActivateStudentHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.Students.Activate(id)
+	err = h.Students.ChangeStatus(&models.StudentStatusChange{
+		StudentID: id,
+		ToStatus:  models.StudentEnrolled,
+		Reason:    "Reativado pelo cadastro de alunos",
+		ChangedBy: requestUser(r),
+	})
 	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhum aluno encontrado com o ID %d", id) {
+			http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "transição inválida") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
 		http.Error(w, "Erro ao reativar aluno", http.StatusInternalServerError)
 		return
 	}
@@ -167,3 +257,86 @@ func (h *Handler) ActivateStudentHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Aluno reativado com sucesso"})
 }
+
+// ChangeStudentStatusHandler registra uma nova situação acadêmica do aluno (formatura, evasão, desligamento...).
+// Trancamento e transferência só pelos endpoints próprios.
+func (h *Handler) ChangeStudentStatusHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		Status        string    `json:"status"`
+		Reason        string    `json:"reason"`
+		EffectiveDate time.Time `json:"effective_date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !models.ValidStudentStatus(input.Status) {
+		http.Error(w, "Situação inválida", http.StatusBadRequest)
+		return
+	}
+	// Trancamento e transferência têm fluxos próprios (cancelam matrículas, contam o prazo de
+	// integralização, aproveitam disciplinas) e não podem ser aplicados direto na situação
+	switch input.Status {
+	case models.StudentLeaveOfAbsence:
+		http.Error(w, "Use POST /api/students/{id}/leave-requests para trancar a matrícula", http.StatusUnprocessableEntity)
+		return
+	case models.StudentTransferred:
+		http.Error(w, "Use POST /api/students/{id}/transfer para transferir o aluno", http.StatusUnprocessableEntity)
+		return
+	}
+	if strings.TrimSpace(input.Reason) == "" {
+		http.Error(w, "O motivo da alteração é obrigatório", http.StatusBadRequest)
+		return
+	}
+
+	change := models.StudentStatusChange{
+		StudentID:     id,
+		ToStatus:      input.Status,
+		Reason:        input.Reason,
+		ChangedBy:     requestUser(r),
+		EffectiveDate: input.EffectiveDate,
+	}
+
+	err = h.Students.ChangeStatus(&change)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("nenhum aluno encontrado com o ID %d", id) {
+			http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "transição inválida") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Println(err)
+		http.Error(w, "Erro ao alterar situação do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
+
+func (h *Handler) GetStudentStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Students.StatusHistory(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico do aluno", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
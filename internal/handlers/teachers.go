@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/export"
 	"sistema-faculdade/internal/models"
 	"strconv"
 
@@ -20,7 +22,18 @@ func (h *Handler) CreateTeacherHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := h.Teachers.Create(&input)
+	if err := input.ValidateProfile(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.PreferredSchedule != "" {
+		if _, err := academic.ParseSchedule(input.PreferredSchedule); err != nil {
+			http.Error(w, "Horário de preferência inválido: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	id, err := h.Teachers.Create(&input, requestUser(r))
 	if err != nil {
 		log.Println(err)
 		http.Error(w, "Erro ao criar professor", http.StatusInternalServerError)
@@ -35,6 +48,26 @@ func (h *Handler) CreateTeacherHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllTeachersHandler(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if format != "" {
+		header := []string{
+			"id", "nome", "email", "cpf", "telefone", "departamento", "contratacao", "regime", "horas_contrato",
+			"titulacao", "areas_atuacao", "horario_preferencia", "ativo",
+		}
+		exportList(w, format, "professores", header, func(xw export.Writer) error {
+			return h.Teachers.Each(func(t models.Teacher) error {
+				return xw.Row(
+					t.ID, t.Name, t.Email, t.CPF, t.Telephone, t.DepartmentName, t.DateContract, t.ContractRegime, t.ContractHours,
+					t.AcademicTitle, t.ExpertiseAreas, t.PreferredSchedule, t.Active,
+				)
+			})
+		})
+		return
+	}
+
 	list, err := h.Teachers.GetAll()
 	if err != nil {
 		log.Println(err)
@@ -87,7 +120,18 @@ func (h *Handler) UpdateTeacherHandler(w http.ResponseWriter, r *http.Request) {
 
 	input.ID = id
 
-	err = h.Teachers.Update(&input)
+	if err := input.ValidateProfile(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.PreferredSchedule != "" {
+		if _, err := academic.ParseSchedule(input.PreferredSchedule); err != nil {
+			http.Error(w, "Horário de preferência inválido: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.Teachers.Update(&input, requestUser(r))
 	if err != nil {
 		if err.Error() == fmt.Sprintf("nenhum professor encontrado com o ID %d", id) {
 			http.Error(w, "Professor não encontrado", http.StatusNotFound)
@@ -145,3 +189,33 @@ func (h *Handler) ActivateTeacherHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Professor ativado com sucesso"})
 }
+
+// GetTeacherContractsHandler retorna o histórico de regime e horas contratadas do professor
+func (h *Handler) GetTeacherContractsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	teacher, err := h.Teachers.GetByID(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
+		return
+	}
+	if teacher == nil {
+		http.Error(w, "Professor não encontrado", http.StatusNotFound)
+		return
+	}
+
+	list, err := h.Teachers.GetContracts(id)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar histórico contratual", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sistema-faculdade/internal/models"
+	"strconv"
+	"strings"
+)
+
+// TransferStudentHandler troca o curso do aluno aproveitando as disciplinas já aprovadas
+func (h *Handler) TransferStudentHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		CourseID int    `json:"course_id"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Erro ao ler JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.CourseID < 1 || strings.TrimSpace(input.Reason) == "" {
+		http.Error(w, "Informe o novo curso e o motivo da transferência", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.Courses.GetByID(input.CourseID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar curso", http.StatusInternalServerError)
+		return
+	}
+	if course == nil {
+		http.Error(w, "Curso não encontrado", http.StatusNotFound)
+		return
+	}
+
+	// A transferência é autorizada pela coordenação do curso de destino
+	coordinator, courseIDs, ok := h.coordinatorScope(w, r)
+	if !ok || !h.authorizeCourse(w, courseIDs, course.ID) {
+		return
+	}
+
+	transfer := models.CourseTransfer{
+		StudentID:     studentID,
+		ToCourseID:    input.CourseID,
+		ToCourseName:  course.Name,
+		Reason:        input.Reason,
+		TransferredBy: coordinator.Name,
+	}
+
+	err = h.Transfers.Transfer(&transfer)
+	if err != nil {
+		switch err.Error() {
+		case fmt.Sprintf("nenhum aluno encontrado com o ID %d", studentID):
+			http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		case "somente alunos matriculados podem trocar de curso", "o aluno já pertence a este curso":
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.Println(err)
+			http.Error(w, "Erro ao transferir aluno", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+func (h *Handler) GetStudentTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.Transfers.GetByStudent(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao buscar transferências", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) GetTranscriptHandler(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || studentID < 1 {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	transcript, err := h.Transcripts.Get(studentID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Erro ao gerar histórico escolar", http.StatusInternalServerError)
+		return
+	}
+	if transcript == nil {
+		http.Error(w, "Aluno não encontrado", http.StatusNotFound)
+		return
+	}
+
+	if r.URL.Query().Get("signed") == "true" {
+		h.writeSigned(w, models.DocumentTranscript, transcript)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transcript)
+}
@@ -0,0 +1,101 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TimeZone usado nos eventos. Os horários das ofertas são sempre horário local da instituição.
+const TimeZone = "America/Sao_Paulo"
+
+// Event é um VEVENT. Start e End são horários locais (sem fuso), interpretados em TimeZone.
+type Event struct {
+	UID         string
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write serializa o calendário no formato iCalendar (RFC 5545)
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	line := func(s string) {
+		bw.WriteString(fold(s))
+		bw.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//UniSystem//Horarios//PT-BR")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escape(c.Name))
+	line("X-WR-TIMEZONE:" + TimeZone)
+
+	// Brasília não tem horário de verão desde 2019
+	line("BEGIN:VTIMEZONE")
+	line("TZID:" + TimeZone)
+	line("BEGIN:STANDARD")
+	line("DTSTART:19700101T000000")
+	line("TZOFFSETFROM:-0300")
+	line("TZOFFSETTO:-0300")
+	line("TZNAME:-03")
+	line("END:STANDARD")
+	line("END:VTIMEZONE")
+
+	for _, e := range c.Events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		line(fmt.Sprintf("DTSTART;TZID=%s:%s", TimeZone, e.Start.Format("20060102T150405")))
+		line(fmt.Sprintf("DTEND;TZID=%s:%s", TimeZone, e.End.Format("20060102T150405")))
+		line("SUMMARY:" + escape(e.Summary))
+		if e.Location != "" {
+			line("LOCATION:" + escape(e.Location))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// fold quebra linhas com mais de 75 octetos, sem partir caracteres UTF-8
+func fold(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+
+	var b strings.Builder
+	size := 0
+	for _, r := range s {
+		n := len(string(r))
+		if size+n > 75 {
+			b.WriteString("\r\n ")
+			size = 1
+		}
+		b.WriteRune(r)
+		size += n
+	}
+	return b.String()
+}
@@ -0,0 +1,246 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sistema-faculdade/internal/models"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// column é uma coluna aceita no cabeçalho do arquivo
+type column struct {
+	name     string
+	required bool
+}
+
+// row é uma linha do arquivo com os valores indexados pelo nome da coluna
+type row struct {
+	line   int
+	values map[string]string
+}
+
+func (r row) get(name string) string {
+	return strings.TrimSpace(r.values[name])
+}
+
+// readRows lê o CSV (separado por vírgula ou ponto e vírgula). O cabeçalho é obrigatório e
+// define a ordem das colunas; colunas desconhecidas ou obrigatórias ausentes invalidam o arquivo.
+func readRows(body io.Reader, columns []column) ([]row, []models.ImportRowError) {
+	br := bufio.NewReader(body)
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _ := br.Peek(br.Size()); strings.Contains(strings.SplitN(string(first), "\n", 2)[0], ";") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, []models.ImportRowError{{Line: 1, Message: "arquivo vazio"}}
+	}
+	if err != nil {
+		return nil, []models.ImportRowError{{Line: 1, Message: err.Error()}}
+	}
+
+	known := map[string]bool{}
+	for _, c := range columns {
+		known[c.name] = true
+	}
+
+	var errs []models.ImportRowError
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			errs = append(errs, models.ImportRowError{Line: 1, Field: name, Message: "coluna desconhecida"})
+			continue
+		}
+		if _, dup := index[name]; dup {
+			errs = append(errs, models.ImportRowError{Line: 1, Field: name, Message: "coluna repetida"})
+			continue
+		}
+		index[name] = i
+	}
+	for _, c := range columns {
+		if _, ok := index[c.name]; c.required && !ok {
+			errs = append(errs, models.ImportRowError{Line: 1, Field: c.name, Message: "coluna obrigatória ausente"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line := 0
+			if pe, ok := err.(*csv.ParseError); ok {
+				line = pe.Line
+			}
+			errs = append(errs, models.ImportRowError{Line: line, Message: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		blank := true
+		for _, v := range record {
+			if strings.TrimSpace(v) != "" {
+				blank = false
+				break
+			}
+		}
+		if blank {
+			continue
+		}
+
+		r := row{line: line, values: map[string]string{}}
+		for name, i := range index {
+			if i < len(record) {
+				r.values[name] = record[i]
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows, errs
+}
+
+// rowCheck junta os erros de uma linha
+type rowCheck struct {
+	line int
+	errs *[]models.ImportRowError
+	ok   bool
+}
+
+func newRowCheck(line int, errs *[]models.ImportRowError) *rowCheck {
+	return &rowCheck{line: line, errs: errs, ok: true}
+}
+
+func (c *rowCheck) fail(field, format string, args ...any) {
+	*c.errs = append(*c.errs, models.ImportRowError{Line: c.line, Field: field, Message: fmt.Sprintf(format, args...)})
+	c.ok = false
+}
+
+// required confere o preenchimento e o tamanho máximo da coluna
+func (c *rowCheck) required(r row, field string, max int) string {
+	v := r.get(field)
+	if v == "" {
+		c.fail(field, "campo obrigatório")
+	} else if max > 0 && len([]rune(v)) > max {
+		c.fail(field, "máximo de %d caracteres", max)
+	}
+	return v
+}
+
+func (c *rowCheck) date(r row, field string, required bool) time.Time {
+	v := r.get(field)
+	if v == "" {
+		if required {
+			c.fail(field, "campo obrigatório")
+		}
+		return time.Time{}
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			if t.After(time.Now()) {
+				c.fail(field, "data no futuro")
+			}
+			return t
+		}
+	}
+	c.fail(field, "data inválida %q", v)
+	return time.Time{}
+}
+
+// unique acusa valores repetidos no arquivo (seen guarda a linha da primeira ocorrência)
+func (c *rowCheck) unique(seen map[string]int, field, value string) {
+	if value == "" {
+		return
+	}
+	key := strings.ToLower(value)
+	if first, dup := seen[key]; dup {
+		c.fail(field, "valor repetido no arquivo (linha %d)", first)
+		return
+	}
+	seen[key] = c.line
+}
+
+// NormalizeCPF remove pontos, traços e espaços do CPF
+func NormalizeCPF(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '/' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ValidCPF confere os onze dígitos e os dois dígitos verificadores do CPF
+func ValidCPF(cpf string) bool {
+	if len(cpf) != 11 {
+		return false
+	}
+	same := true
+	for i := range 11 {
+		if cpf[i] < '0' || cpf[i] > '9' {
+			return false
+		}
+		if cpf[i] != cpf[0] {
+			same = false
+		}
+	}
+	if same {
+		return false
+	}
+
+	for _, n := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += int(cpf[i]-'0') * (n + 1 - i)
+		}
+		dv := sum * 10 % 11
+		if dv == 10 {
+			dv = 0
+		}
+		if dv != int(cpf[n]-'0') {
+			return false
+		}
+	}
+	return true
+}
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func ValidEmail(email string) bool {
+	return len(email) <= 120 && emailPattern.MatchString(email)
+}
+
+func (c *rowCheck) cpf(r row) string {
+	cpf := NormalizeCPF(r.get("cpf"))
+	if cpf == "" {
+		c.fail("cpf", "campo obrigatório")
+	} else if !ValidCPF(cpf) {
+		c.fail("cpf", "CPF inválido")
+	}
+	return cpf
+}
+
+func (c *rowCheck) email(r row, required bool) string {
+	email := strings.ToLower(r.get("email"))
+	if email == "" {
+		if required {
+			c.fail("email", "campo obrigatório")
+		}
+	} else if !ValidEmail(email) {
+		c.fail("email", "email inválido")
+	}
+	return email
+}
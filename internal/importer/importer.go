@@ -0,0 +1,207 @@
+// Package importer lê e valida os arquivos CSV da importação em lote de alunos, professores e
+// disciplinas. É usado pela rota POST /api/import/{entity} e pelo comando cmd/import.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sistema-faculdade/internal/academic"
+	"sistema-faculdade/internal/data"
+	"sistema-faculdade/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Columns lista as colunas aceitas por entidade, as obrigatórias primeiro
+var Columns = map[string][]column{
+	models.ImportStudents: {
+		{"name", true}, {"cpf", true}, {"date_birth", true}, {"gender", true}, {"course", true},
+		{"email", false}, {"registration_number", false},
+	},
+	models.ImportTeachers: {
+		{"name", true}, {"email", true}, {"cpf", true}, {"telephone", true}, {"department", true},
+		{"date_contract", true}, {"contract_hours", false}, {"contract_regime", false},
+		{"academic_title", false}, {"expertise_areas", false}, {"preferred_schedule", false},
+	},
+	models.ImportDisciplines: {
+		{"code", true}, {"name", true}, {"credits", true}, {"workload_hours", true},
+		{"department", true}, {"description", false},
+	},
+}
+
+// Import valida o arquivo e grava as linhas válidas numa única transação (upsert pelo CPF ou
+// pelo código). Linhas com erro são recusadas e aparecem no relatório; em dryRun nada é gravado.
+func Import(repo data.ImportRepository, entity string, body io.Reader, dryRun bool, changedBy string) (*models.ImportResult, error) {
+	columns, ok := Columns[entity]
+	if !ok {
+		return nil, fmt.Errorf("importação: entidade desconhecida %q", entity)
+	}
+
+	rows, errs := readRows(body, columns)
+
+	var result *models.ImportResult
+	var err error
+	switch entity {
+	case models.ImportStudents:
+		list := parseStudents(rows, &errs)
+		result, err = repo.ImportStudents(list, dryRun, changedBy)
+	case models.ImportTeachers:
+		list := parseTeachers(rows, &errs)
+		result, err = repo.ImportTeachers(list, dryRun, changedBy)
+	case models.ImportDisciplines:
+		list := parseDisciplines(rows, &errs)
+		result, err = repo.ImportDisciplines(list, dryRun)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Entity = entity
+	result.Errors = append(errs, result.Errors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+
+	rejected := map[int]bool{}
+	for _, e := range result.Errors {
+		if e.Line > 1 {
+			rejected[e.Line] = true
+		}
+	}
+	result.Rows = len(rows)
+	result.Rejected = len(rejected)
+	return result, nil
+}
+
+func parseStudents(rows []row, errs *[]models.ImportRowError) []models.StudentImportRow {
+	var list []models.StudentImportRow
+	cpfs, emails, numbers := map[string]int{}, map[string]int{}, map[string]int{}
+
+	for _, r := range rows {
+		c := newRowCheck(r.line, errs)
+		s := models.Student{
+			Name:               c.required(r, "name", 120),
+			CPF:                c.cpf(r),
+			DateBirth:          c.date(r, "date_birth", true),
+			Gender:             strings.ToUpper(c.required(r, "gender", 0)),
+			RegistrationNumber: r.get("registration_number"),
+		}
+		course := c.required(r, "course", 0)
+
+		if email := c.email(r, false); email != "" {
+			s.Email = &email
+		}
+		switch s.Gender {
+		case "", "M", "F", "O":
+			// vazio já foi acusado como campo obrigatório
+		default:
+			c.fail("gender", "use M, F ou O")
+		}
+		if len(s.RegistrationNumber) > 30 {
+			c.fail("registration_number", "máximo de 30 caracteres")
+		}
+
+		c.unique(cpfs, "cpf", s.CPF)
+		if s.Email != nil {
+			c.unique(emails, "email", *s.Email)
+		}
+		c.unique(numbers, "registration_number", s.RegistrationNumber)
+
+		if c.ok {
+			list = append(list, models.StudentImportRow{Line: r.line, Student: s, Course: course})
+		}
+	}
+	return list
+}
+
+func parseTeachers(rows []row, errs *[]models.ImportRowError) []models.TeacherImportRow {
+	var list []models.TeacherImportRow
+	cpfs, emails := map[string]int{}, map[string]int{}
+
+	for _, r := range rows {
+		c := newRowCheck(r.line, errs)
+		t := models.Teacher{
+			Name:              c.required(r, "name", 120),
+			Email:             c.email(r, true),
+			CPF:               c.cpf(r),
+			Telephone:         c.required(r, "telephone", 20),
+			DateContract:      c.date(r, "date_contract", true),
+			PreferredSchedule: r.get("preferred_schedule"),
+		}
+		department := c.required(r, "department", 0)
+
+		if v := r.get("contract_hours"); v != "" {
+			hours, err := strconv.Atoi(v)
+			if err != nil {
+				c.fail("contract_hours", "número inválido %q", v)
+			} else {
+				t.ContractHours = &hours
+			}
+		}
+		if v := strings.ToLower(r.get("contract_regime")); v != "" {
+			t.ContractRegime = &v
+		}
+		if v := strings.ToLower(r.get("academic_title")); v != "" {
+			t.AcademicTitle = &v
+		}
+		// Áreas de atuação separadas por "|" para não conflitar com o separador do CSV
+		if v := r.get("expertise_areas"); v != "" {
+			t.ExpertiseAreas = strings.Split(v, "|")
+		}
+
+		if err := t.ValidateProfile(); err != nil {
+			c.fail("", "%v", err)
+		}
+		if t.PreferredSchedule != "" {
+			if _, err := academic.ParseSchedule(t.PreferredSchedule); err != nil {
+				c.fail("preferred_schedule", "horário de preferência inválido: %v", err)
+			}
+		}
+
+		c.unique(cpfs, "cpf", t.CPF)
+		c.unique(emails, "email", t.Email)
+
+		if c.ok {
+			list = append(list, models.TeacherImportRow{Line: r.line, Teacher: t, Department: department})
+		}
+	}
+	return list
+}
+
+func parseDisciplines(rows []row, errs *[]models.ImportRowError) []models.DisciplineImportRow {
+	var list []models.DisciplineImportRow
+	codes, names := map[string]int{}, map[string]int{}
+
+	for _, r := range rows {
+		c := newRowCheck(r.line, errs)
+		d := models.Discipline{
+			Code:        strings.ToUpper(c.required(r, "code", 20)),
+			Name:        c.required(r, "name", 120),
+			Description: r.get("description"),
+		}
+		department := c.required(r, "department", 0)
+
+		d.Credits = c.positive(r, "credits")
+		d.WorkloadHours = c.positive(r, "workload_hours")
+
+		c.unique(codes, "code", d.Code)
+		c.unique(names, "name", d.Name)
+
+		if c.ok {
+			list = append(list, models.DisciplineImportRow{Line: r.line, Discipline: d, Department: department})
+		}
+	}
+	return list
+}
+
+func (c *rowCheck) positive(r row, field string) int {
+	v := r.get(field)
+	if v == "" {
+		c.fail(field, "campo obrigatório")
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		c.fail(field, "deve ser um número inteiro maior que zero")
+	}
+	return n
+}
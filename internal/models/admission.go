@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Formas de ingresso (enum admission_method)
+const (
+	AdmissionVestibular = "vestibular"
+	AdmissionEnem       = "enem"
+)
+
+// Situações do candidato (enum applicant_status)
+const (
+	ApplicantRegistered = "registered"
+	ApplicantCalled     = "called"
+	ApplicantConfirmed  = "confirmed"
+	ApplicantDeclined   = "declined"
+	ApplicantForfeited  = "forfeited"
+)
+
+// AdmissionProcess é um processo seletivo com as vagas por curso
+type AdmissionProcess struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Method     string          `json:"method"`
+	SemesterID int             `json:"semester_id"`
+	Semester   string          `json:"semester"`
+	Seats      []AdmissionSeat `json:"seats"`
+	CreatedBy  string          `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AdmissionSeat são as vagas de um curso no processo e quantas já foram ocupadas
+type AdmissionSeat struct {
+	CourseID   int    `json:"course_id"`
+	CourseName string `json:"course_name"`
+	Seats      int    `json:"seats"`
+	Applicants int    `json:"applicants"`
+	Called     int    `json:"called"`
+	Confirmed  int    `json:"confirmed"`
+}
+
+// EnemScores são as notas do ENEM por área
+type EnemScores struct {
+	Languages  float64 `json:"languages"`
+	Humanities float64 `json:"humanities"`
+	Sciences   float64 `json:"sciences"`
+	Math       float64 `json:"math"`
+	Essay      float64 `json:"essay"`
+}
+
+// Final é a média simples das cinco áreas
+func (s EnemScores) Final() float64 {
+	return (s.Languages + s.Humanities + s.Sciences + s.Math + s.Essay) / 5
+}
+
+type Applicant struct {
+	ID         int         `json:"id"`
+	ProcessID  int         `json:"process_id"`
+	CourseID   int         `json:"course_id"`
+	CourseName string      `json:"course_name"`
+	Name       string      `json:"name"`
+	CPF        string      `json:"cpf"`
+	Email      *string     `json:"email"`
+	Gender     string      `json:"gender"`
+	DateBirth  time.Time   `json:"date_birth"`
+	Enem       *EnemScores `json:"enem,omitempty"`
+	FinalScore *float64    `json:"final_score"`
+	Rank       *int        `json:"rank"`
+	Status     string      `json:"status"`
+	CallNumber *int        `json:"call_number"`
+	CalledAt   *time.Time  `json:"called_at"`
+	StudentID  *int        `json:"student_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// ApplicantScore é uma linha da importação de notas: as áreas do ENEM ou só a nota final (vestibular)
+type ApplicantScore struct {
+	CPF        string
+	Enem       *EnemScores
+	FinalScore float64
+}
+
+// AdmissionCall é o resultado de uma chamada do processo seletivo
+type AdmissionCall struct {
+	CallNumber int         `json:"call_number"`
+	Called     []Applicant `json:"called"`
+	Forfeited  int         `json:"forfeited"`
+}
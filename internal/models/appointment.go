@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Appointment é um mandato de professor em uma função (chefia de departamento ou coordenação de curso).
+// EndDate nulo indica mandato sem término definido.
+type Appointment struct {
+	ID          int        `json:"id"`
+	TeacherID   int        `json:"teacher_id"`
+	TeacherName string     `json:"teacher_name"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	AssignedBy  string     `json:"assigned_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type DepartmentHead struct {
+	DepartmentID int `json:"department_id"`
+	Appointment
+}
+
+type CourseCoordinator struct {
+	CourseID int `json:"course_id"`
+	Appointment
+}
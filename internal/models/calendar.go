@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Tipos de evento do calendário acadêmico (enum calendar_event_kind)
+const (
+	CalendarHoliday  = "holiday"
+	CalendarRecess   = "recess"
+	CalendarExamWeek = "exam_week"
+)
+
+// CalendarEvent é um período do calendário acadêmico de um semestre
+type CalendarEvent struct {
+	ID          int       `json:"id"`
+	SemesterID  int       `json:"semester_id"`
+	Kind        string    `json:"kind"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	Description string    `json:"description"`
+}
+
+// NonTeaching indica se o evento suspende as aulas. Semanas de prova continuam sendo dias letivos.
+func (e *CalendarEvent) NonTeaching() bool {
+	return e.Kind == CalendarHoliday || e.Kind == CalendarRecess
+}
+
+// Covers indica se a data está dentro do período do evento
+func (e *CalendarEvent) Covers(day time.Time) bool {
+	d := day.Format("2006-01-02")
+	return d >= e.StartDate.Format("2006-01-02") && d <= e.EndDate.Format("2006-01-02")
+}
+
+// ParseCalendarKind aceita o nome do enum ou o equivalente em português (usado na importação)
+func ParseCalendarKind(s string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case CalendarHoliday, "feriado":
+		return CalendarHoliday, true
+	case CalendarRecess, "recesso":
+		return CalendarRecess, true
+	case CalendarExamWeek, "provas", "semana de provas":
+		return CalendarExamWeek, true
+	}
+	return "", false
+}
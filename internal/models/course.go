@@ -5,9 +5,27 @@ import (
 )
 
 type Course struct {
-	ID                   int       `json:"id"`
-	Name                 string    `json:"name"`
-	TotalCreditsRequired int       `json:"total_credits_required"`
-	DurationSemesters    int       `json:"duration_semesters"`
-	CreatedAt            time.Time `json:"created_at"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Sigla usada no número de matrícula
+	Code                 *string `json:"code"`
+	TotalCreditsRequired int     `json:"total_credits_required"`
+	DurationSemesters    int     `json:"duration_semesters"`
+	MaxDurationSemesters *int    `json:"max_duration_semesters"`
+	// Cursos com aprovação da coordenação recebem pedidos de matrícula em vez de matrículas diretas
+	RequiresEnrollmentApproval bool `json:"requires_enrollment_approval"`
+	MinCreditsPerSemester      *int `json:"min_credits_per_semester"`
+	MaxCreditsPerSemester      *int `json:"max_credits_per_semester"`
+	// Coordenador do curso com mandato em vigor
+	CoordinatorID   *int      `json:"coordinator_id"`
+	CoordinatorName *string   `json:"coordinator_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MaxDuration retorna o prazo máximo de integralização em semestres (padrão: 1,5x a duração)
+func (c *Course) MaxDuration() int {
+	if c.MaxDurationSemesters != nil {
+		return *c.MaxDurationSemesters
+	}
+	return (c.DurationSemesters*3 + 1) / 2
 }
@@ -0,0 +1,12 @@
+package models
+
+// CurriculumItem é uma disciplina da matriz curricular de um curso
+type CurriculumItem struct {
+	CourseID          int    `json:"course_id"`
+	DisciplineID      int    `json:"discipline_id"`
+	DisciplineName    string `json:"discipline_name"`
+	DisciplineCode    string `json:"discipline_code"`
+	Credits           int    `json:"credits"`
+	SuggestedSemester *int   `json:"suggested_semester"`
+	Mandatory         bool   `json:"mandatory"`
+}
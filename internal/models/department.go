@@ -3,8 +3,11 @@ package models
 import "time"
 
 type Department struct {
-	ID           int       `json:"id"`
-	Name         string    `json:"name"`
-	Abbreviation string    `json:"abbreviation"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	// Chefe do departamento com mandato em vigor
+	HeadID    *int      `json:"head_id"`
+	HeadName  *string   `json:"head_name"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// GraduationAudit é a conferência dos requisitos de conclusão do curso: todas as disciplinas
+// obrigatórias da matriz cumpridas e o total de créditos do curso integralizado
+type GraduationAudit struct {
+	StudentID        int              `json:"student_id"`
+	CourseID         int              `json:"course_id"`
+	CourseName       string           `json:"course_name"`
+	RequiredCredits  int              `json:"required_credits"`
+	EarnedCredits    int              `json:"earned_credits"`
+	MissingMandatory []CurriculumItem `json:"missing_mandatory"`
+	Eligible         bool             `json:"eligible"`
+	// Pending descreve o que falta para a conclusão
+	Pending []string `json:"pending"`
+}
+
+// Diploma é o registro de um diploma digital emitido
+type Diploma struct {
+	ID             int       `json:"id"`
+	Code           string    `json:"code"`
+	RegistryNumber string    `json:"registry_number"`
+	StudentID      int       `json:"student_id"`
+	StudentName    string    `json:"student_name"`
+	CourseID       int       `json:"course_id"`
+	CourseName     string    `json:"course_name"`
+	GraduationDate time.Time `json:"graduation_date"`
+	XMLHash        string    `json:"xml_hash"`
+	SignatureKeyID string    `json:"signature_key_id"`
+	IssuedBy       string    `json:"issued_by"`
+	IssuedAt       time.Time `json:"issued_at"`
+}
+
+// DiplomaVerification é a resposta da verificação pública de um diploma
+type DiplomaVerification struct {
+	Valid          bool       `json:"valid"`
+	Code           string     `json:"code"`
+	RegistryNumber string     `json:"registry_number,omitempty"`
+	StudentName    string     `json:"student_name,omitempty"`
+	CPF            string     `json:"cpf,omitempty"`
+	CourseName     string     `json:"course_name,omitempty"`
+	GraduationDate *time.Time `json:"graduation_date,omitempty"`
+	IssuedAt       *time.Time `json:"issued_at,omitempty"`
+	XMLHash        string     `json:"xml_hash,omitempty"`
+	SignatureValid *bool      `json:"signature_valid,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de documento oficial (enum document_kind)
+const (
+	DocumentEnrollment = "enrollment_declaration"
+	DocumentAttendance = "attendance_declaration"
+	DocumentTranscript = "transcript"
+	// DocumentGradeSheet é a pauta de notas da oferta, emitida apenas como JSON assinado
+	DocumentGradeSheet = "grade_sheet"
+	// DocumentDiploma é o diploma digital em XML, registrado à parte na tabela diplomas
+	DocumentDiploma = "diploma"
+)
+
+// IssuedDocument é o registro de um documento emitido. Content guarda os dados impressos no
+// documento e ContentHash o SHA-256 do PDF entregue.
+type IssuedDocument struct {
+	ID          int             `json:"id"`
+	Code        string          `json:"code"`
+	Kind        string          `json:"kind"`
+	StudentID   int             `json:"student_id"`
+	StudentName string          `json:"student_name"`
+	SemesterID  *int            `json:"semester_id"`
+	Content     json.RawMessage `json:"content,omitempty"`
+	ContentHash string          `json:"content_hash"`
+	// SignatureKeyID e Signature são a assinatura Ed25519 do PDF e a chave que a fez
+	SignatureKeyID string    `json:"signature_key_id"`
+	Signature      string    `json:"signature"`
+	IssuedBy       string    `json:"issued_by"`
+	IssuedAt       time.Time `json:"issued_at"`
+}
+
+// DocumentDiscipline é uma disciplina cursada no semestre, como aparece nas declarações
+type DocumentDiscipline struct {
+	Code          string   `json:"code"`
+	Name          string   `json:"name"`
+	Schedule      string   `json:"schedule"`
+	WorkloadHours int      `json:"workload_hours"`
+	Absences      int      `json:"absences"`
+	Frequency     *float64 `json:"frequency"`
+	Status        string   `json:"status"`
+}
+
+// DocumentContent são os dados impressos no documento. O CPF é guardado mascarado, pois o
+// conteúdo é exibido na verificação pública.
+type DocumentContent struct {
+	Institution        string               `json:"institution"`
+	StudentName        string               `json:"student_name"`
+	CPF                string               `json:"cpf"`
+	RegistrationNumber string               `json:"registration_number"`
+	CourseName         string               `json:"course_name"`
+	Semester           string               `json:"semester,omitempty"`
+	Disciplines        []DocumentDiscipline `json:"disciplines,omitempty"`
+	Transcript         *Transcript          `json:"transcript,omitempty"`
+}
+
+// DocumentVerification é a resposta da verificação pública de um documento
+type DocumentVerification struct {
+	Valid       bool       `json:"valid"`
+	Code        string     `json:"code"`
+	Kind        string     `json:"kind,omitempty"`
+	IssuedAt    *time.Time `json:"issued_at,omitempty"`
+	ContentHash string     `json:"content_hash,omitempty"`
+	HashMatches *bool      `json:"hash_matches,omitempty"`
+	// SignatureValid indica se a assinatura do PDF registrado confere com uma chave pública ativa
+	SignatureValid *bool            `json:"signature_valid,omitempty"`
+	SignatureKeyID string           `json:"signature_key_id,omitempty"`
+	Signature      string           `json:"signature,omitempty"`
+	Content        *DocumentContent `json:"content,omitempty"`
+}
+
+// SignatureVerification é o resultado da conferência da assinatura de um arquivo apresentado
+type SignatureVerification struct {
+	Valid    bool       `json:"valid"`
+	KeyID    string     `json:"key_id,omitempty"`
+	Kind     string     `json:"kind,omitempty"`
+	IssuedAt *time.Time `json:"issued_at,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// GradeSheet é a pauta de notas e frequência de uma oferta
+type GradeSheet struct {
+	OfferID       int            `json:"offer_id"`
+	Registrations []Registration `json:"registrations"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// EnrollmentRequest é o pedido de matrícula em uma oferta, usado nos cursos que exigem aprovação da coordenação
+type EnrollmentRequest struct {
+	ID               int        `json:"id"`
+	StudentID        int        `json:"student_id"`
+	StudentName      string     `json:"student_name"`
+	OfferID          int        `json:"offer_id"`
+	SemesterID       int        `json:"semester_id"`
+	DisciplineID     int        `json:"discipline_id"`
+	DisciplineName   string     `json:"discipline_name"`
+	Credits          int        `json:"credits"`
+	Status           string     `json:"status"`
+	RequestedAt      time.Time  `json:"requested_at"`
+	ReviewedBy       *string    `json:"reviewed_by"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+	RejectionReason  *string    `json:"rejection_reason"`
+	SuggestedOfferID *int       `json:"suggested_offer_id"`
+	RegistrationID   *int       `json:"registration_id"`
+}
+
+// EnrollmentDecision é a análise da coordenação sobre um pedido de matrícula
+type EnrollmentDecision struct {
+	RequestID        int    `json:"request_id"`
+	Approve          bool   `json:"approve"`
+	Reason           string `json:"reason"`
+	SuggestedOfferID *int   `json:"suggested_offer_id"`
+}
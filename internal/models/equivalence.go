@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Equivalence indica que a disciplina alvo é cumprida quando todas as disciplinas de origem foram aprovadas
+type Equivalence struct {
+	ID                   int       `json:"id"`
+	TargetDisciplineID   int       `json:"target_discipline_id"`
+	TargetDisciplineName string    `json:"target_discipline_name"`
+	SourceDisciplineIDs  []int     `json:"source_discipline_ids"`
+	Notes                *string   `json:"notes"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// ExternalCredit é o pedido de aproveitamento de uma disciplina cursada em outra instituição
+type ExternalCredit struct {
+	ID                    int        `json:"id"`
+	StudentID             int        `json:"student_id"`
+	StudentName           string     `json:"student_name"`
+	DisciplineID          int        `json:"discipline_id"`
+	DisciplineName        string     `json:"discipline_name"`
+	Credits               int        `json:"credits"`
+	Institution           string     `json:"institution"`
+	ExternalCode          *string    `json:"external_code"`
+	ExternalName          string     `json:"external_name"`
+	ExternalWorkloadHours int        `json:"external_workload_hours"`
+	Grade                 *float64   `json:"grade"`
+	CompletedYear         int        `json:"completed_year"`
+	Status                string     `json:"status"`
+	RequestedAt           time.Time  `json:"requested_at"`
+	ReviewedBy            *string    `json:"reviewed_by"`
+	ReviewedAt            *time.Time `json:"reviewed_at"`
+	ReviewNotes           *string    `json:"review_notes"`
+}
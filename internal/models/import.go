@@ -0,0 +1,48 @@
+package models
+
+// Entidades aceitas pela importação em lote (POST /api/import/{entity})
+const (
+	ImportStudents    = "students"
+	ImportTeachers    = "teachers"
+	ImportDisciplines = "disciplines"
+)
+
+// ImportRowError é um problema encontrado numa linha do arquivo importado.
+// Line é a linha do CSV (o cabeçalho é a linha 1).
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult é o relatório da importação. Em simulação (DryRun) nada é gravado, mas
+// Inserted e Updated indicam o que seria feito.
+type ImportResult struct {
+	Entity   string           `json:"entity"`
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Rejected int              `json:"rejected"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// Linhas já validadas do arquivo. Curso e departamento vêm como texto (ID, sigla ou nome) e
+// são resolvidos na gravação.
+type StudentImportRow struct {
+	Line    int
+	Student Student
+	Course  string
+}
+
+type TeacherImportRow struct {
+	Line       int
+	Teacher    Teacher
+	Department string
+}
+
+type DisciplineImportRow struct {
+	Line       int
+	Discipline Discipline
+	Department string
+}
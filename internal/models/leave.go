@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Situações de solicitações com aprovação (enum request_status)
+const (
+	RequestPending  = "pending"
+	RequestApproved = "approved"
+	RequestRejected = "rejected"
+)
+
+// LeaveRequest é uma solicitação de trancamento de um semestre
+type LeaveRequest struct {
+	ID          int        `json:"id"`
+	StudentID   int        `json:"student_id"`
+	StudentName string     `json:"student_name"`
+	SemesterID  int        `json:"semester_id"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ReviewedBy  *string    `json:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	ReviewNotes *string    `json:"review_notes"`
+	// StartedAt é quando o aluno passou para "trancado": na aprovação ou no início do semestre
+	StartedAt        *time.Time `json:"started_at"`
+	ReturnSemesterID *int       `json:"return_semester_id"`
+	ReturnedAt       *time.Time `json:"returned_at"`
+}
+
+// AcademicClock é a contagem de semestres usada para o prazo máximo de integralização.
+// Semestres trancados não contam.
+type AcademicClock struct {
+	StudentID            int `json:"student_id"`
+	ElapsedSemesters     int `json:"elapsed_semesters"`
+	LeaveSemesters       int `json:"leave_semesters"`
+	CountedSemesters     int `json:"counted_semesters"`
+	DurationSemesters    int `json:"duration_semesters"`
+	MaxDurationSemesters int `json:"max_duration_semesters"`
+	RemainingSemesters   int `json:"remaining_semesters"`
+}
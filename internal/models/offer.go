@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// DisciplineOffer é a oferta de uma disciplina em um semestre acadêmico
+type DisciplineOffer struct {
+	ID             int    `json:"id"`
+	DisciplineID   int    `json:"discipline_id"`
+	DisciplineName string `json:"discipline_name"`
+	DisciplineCode string `json:"discipline_code"`
+	SemesterID     int    `json:"semester_id"`
+	TeacherID      int    `json:"teacher_id"`
+	TeacherName    string `json:"teacher_name"`
+	Schedule       string `json:"schedule"`
+	Room           string `json:"room"`
+	PlannedHours   *int   `json:"planned_hours"`
+	Capacity       *int   `json:"capacity"`
+	// ClosedAt é o fechamento do diário; depois dele a situação das matrículas é definitiva
+	ClosedAt *time.Time `json:"closed_at"`
+	ClosedBy *string    `json:"closed_by"`
+}
+
+// Papéis do professor na equipe da oferta (enum offer_teacher_role)
+const (
+	RoleLead      = "lead"
+	RoleAssistant = "assistant"
+)
+
+// OfferTeacher é um professor da equipe da oferta com a fração da carga horária que assume
+type OfferTeacher struct {
+	OfferID     int     `json:"offer_id"`
+	TeacherID   int     `json:"teacher_id"`
+	TeacherName string  `json:"teacher_name"`
+	Role        string  `json:"role"`
+	HourShare   float64 `json:"hour_share"`
+}
+
+// OfferSubstitution é a substituição de um professor da equipe em um período
+type OfferSubstitution struct {
+	ID                    int       `json:"id"`
+	OfferID               int       `json:"offer_id"`
+	AbsentTeacherID       int       `json:"absent_teacher_id"`
+	AbsentTeacherName     string    `json:"absent_teacher_name"`
+	SubstituteTeacherID   int       `json:"substitute_teacher_id"`
+	SubstituteTeacherName string    `json:"substitute_teacher_name"`
+	StartDate             time.Time `json:"start_date"`
+	EndDate               time.Time `json:"end_date"`
+	Reason                string    `json:"reason"`
+	CreatedBy             string    `json:"created_by"`
+	CreatedAt             time.Time `json:"created_at"`
+}
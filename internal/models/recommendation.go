@@ -0,0 +1,30 @@
+package models
+
+// Recommendation é uma oferta analisada para o planejamento de matrícula do aluno
+type Recommendation struct {
+	OfferID              int    `json:"offer_id"`
+	DisciplineID         int    `json:"discipline_id"`
+	DisciplineCode       string `json:"discipline_code"`
+	DisciplineName       string `json:"discipline_name"`
+	Credits              int    `json:"credits"`
+	Schedule             string `json:"schedule"`
+	SuggestedSemester    *int   `json:"suggested_semester"`
+	Mandatory            bool   `json:"mandatory"`
+	Overdue              bool   `json:"overdue"`
+	SkipReason           string `json:"skip_reason,omitempty"`
+	MissingPrerequisites []int  `json:"missing_prerequisites,omitempty"`
+}
+
+// RecommendationResult separa as ofertas recomendadas das que não puderam entrar no plano
+type RecommendationResult struct {
+	StudentID  int  `json:"student_id"`
+	SemesterID int  `json:"semester_id"`
+	Level      int  `json:"level"`
+	MaxCredits *int `json:"max_credits"`
+	// RegisteredCredits são os créditos das matrículas que o aluno já tem no semestre;
+	// TotalCredits soma a eles os créditos recomendados
+	RegisteredCredits int              `json:"registered_credits"`
+	TotalCredits      int              `json:"total_credits"`
+	Recommended       []Recommendation `json:"recommended"`
+	Skipped           []Recommendation `json:"skipped"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ReenrollmentCampaign é o período em que os alunos confirmam a rematrícula para um semestre
+type ReenrollmentCampaign struct {
+	ID         int        `json:"id"`
+	SemesterID int        `json:"semester_id"`
+	OpensAt    time.Time  `json:"opens_at"`
+	ClosesAt   time.Time  `json:"closes_at"`
+	CreatedBy  string     `json:"created_by"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	ClosedBy   *string    `json:"closed_by"`
+	Confirmed  int        `json:"confirmed"`
+	Pending    int        `json:"pending"`
+}
+
+// IsOpen indica se a campanha aceita confirmações no dia informado
+func (c *ReenrollmentCampaign) IsOpen(day time.Time) bool {
+	if c.ClosedAt != nil {
+		return false
+	}
+	d := day.Format("2006-01-02")
+	return d >= c.OpensAt.Format("2006-01-02") && d <= c.ClosesAt.Format("2006-01-02")
+}
+
+// Reenrollment é a confirmação de rematrícula do aluno com as ofertas escolhidas
+type Reenrollment struct {
+	CampaignID  int       `json:"campaign_id"`
+	StudentID   int       `json:"student_id"`
+	OfferIDs    []int     `json:"offer_ids"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
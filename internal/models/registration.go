@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Registration é a matrícula de um aluno em uma oferta de disciplina
+type Registration struct {
+	ID             int       `json:"id"`
+	StudentID      int       `json:"student_id"`
+	StudentName    string    `json:"student_name"`
+	OfferID        int       `json:"offer_id"`
+	SemesterID     int       `json:"semester_id"`
+	DisciplineName string    `json:"discipline_name"`
+	FinalGrade     *float64  `json:"final_grade"`
+	Frequency      *float64  `json:"frequency"`
+	Absences       int       `json:"absences"`
+	Status         string    `json:"status"`
+	Approved       bool      `json:"approved"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// AttendanceRecord é o lançamento de faltas de uma matrícula em um dia de aula
+type AttendanceRecord struct {
+	ID             int       `json:"id"`
+	RegistrationID int       `json:"registration_id"`
+	SessionID      *int      `json:"session_id"`
+	ClassDate      time.Time `json:"class_date"`
+	HoursAbsent    int       `json:"hours_absent"`
+	// TeacherID é o professor responsável pela aula na data (o substituto, se houver)
+	TeacherID *int `json:"teacher_id"`
+}
@@ -0,0 +1,90 @@
+package models
+
+// DisciplineDemand é a linha do relatório de previsão de demanda
+type DisciplineDemand struct {
+	DisciplineID          int     `json:"discipline_id"`
+	DisciplineCode        string  `json:"discipline_code"`
+	DisciplineName        string  `json:"discipline_name"`
+	Eligible              int     `json:"eligible"`
+	AwaitingPrerequisites int     `json:"awaiting_prerequisites"`
+	OnTrack               int     `json:"on_track"`
+	Overdue               int     `json:"overdue"`
+	ExpectedRetakes       int     `json:"expected_retakes"`
+	FailureRate           float64 `json:"failure_rate"`
+	Estimated             int     `json:"estimated"`
+	Sections              int     `json:"sections"`
+}
+
+// WorkloadOffer é uma oferta atribuída ao professor no relatório de carga docente
+type WorkloadOffer struct {
+	OfferID        int    `json:"offer_id"`
+	TeacherID      *int   `json:"-"`
+	DisciplineCode string `json:"discipline_code"`
+	DisciplineName string `json:"discipline_name"`
+	Schedule       string `json:"schedule"`
+	// Role é o papel do professor na oferta: lead, assistant ou substitute (substituição no período)
+	Role string `json:"role,omitempty"`
+	// HourShare é a fração (%) da carga da oferta atribuída ao professor
+	HourShare     float64 `json:"hour_share"`
+	WeeklyHours   float64 `json:"weekly_hours"`
+	SemesterHours int     `json:"semester_hours"`
+	// Estimated indica que o horário não pôde ser interpretado e as horas semanais foram estimadas
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// TeacherWorkload é a carga de um professor no semestre frente às horas do contrato
+type TeacherWorkload struct {
+	TeacherID      int             `json:"teacher_id"`
+	TeacherName    string          `json:"teacher_name"`
+	Active         bool            `json:"active"`
+	DepartmentID   *int            `json:"department_id"`
+	DepartmentName string          `json:"department_name"`
+	ContractHours  *int            `json:"contract_hours"`
+	ContractRegime *string         `json:"contract_regime"`
+	AcademicTitle  *string         `json:"academic_title"`
+	OfferCount     int             `json:"offer_count"`
+	WeeklyHours    float64         `json:"weekly_hours"`
+	SemesterHours  int             `json:"semester_hours"`
+	Utilization    *float64        `json:"utilization,omitempty"`
+	Status         string          `json:"status"`
+	Offers         []WorkloadOffer `json:"offers"`
+}
+
+// DepartmentWorkload soma a carga dos professores de um departamento
+type DepartmentWorkload struct {
+	DepartmentID   *int    `json:"department_id"`
+	DepartmentName string  `json:"department_name"`
+	Teachers       int     `json:"teachers"`
+	OfferCount     int     `json:"offer_count"`
+	WeeklyHours    float64 `json:"weekly_hours"`
+	SemesterHours  int     `json:"semester_hours"`
+	ContractHours  int     `json:"contract_hours"`
+	Overloaded     int     `json:"overloaded"`
+	Underallocated int     `json:"underallocated"`
+}
+
+// WorkloadReport é o relatório de carga docente de um semestre
+type WorkloadReport struct {
+	SemesterID       int                  `json:"semester_id"`
+	Semester         string               `json:"semester"`
+	Teachers         []TeacherWorkload    `json:"teachers"`
+	Departments      []DepartmentWorkload `json:"departments"`
+	UnassignedOffers []WorkloadOffer      `json:"unassigned_offers"`
+}
+
+// FacultyProfile resume a titulação e o regime de trabalho do corpo docente ativo,
+// nos indicadores usados pelos relatórios regulatórios
+type FacultyProfile struct {
+	DepartmentID *int           `json:"department_id,omitempty"`
+	Teachers     int            `json:"teachers"`
+	ByTitle      map[string]int `json:"by_title"`
+	ByRegime     map[string]int `json:"by_regime"`
+	// Percentuais de mestres e doutores, de doutores e de professores em tempo integral ou parcial
+	MastersAndDoctorsPct float64 `json:"masters_and_doctors_pct"`
+	DoctorsPct           float64 `json:"doctors_pct"`
+	FullOrPartTimePct    float64 `json:"full_or_part_time_pct"`
+	FullTimePct          float64 `json:"full_time_pct"`
+	// Professores sem titulação ou sem regime cadastrados
+	MissingTitle  int `json:"missing_title"`
+	MissingRegime int `json:"missing_regime"`
+}
@@ -1,13 +1,109 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// Tipos de período letivo. Os semestres regulares continuam com o rótulo "Ano.Período" (2025.1);
+// os demais tipos têm rótulo próprio (2025.V, 2025.I, 2025.T1, 2025.M1).
+const (
+	PeriodSemester = "semester"
+	PeriodSummer   = "summer"
+	PeriodWinter   = "winter"
+	PeriodQuarter  = "quarter"
+	PeriodModule   = "module"
+)
+
+// MaxModules é o número máximo de módulos por ano nos cursos modulares
+const MaxModules = 12
 
 type AcademicSemester struct {
-	ID     int `json:"id"`
-	Year   int `json:"year"`
-	Period int `json:"period"`
+	ID        int        `json:"id"`
+	Year      int        `json:"year"`
+	Kind      string     `json:"kind"`
+	Period    int        `json:"period"`
+	Label     string     `json:"label"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
 }
 
 func (s *AcademicSemester) String() string {
+	switch s.Kind {
+	case PeriodSummer:
+		return fmt.Sprintf("%d.V", s.Year)
+	case PeriodWinter:
+		return fmt.Sprintf("%d.I", s.Year)
+	case PeriodQuarter:
+		return fmt.Sprintf("%d.T%d", s.Year, s.Period)
+	case PeriodModule:
+		return fmt.Sprintf("%d.M%d", s.Year, s.Period)
+	}
 	return fmt.Sprintf("%d.%d", s.Year, s.Period)
 }
+
+// IsRegular indica se o período é um semestre regular (1 ou 2)
+func (s *AcademicSemester) IsRegular() bool {
+	return s.Kind == "" || s.Kind == PeriodSemester
+}
+
+// lastPeriod é o maior período possível no ano para o tipo; 0 indica tipo inválido
+func lastPeriod(kind string) int {
+	switch kind {
+	case PeriodSemester, "":
+		return 2
+	case PeriodSummer, PeriodWinter:
+		return 1
+	case PeriodQuarter:
+		return 4
+	case PeriodModule:
+		return MaxModules
+	}
+	return 0
+}
+
+// Validate confere o tipo e o período. Tipo vazio é tratado como semestre regular.
+func (s *AcademicSemester) Validate() error {
+	if s.Kind == "" {
+		s.Kind = PeriodSemester
+	}
+	last := lastPeriod(s.Kind)
+	if last == 0 {
+		return fmt.Errorf("tipo de período inválido: %s", s.Kind)
+	}
+	if s.Period < 1 || s.Period > last {
+		if last == 1 {
+			return fmt.Errorf("período inválido para o tipo %s: deve ser 1", s.Kind)
+		}
+		return fmt.Errorf("período inválido para o tipo %s: deve ser entre 1 e %d", s.Kind, last)
+	}
+	return nil
+}
+
+// Next retorna o ano e o período seguintes do mesmo tipo
+func (s *AcademicSemester) Next() (year, period int) {
+	if s.Period < lastPeriod(s.Kind) {
+		return s.Year, s.Period + 1
+	}
+	return s.Year + 1, 1
+}
+
+// RolloverItem é uma oferta do semestre de origem e o resultado da cópia
+type RolloverItem struct {
+	SourceOfferID  int    `json:"source_offer_id"`
+	NewOfferID     *int   `json:"new_offer_id,omitempty"`
+	DisciplineName string `json:"discipline_name"`
+	TeacherName    string `json:"teacher_name"`
+	Reason         string `json:"reason,omitempty"`
+	// Warnings são os avisos da alocação dos professores copiados (departamento, preferência, choque)
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RolloverResult é o resumo da criação de um semestre a partir das ofertas de um semestre anterior
+type RolloverResult struct {
+	SemesterID       int            `json:"semester_id"`
+	Semester         string         `json:"semester"`
+	SourceSemesterID int            `json:"source_semester_id"`
+	Copied           []RolloverItem `json:"copied"`
+	Skipped          []RolloverItem `json:"skipped"`
+}
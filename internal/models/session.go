@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ClassSession é uma aula gerada a partir do horário da oferta e do calendário acadêmico.
+// Topic e Notes compõem o diário de classe.
+type ClassSession struct {
+	ID        int       `json:"id"`
+	OfferID   int       `json:"offer_id"`
+	Date      time.Time `json:"date"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+	Topic     string    `json:"topic"`
+	Notes     string    `json:"notes"`
+	Absentees int       `json:"absentees"`
+	// Professor responsável pela aula na data (o substituto, durante uma substituição)
+	TeacherID   *int   `json:"teacher_id"`
+	TeacherName string `json:"teacher_name"`
+}
+
+// SessionAttendance é a chamada de uma aula: faltas de cada matrícula
+type SessionAttendance struct {
+	RegistrationID int `json:"registration_id"`
+	HoursAbsent    int `json:"hours_absent"`
+}
@@ -15,8 +15,10 @@ type Student struct {
 	CPF                string    `json:"cpf"`
 	RegistrationNumber string    `json:"registration_number"`
 	Active             bool      `json:"active"`
+	Status             string    `json:"status"`
 	CourseID           int       `json:"course_id"`
 	CourseName         string    `json:"course_name"`
+	EntrySemesterID    *int      `json:"entry_semester_id"`
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 }
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Situações acadêmicas do aluno (enum student_status)
+const (
+	StudentEnrolled       = "enrolled"
+	StudentLeaveOfAbsence = "leave_of_absence"
+	StudentTransferred    = "transferred"
+	StudentGraduated      = "graduated"
+	StudentDroppedOut     = "dropped_out"
+	StudentExpelled       = "expelled"
+)
+
+// studentTransitions define para quais situações o aluno pode passar a partir da situação atual.
+// Formado e expulso são situações finais.
+var studentTransitions = map[string][]string{
+	StudentEnrolled:       {StudentLeaveOfAbsence, StudentTransferred, StudentGraduated, StudentDroppedOut, StudentExpelled},
+	StudentLeaveOfAbsence: {StudentEnrolled, StudentTransferred, StudentDroppedOut, StudentExpelled},
+	StudentDroppedOut:     {StudentEnrolled},
+	StudentTransferred:    {StudentEnrolled},
+}
+
+// ValidStudentStatus indica se o valor é uma situação conhecida
+func ValidStudentStatus(status string) bool {
+	switch status {
+	case StudentEnrolled, StudentLeaveOfAbsence, StudentTransferred,
+		StudentGraduated, StudentDroppedOut, StudentExpelled:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica se o aluno pode passar da situação from para to
+func CanTransition(from, to string) bool {
+	for _, s := range studentTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StudentIsActive indica se a situação mantém o vínculo ativo com a instituição
+func StudentIsActive(status string) bool {
+	return status == StudentEnrolled || status == StudentLeaveOfAbsence
+}
+
+// StudentStatusChange é uma transição registrada no histórico do aluno
+type StudentStatusChange struct {
+	ID            int       `json:"id"`
+	StudentID     int       `json:"student_id"`
+	FromStatus    *string   `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	Reason        string    `json:"reason"`
+	ChangedBy     string    `json:"changed_by"`
+	EffectiveDate time.Time `json:"effective_date"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -1,9 +1,31 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
+// Titulação acadêmica do professor (enum academic_title)
+const (
+	TitleGraduate   = "graduate"
+	TitleSpecialist = "specialist"
+	TitleMaster     = "master"
+	TitleDoctor     = "doctor"
+)
+
+// Regime de trabalho do professor (enum contract_regime)
+const (
+	RegimeHourly   = "hourly"
+	RegimePartTime = "part_time"
+	RegimeFullTime = "full_time"
+)
+
+// Limites de horas semanais por regime: tempo integral é de 40h e tempo parcial de 12h a 39h
+const (
+	FullTimeHours    = 40
+	PartTimeMinHours = 12
+)
+
 type Teacher struct {
 	ID             int       `json:"id"`
 	Name           string    `json:"name"`
@@ -14,6 +36,83 @@ type Teacher struct {
 	DepartmentID   int       `json:"department_id"`
 	DepartmentName string    `json:"department_name"`
 	DateContract   time.Time `json:"date_contract"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ContractHours  *int      `json:"contract_hours"`
+	ContractRegime *string   `json:"contract_regime"`
+	AcademicTitle  *string   `json:"academic_title"`
+	ExpertiseAreas []string  `json:"expertise_areas"`
+	// PreferredSchedule usa o mesmo formato do horário das ofertas ("Seg/Qua 08h-12h")
+	PreferredSchedule string    `json:"preferred_schedule"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ValidateProfile confere a titulação, o regime e as horas do contrato
+func (t *Teacher) ValidateProfile() error {
+	if t.AcademicTitle != nil {
+		switch *t.AcademicTitle {
+		case TitleGraduate, TitleSpecialist, TitleMaster, TitleDoctor:
+		default:
+			return fmt.Errorf("titulação inválida: %s", *t.AcademicTitle)
+		}
+	}
+
+	if t.ContractHours != nil && (*t.ContractHours < 1 || *t.ContractHours > 60) {
+		return fmt.Errorf("carga horária do contrato deve ser entre 1 e 60 horas semanais")
+	}
+
+	if t.ContractRegime == nil {
+		return nil
+	}
+	switch *t.ContractRegime {
+	case RegimeHourly:
+	case RegimePartTime:
+		if t.ContractHours == nil || *t.ContractHours < PartTimeMinHours || *t.ContractHours >= FullTimeHours {
+			return fmt.Errorf("tempo parcial exige de %d a %d horas semanais", PartTimeMinHours, FullTimeHours-1)
+		}
+	case RegimeFullTime:
+		if t.ContractHours == nil || *t.ContractHours != FullTimeHours {
+			return fmt.Errorf("tempo integral exige %d horas semanais", FullTimeHours)
+		}
+	default:
+		return fmt.Errorf("regime de trabalho inválido: %s", *t.ContractRegime)
+	}
+	return nil
+}
+
+// TeacherContract é um período do histórico contratual do professor.
+// Um novo registro é aberto sempre que o regime ou as horas do contrato mudam.
+type TeacherContract struct {
+	ID            int        `json:"id"`
+	TeacherID     int        `json:"teacher_id"`
+	Regime        *string    `json:"regime"`
+	ContractHours *int       `json:"contract_hours"`
+	StartDate     time.Time  `json:"start_date"`
+	EndDate       *time.Time `json:"end_date"`
+	ChangedBy     string     `json:"changed_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TeacherDiscipline é uma disciplina que o professor está habilitado a lecionar
+type TeacherDiscipline struct {
+	TeacherID      int    `json:"teacher_id"`
+	DisciplineID   int    `json:"discipline_id"`
+	DisciplineName string `json:"discipline_name"`
+	DisciplineCode string `json:"discipline_code"`
+}
+
+// TeacherCandidate é um professor habilitado para a disciplina, avaliado para um horário
+type TeacherCandidate struct {
+	TeacherID         int     `json:"teacher_id"`
+	TeacherName       string  `json:"teacher_name"`
+	DepartmentID      *int    `json:"department_id"`
+	DepartmentName    string  `json:"department_name"`
+	AcademicTitle     *string `json:"academic_title"`
+	ContractHours     *int    `json:"contract_hours"`
+	PreferredSchedule string  `json:"preferred_schedule"`
+	SameDepartment    bool    `json:"same_department"`
+	// PreferredSlot indica que o horário está dentro dos horários de preferência do professor
+	PreferredSlot bool `json:"preferred_slot"`
+	// Conflicts são as ofertas do professor no semestre com choque de horário
+	Conflicts   []string `json:"conflicts"`
+	WeeklyHours float64  `json:"weekly_hours"`
 }
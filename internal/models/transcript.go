@@ -0,0 +1,39 @@
+package models
+
+// Origem das disciplinas no histórico escolar
+const (
+	OriginTaken       = "taken"
+	OriginTransferred = "transferred"
+)
+
+// TranscriptEntry é uma linha do histórico escolar
+type TranscriptEntry struct {
+	RegistrationID *int     `json:"registration_id"`
+	Semester       string   `json:"semester"`
+	DisciplineID   int      `json:"discipline_id"`
+	DisciplineCode string   `json:"discipline_code"`
+	DisciplineName string   `json:"discipline_name"`
+	Credits        int      `json:"credits"`
+	WorkloadHours  int      `json:"workload_hours"`
+	FinalGrade     *float64 `json:"final_grade"`
+	Frequency      *float64 `json:"frequency"`
+	Status         string   `json:"status"`
+	Origin         string   `json:"origin"`
+	// Institution é a instituição de origem das disciplinas aproveitadas
+	Institution *string `json:"institution"`
+	// Classification indica se a disciplina conta para a matriz do curso atual ou como optativa livre
+	Classification string `json:"classification"`
+}
+
+// Transcript é o histórico escolar completo do aluno, incluindo as disciplinas cursadas antes de transferências
+type Transcript struct {
+	StudentID          int               `json:"student_id"`
+	StudentName        string            `json:"student_name"`
+	RegistrationNumber string            `json:"registration_number"`
+	CourseID           int               `json:"course_id"`
+	CourseName         string            `json:"course_name"`
+	Entries            []TranscriptEntry `json:"entries"`
+	Transfers          []CourseTransfer  `json:"transfers"`
+	EarnedCredits      int               `json:"earned_credits"`
+	EarnedWorkload     int               `json:"earned_workload_hours"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Classificação das disciplinas aproveitadas em relação à matriz do curso
+const (
+	CreditCurriculum   = "curriculum"
+	CreditFreeElective = "free_elective"
+)
+
+// CourseTransfer registra a troca de curso de um aluno
+type CourseTransfer struct {
+	ID             int              `json:"id"`
+	StudentID      int              `json:"student_id"`
+	FromCourseID   *int             `json:"from_course_id"`
+	FromCourseName *string          `json:"from_course_name"`
+	ToCourseID     int              `json:"to_course_id"`
+	ToCourseName   string           `json:"to_course_name"`
+	Reason         string           `json:"reason"`
+	TransferredBy  string           `json:"transferred_by"`
+	TransferredAt  time.Time        `json:"transferred_at"`
+	Credits        []TransferCredit `json:"credits"`
+}
+
+// TransferCredit é uma disciplina aprovada no curso de origem e como ela conta no novo curso
+type TransferCredit struct {
+	RegistrationID int    `json:"registration_id"`
+	DisciplineID   int    `json:"discipline_id"`
+	DisciplineName string `json:"discipline_name"`
+	Credits        int    `json:"credits"`
+	Classification string `json:"classification"`
+}
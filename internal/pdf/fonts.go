@@ -0,0 +1,138 @@
+package pdf
+
+// Font é uma das fontes padrão do PDF (Type 1, sem embutir), com codificação WinAnsi
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var baseFonts = []string{Helvetica: "Helvetica", HelveticaBold: "Helvetica-Bold"}
+
+// Larguras dos caracteres ASCII 32 a 126, em milésimos do tamanho da fonte (métricas AFM)
+var asciiWidths = [][]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// As letras acentuadas de 0xC0 a 0xFF têm a largura da letra base, exceto o "i" acentuado,
+// mais largo que o "i" com pingo
+const latinBase = "AAAAAAECEEEEIIIIDNOOOOO*OUUUUYPsaaaaaaeceeeeiiiidnooooo/ouuuuypy"
+
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converte o texto para WinAnsi; caracteres sem representação viram "?"
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func charWidth(f Font, c byte) int {
+	switch {
+	case c >= 32 && c <= 126:
+		return asciiWidths[f][c-32]
+	case c == 0xC6:
+		return 1000
+	case c == 0xE6:
+		return 889
+	case c >= 0xC0:
+		switch base := latinBase[c-0xC0]; base {
+		case '*', '/':
+			return 584
+		case 's':
+			return 611
+		case 'i':
+			return 278
+		default:
+			return asciiWidths[f][base-32]
+		}
+	case c == 0x96:
+		return 556
+	case c == 0x97:
+		return 1000
+	default:
+		return 556
+	}
+}
+
+// TextWidth é a largura do texto em pontos
+func TextWidth(f Font, size float64, s string) float64 {
+	total := 0
+	for _, c := range encode(s) {
+		total += charWidth(f, c)
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap quebra o texto em linhas de até width pontos, nos espaços
+func Wrap(f Font, size float64, s string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range splitWords(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && TextWidth(f, size, candidate) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func splitWords(s string) []string {
+	var words []string
+	start := -1
+	for i, r := range s {
+		if r == ' ' || r == '\n' || r == '\t' {
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
@@ -0,0 +1,210 @@
+// Package pdf gera documentos PDF simples (texto com as fontes padrão, linhas e retângulos) em
+// páginas A4. As coordenadas são em pontos, a partir do canto superior esquerdo da página; o y
+// do texto é a linha de base.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Tamanho da página A4 em pontos
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Document struct {
+	Title     string
+	Author    string
+	CreatedAt time.Time
+	pages     []*Page
+}
+
+type Page struct {
+	content bytes.Buffer
+}
+
+func New(title, author string, createdAt time.Time) *Document {
+	return &Document{Title: title, Author: author, CreatedAt: createdAt}
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text escreve o texto com a linha de base em y
+func (p *Page) Text(x, y float64, f Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td ", int(f)+1, num(size), num(x), num(PageHeight-y))
+	p.content.WriteString(literal(encode(s)))
+	p.content.WriteString(" Tj ET\n")
+}
+
+// TextRight escreve o texto alinhado à direita em x
+func (p *Page) TextRight(x, y float64, f Font, size float64, s string) {
+	p.Text(x-TextWidth(f, size, s), y, f, size, s)
+}
+
+// TextCenter escreve o texto centralizado em x
+func (p *Page) TextCenter(x, y float64, f Font, size float64, s string) {
+	p.Text(x-TextWidth(f, size, s)/2, y, f, size, s)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Rect preenche o retângulo com o tom de cinza (0 = preto, 1 = branco)
+func (p *Page) Rect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f 0 g\n",
+		num(gray), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Write grava o documento. A saída depende apenas do conteúdo e de CreatedAt.
+func (d *Document) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+
+	startObject := func() int {
+		offsets = append(offsets, buf.Len())
+		n := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		return n
+	}
+	endObject := func() {
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos 1 a 5: catálogo, árvore de páginas, fontes e informações; as páginas vêm depois
+	pageObjects := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageObjects[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+
+	startObject()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	endObject()
+
+	startObject()
+	fmt.Fprintf(&buf, "<< /Type /Pages /Count %d /Kids [", len(d.pages))
+	for i, ref := range pageObjects {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		buf.WriteString(ref)
+	}
+	buf.WriteString("] >>\n")
+	endObject()
+
+	for _, name := range baseFonts {
+		startObject()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", name)
+		endObject()
+	}
+
+	startObject()
+	fmt.Fprintf(&buf, "<< /Title %s /Author %s /Producer (UniSystem) /CreationDate %s >>\n",
+		literal(encode(d.Title)), literal(encode(d.Author)), literal([]byte(pdfDate(d.CreatedAt))))
+	endObject()
+
+	for i, p := range d.pages {
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		zw.Write(p.content.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		startObject()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>\n",
+			num(PageWidth), num(PageHeight), 7+2*i)
+		endObject()
+
+		startObject()
+		fmt.Fprintf(&buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", stream.Len())
+		buf.Write(stream.Bytes())
+		buf.WriteString("\nendstream\n")
+		endObject()
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+
+	// O identificador do arquivo é derivado do próprio conteúdo para manter a saída determinística
+	id := sha256.Sum256(buf.Bytes())
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, id[:16], id[:16], xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Bytes é o documento gravado em memória
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func literal(b []byte) string {
+	var out bytes.Buffer
+	out.WriteByte('(')
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	out.WriteByte(')')
+	return out.String()
+}
+
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
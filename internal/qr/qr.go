@@ -0,0 +1,204 @@
+// Package qr gera QR Codes (ISO/IEC 18004) no modo byte com correção de erros nível M, versões 1
+// a 10 (até 213 bytes), o suficiente para os links de verificação dos documentos.
+package qr
+
+import "fmt"
+
+// Code é a matriz de módulos do símbolo, sem a zona de silêncio
+type Code struct {
+	Size    int
+	modules [][]bool
+}
+
+// Dark indica se o módulo da coluna x e linha y é escuro
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Estrutura dos blocos no nível M: códigos de correção por bloco e blocos de cada grupo
+type blockSpec struct {
+	ecPerBlock int
+	groups     [][2]int // {quantidade de blocos, palavras de dados por bloco}
+}
+
+var specsM = []blockSpec{
+	1:  {10, [][2]int{{1, 16}}},
+	2:  {16, [][2]int{{1, 28}}},
+	3:  {26, [][2]int{{1, 44}}},
+	4:  {18, [][2]int{{2, 32}}},
+	5:  {24, [][2]int{{2, 43}}},
+	6:  {16, [][2]int{{4, 27}}},
+	7:  {18, [][2]int{{4, 31}}},
+	8:  {22, [][2]int{{2, 38}, {2, 39}}},
+	9:  {22, [][2]int{{3, 36}, {2, 37}}},
+	10: {26, [][2]int{{4, 43}, {1, 44}}},
+}
+
+var alignmentCenters = [][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+	7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+func (s blockSpec) dataCodewords() int {
+	n := 0
+	for _, g := range s.groups {
+		n += g[0] * g[1]
+	}
+	return n
+}
+
+// Encode gera o símbolo da menor versão que comporta o texto
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v < len(specsM); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= specsM[v].dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("texto longo demais para o QR Code: %d bytes", len(data))
+	}
+
+	s := newSymbol(version)
+	s.drawFunctionPatterns()
+	s.drawCodewords(interleave(version, encodeData(version, data)))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		s.applyMask(mask)
+		s.drawFormatBits(mask)
+		if p := s.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		s.applyMask(mask) // desfaz (XOR)
+	}
+	s.applyMask(best)
+	s.drawFormatBits(best)
+
+	return &Code{Size: s.size, modules: s.modules}, nil
+}
+
+// encodeData monta as palavras de dados: modo byte, contagem, dados, terminador e preenchimento
+func encodeData(version int, data []byte) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := specsM[version].dataCodewords() * 8
+	terminator := min(4, capacity-len(bits))
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// interleave divide os dados em blocos, calcula a correção de cada um e intercala as palavras
+func interleave(version int, data []byte) []byte {
+	spec := specsM[version]
+	divisor := rsDivisor(spec.ecPerBlock)
+
+	var blocks, eccs [][]byte
+	k := 0
+	for _, g := range spec.groups {
+		for range g[0] {
+			block := data[k : k+g[1]]
+			k += g[1]
+			blocks = append(blocks, block)
+			eccs = append(eccs, rsRemainder(block, divisor))
+		}
+	}
+
+	var result []byte
+	for i := 0; ; i++ {
+		added := false
+		for _, b := range blocks {
+			if i < len(b) {
+				result = append(result, b[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	for i := range spec.ecPerBlock {
+		for _, e := range eccs {
+			result = append(result, e[i])
+		}
+	}
+	return result
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// Reed-Solomon sobre GF(256) com o polinômio primitivo 0x11D
+
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
@@ -0,0 +1,243 @@
+package qr
+
+// symbol é a matriz em construção; function marca os módulos dos padrões fixos, que não recebem
+// dados nem máscara
+type symbol struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+func newSymbol(version int) *symbol {
+	size := version*4 + 17
+	s := &symbol{version: version, size: size}
+	s.modules = make([][]bool, size)
+	s.function = make([][]bool, size)
+	for i := range size {
+		s.modules[i] = make([]bool, size)
+		s.function[i] = make([]bool, size)
+	}
+	return s
+}
+
+func (s *symbol) set(x, y int, dark bool) {
+	s.modules[y][x] = dark
+	s.function[y][x] = true
+}
+
+func (s *symbol) drawFunctionPatterns() {
+	// Padrões de sincronismo
+	for i := range s.size {
+		s.set(6, i, i%2 == 0)
+		s.set(i, 6, i%2 == 0)
+	}
+
+	// Padrões de posição (com o separador em volta)
+	s.drawFinder(3, 3)
+	s.drawFinder(s.size-4, 3)
+	s.drawFinder(3, s.size-4)
+
+	// Padrões de alinhamento, exceto onde coincidem com os de posição
+	centers := alignmentCenters[s.version]
+	last := len(centers) - 1
+	for i, x := range centers {
+		for j, y := range centers {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					s.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserva a área do formato; os bits reais são gravados depois da escolha da máscara
+	s.drawFormatBits(0)
+
+	if s.version >= 7 {
+		rem := s.version
+		for range 12 {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := s.version<<12 | rem
+		for i := range 18 {
+			dark := bits>>i&1 == 1
+			a, b := s.size-11+i%3, i/3
+			s.set(a, b, dark)
+			s.set(b, a, dark)
+		}
+	}
+}
+
+func (s *symbol) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= s.size || y >= s.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			s.set(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormatBits grava o nível de correção (M = 00) e a máscara, com o BCH(15,5), nas duas cópias
+func (s *symbol) drawFormatBits(mask int) {
+	data := mask // bits do nível M são 00
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		s.set(8, i, bit(i))
+	}
+	s.set(8, 7, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		s.set(s.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(8, s.size-15+i, bit(i))
+	}
+	s.set(8, s.size-8, true) // módulo escuro fixo
+}
+
+// drawCodewords distribui os bits em zigue-zague, de baixo para cima, em colunas duplas da direita
+func (s *symbol) drawCodewords(data []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range s.size {
+			for j := range 2 {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = s.size - 1 - vert
+				}
+				if !s.function[y][x] && i < len(data)*8 {
+					s.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (s *symbol) applyMask(mask int) {
+	for y := range s.size {
+		for x := range s.size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !s.function[y][x] {
+				s.modules[y][x] = !s.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty pontua a matriz pelas quatro regras da norma; a máscara de menor pontuação é usada
+func (s *symbol) penalty() int {
+	total := 0
+	n := s.size
+	at := func(x, y int, transposed bool) bool {
+		if transposed {
+			return s.modules[x][y]
+		}
+		return s.modules[y][x]
+	}
+
+	for _, transposed := range []bool{false, true} {
+		for y := range n {
+			// Regra 1: sequências de cinco ou mais módulos da mesma cor
+			run := 1
+			for x := 1; x < n; x++ {
+				if at(x, y, transposed) == at(x-1, y, transposed) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					total += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				total += run - 2
+			}
+
+			// Regra 3: padrões parecidos com os de posição (1:1:3:1:1 com quatro claros ao lado)
+			for x := 0; x+10 < n; x++ {
+				pattern := 0
+				for k := range 11 {
+					pattern <<= 1
+					if at(x+k, y, transposed) {
+						pattern |= 1
+					}
+				}
+				if pattern == 0b10111010000 || pattern == 0b00001011101 {
+					total += 40
+				}
+			}
+		}
+	}
+
+	// Regra 2: blocos 2x2 da mesma cor
+	dark := 0
+	for y := range n {
+		for x := range n {
+			if s.modules[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				c := s.modules[y][x]
+				if c == s.modules[y][x+1] && c == s.modules[y+1][x] && c == s.modules[y+1][x+1] {
+					total += 3
+				}
+			}
+		}
+	}
+
+	// Regra 4: proporção de módulos escuros longe de 50%
+	cells := n * n
+	k := (abs(dark*20-cells*10)+cells-1)/cells - 1
+	total += k * 10
+	return total
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
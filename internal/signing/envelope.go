@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Envelope é um documento JSON assinado. A assinatura cobre Document na forma compacta (sem
+// espaços), então o arquivo pode ser reformatado sem perder a validade.
+type Envelope struct {
+	Document  json.RawMessage `json:"document"`
+	Signature Signature       `json:"signature"`
+}
+
+// Document é o conteúdo assinado: o tipo e a data de emissão ficam dentro da assinatura, para que
+// os dados não possam ser apresentados como outro documento
+type Document struct {
+	Kind     string          `json:"kind"`
+	IssuedAt time.Time       `json:"issued_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// SignJSON assina data como um documento do tipo kind
+func (k *Keyring) SignJSON(kind string, issuedAt time.Time, data any) (*Envelope, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := json.Marshal(Document{Kind: kind, IssuedAt: issuedAt, Data: raw})
+	if err != nil {
+		return nil, err
+	}
+	sig, err := k.Sign(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{Document: doc, Signature: sig}, nil
+}
+
+// VerifyJSON confere um documento JSON assinado e retorna o conteúdo
+func (k *Keyring) VerifyJSON(b []byte) (*Envelope, *Document, error) {
+	var env Envelope
+	if err := json.Unmarshal(b, &env); err != nil || len(env.Document) == 0 {
+		return nil, nil, fmt.Errorf("documento assinado mal formado")
+	}
+
+	var doc Document
+	if err := json.Unmarshal(env.Document, &doc); err != nil {
+		return nil, nil, fmt.Errorf("documento assinado mal formado")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, env.Document); err != nil {
+		return nil, nil, fmt.Errorf("documento assinado mal formado")
+	}
+	if err := k.Verify(compact.Bytes(), env.Signature); err != nil {
+		return &env, &doc, err
+	}
+	return &env, &doc, nil
+}
@@ -0,0 +1,368 @@
+// Package signing assina os documentos emitidos com o par de chaves Ed25519 da instituição,
+// guardado em disco, e confere as assinaturas com as chaves públicas ativas.
+//
+// Cada chave fica no diretório de chaves como {id}.key (privada, PEM PKCS#8) e {id}.pub (pública,
+// PEM PKIX), além do certificado autoassinado {id}.crt, que acompanha os documentos XML assinados
+// (diplomas) para identificar a instituição. Todas as chaves públicas do diretório são aceitas na
+// verificação; apenas uma chave privada assina. Para trocar a chave, gera-se uma nova e as públicas
+// antigas continuam no diretório, de modo que os documentos já emitidos seguem verificáveis.
+// Remover o .pub de uma chave revoga as assinaturas feitas com ela.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Algorithm é o único algoritmo de assinatura usado
+const Algorithm = "Ed25519"
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,40}$`)
+
+// Signature é a assinatura destacada de um documento. Value é a assinatura em base64.
+type Signature struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// PublicKey é uma chave pública ativa, no formato publicado para a verificação fora do sistema
+type PublicKey struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+	// Signing indica a chave que assina os documentos emitidos agora
+	Signing bool `json:"signing"`
+}
+
+// Keyring reúne as chaves públicas ativas e, no servidor, a chave privada que assina
+type Keyring struct {
+	signingID string
+	private   ed25519.PrivateKey
+	public    map[string]ed25519.PublicKey
+	// certificate é o certificado da chave que assina, em DER; chaves geradas antes dos certificados
+	// não têm
+	certificate []byte
+}
+
+// Load carrega as chaves de path. Se path for um diretório, lê os arquivos .pub e .key; a chave que
+// assina é signingID ou, vazio, a de maior ID entre as que têm chave privada. Se path for um arquivo,
+// lê a lista de chaves públicas publicada em /verify/keys, só para verificação.
+func Load(path, signingID string) (*Keyring, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir as chaves de assinatura: %w", err)
+	}
+	if !info.IsDir() {
+		return loadPublished(path)
+	}
+
+	k := &Keyring{public: map[string]ed25519.PublicKey{}}
+
+	pubFiles, err := filepath.Glob(filepath.Join(path, "*.pub"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range pubFiles {
+		id := strings.TrimSuffix(filepath.Base(file), ".pub")
+		key, err := readPublicKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("chave pública %s: %w", id, err)
+		}
+		k.public[id] = key
+	}
+
+	keyFiles, err := filepath.Glob(filepath.Join(path, "*.key"))
+	if err != nil {
+		return nil, err
+	}
+	if signingID == "" {
+		for _, file := range keyFiles {
+			if id := strings.TrimSuffix(filepath.Base(file), ".key"); id > signingID {
+				signingID = id
+			}
+		}
+	}
+	if signingID == "" {
+		return k, nil
+	}
+
+	k.private, err = readPrivateKey(filepath.Join(path, signingID+".key"))
+	if err != nil {
+		return nil, fmt.Errorf("chave privada %s: %w", signingID, err)
+	}
+	public := k.private.Public().(ed25519.PublicKey)
+	if existing, ok := k.public[signingID]; ok && !existing.Equal(public) {
+		return nil, fmt.Errorf("as chaves %s.key e %s.pub não formam um par", signingID, signingID)
+	}
+	k.public[signingID] = public
+	k.signingID = signingID
+
+	k.certificate, err = readCertificate(filepath.Join(path, signingID+".crt"), public)
+	if err != nil {
+		return nil, fmt.Errorf("certificado %s: %w", signingID, err)
+	}
+	return k, nil
+}
+
+func loadPublished(path string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as chaves públicas: %w", err)
+	}
+	var list []PublicKey
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("arquivo de chaves públicas inválido: %w", err)
+	}
+
+	k := &Keyring{public: map[string]ed25519.PublicKey{}}
+	for _, p := range list {
+		if p.Algorithm != Algorithm {
+			continue
+		}
+		key, err := parsePublicKey([]byte(p.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("chave pública %s: %w", p.KeyID, err)
+		}
+		k.public[p.KeyID] = key
+	}
+	return k, nil
+}
+
+// Generate cria um novo par de chaves no diretório, com um certificado autoassinado em nome de
+// organization. O ID padrão é a data e hora da geração, de modo que a chave mais nova passa a
+// assinar quando SIGNING_KEY_ID não está definido.
+func Generate(dir, id, organization string) (string, error) {
+	if !keyIDPattern.MatchString(id) {
+		return "", fmt.Errorf("ID de chave inválido: use até 40 letras, números, '.', '_' ou '-'")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("erro ao criar o diretório de chaves: %w", err)
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar chave: %w", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: organization + " (" + id + ")", Organization: []string{organization}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, public, private)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar o certificado: %w", err)
+	}
+
+	// O_EXCL impede sobrescrever uma chave existente, o que invalidaria os documentos assinados com ela
+	keyPath := filepath.Join(dir, id+".key")
+	if err := writeNew(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		return "", err
+	}
+	pubPath := filepath.Join(dir, id+".pub")
+	if err := writeNew(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644); err != nil {
+		os.Remove(keyPath)
+		return "", err
+	}
+	if err := writeNew(filepath.Join(dir, id+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o644); err != nil {
+		os.Remove(keyPath)
+		os.Remove(pubPath)
+		return "", err
+	}
+	return id, nil
+}
+
+func writeNew(path string, content []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("a chave %s já existe", filepath.Base(path))
+		}
+		return fmt.Errorf("erro ao gravar a chave: %w", err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("erro ao gravar a chave: %w", err)
+	}
+	return f.Close()
+}
+
+func readPublicKey(path string) (ed25519.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePublicKey(b)
+}
+
+func parsePublicKey(b []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("PEM de chave pública não encontrado")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("a chave não é Ed25519")
+	}
+	return public, nil
+}
+
+// readCertificate lê o certificado da chave, se existir, e confere que ele é da mesma chave
+func readCertificate(path string, public ed25519.PublicKey) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("PEM de certificado não encontrado")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := cert.PublicKey.(ed25519.PublicKey); !ok || !key.Equal(public) {
+		return nil, fmt.Errorf("o certificado não corresponde à chave")
+	}
+	return block.Bytes, nil
+}
+
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("PEM de chave privada não encontrado")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("a chave não é Ed25519")
+	}
+	return private, nil
+}
+
+// CanSign indica se há chave privada carregada
+func (k *Keyring) CanSign() bool {
+	return k != nil && k.private != nil
+}
+
+// SigningKey é o ID da chave que assina
+func (k *Keyring) SigningKey() string {
+	if k == nil {
+		return ""
+	}
+	return k.signingID
+}
+
+// Certificate é o certificado da chave que assina, em DER, ou nil se ela não tiver certificado
+func (k *Keyring) Certificate() []byte {
+	if k == nil {
+		return nil
+	}
+	return k.certificate
+}
+
+// Key é a chave pública ativa com o ID, ou nil se ela for desconhecida ou revogada
+func (k *Keyring) Key(id string) ed25519.PublicKey {
+	if k == nil {
+		return nil
+	}
+	return k.public[id]
+}
+
+// Sign assina a mensagem com a chave ativa
+func (k *Keyring) Sign(message []byte) (Signature, error) {
+	if !k.CanSign() {
+		return Signature{}, fmt.Errorf("nenhuma chave de assinatura configurada")
+	}
+	return Signature{
+		KeyID:     k.signingID,
+		Algorithm: Algorithm,
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(k.private, message)),
+	}, nil
+}
+
+// Verify confere a assinatura da mensagem com a chave pública indicada nela
+func (k *Keyring) Verify(message []byte, sig Signature) error {
+	if sig.Algorithm != Algorithm {
+		return fmt.Errorf("algoritmo de assinatura não suportado: %s", sig.Algorithm)
+	}
+	var key ed25519.PublicKey
+	if k != nil {
+		key = k.public[sig.KeyID]
+	}
+	if key == nil {
+		return fmt.Errorf("chave de assinatura desconhecida ou revogada: %s", sig.KeyID)
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("assinatura mal formada")
+	}
+	if !ed25519.Verify(key, message, value) {
+		return fmt.Errorf("assinatura inválida")
+	}
+	return nil
+}
+
+// PublicKeys lista as chaves públicas ativas, ordenadas pelo ID
+func (k *Keyring) PublicKeys() []PublicKey {
+	list := []PublicKey{}
+	if k == nil {
+		return list
+	}
+	for id, key := range k.public {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			continue
+		}
+		list = append(list, PublicKey{
+			KeyID:     id,
+			Algorithm: Algorithm,
+			PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			Signing:   id == k.signingID,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].KeyID < list[j].KeyID })
+	return list
+}
@@ -19,14 +19,36 @@ CREATE TABLE departments (
 CREATE TABLE courses (
   id SERIAL PRIMARY KEY,
   name VARCHAR(120) UNIQUE NOT NULL,
+  -- Sigla usada na matrícula dos alunos ({COURSE} no formato de matrícula)
+  code VARCHAR(10) UNIQUE CHECK (code ~ '^[A-Z0-9]+$'),
   total_credits_required INT NOT NULL CHECK(total_credits_required > 0),
   duration_semesters INT NOT NULL CHECK(duration_semesters > 0),
+  -- Prazo máximo de integralização; NULL usa 1,5x a duração do curso
+  max_duration_semesters INT CHECK(max_duration_semesters >= duration_semesters),
+  -- Matrículas em disciplinas dependem de aprovação da coordenação
+  requires_enrollment_approval BOOLEAN DEFAULT FALSE NOT NULL,
+  min_credits_per_semester INT CHECK(min_credits_per_semester >= 0),
+  max_credits_per_semester INT CHECK(max_credits_per_semester >= min_credits_per_semester),
   created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
 -- =========================================================
 -- TABELA DE PROFESSORES
 -- =========================================================
+CREATE TYPE academic_title AS ENUM (
+  'graduate',
+  'specialist',
+  'master',
+  'doctor'
+);
+
+-- Horista, tempo parcial (12h a 39h) ou tempo integral/dedicação exclusiva (40h)
+CREATE TYPE contract_regime AS ENUM (
+  'hourly',
+  'part_time',
+  'full_time'
+);
+
 CREATE TABLE teachers (
   id SERIAL PRIMARY KEY,
   name VARCHAR(120) NOT NULL,
@@ -36,10 +58,115 @@ CREATE TABLE teachers (
   active BOOLEAN DEFAULT TRUE NOT NULL,
   department_id INT REFERENCES departments(id) ON DELETE RESTRICT,
   date_contract DATE NOT NULL CHECK (date_contract <= CURRENT_DATE),
+  -- Horas semanais do contrato (20h, 40h...); usado no relatório de carga docente
+  contract_hours SMALLINT CHECK (contract_hours BETWEEN 1 AND 60),
+  contract_regime contract_regime,
+  academic_title academic_title,
+  expertise_areas TEXT[] DEFAULT '{}' NOT NULL,
+  -- Horários de preferência, no mesmo formato de discipline_offers.schedule ("Seg/Qua 08h-12h")
+  preferred_schedule VARCHAR(200),
   created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
   updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
+-- Histórico contratual: o registro aberto (end_date NULL) é o contrato vigente
+CREATE TABLE teacher_contracts (
+  id SERIAL PRIMARY KEY,
+  teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+  regime contract_regime,
+  contract_hours SMALLINT,
+  start_date DATE NOT NULL,
+  end_date DATE,
+  changed_by VARCHAR(120) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CHECK (end_date >= start_date)
+);
+
+CREATE UNIQUE INDEX teacher_contracts_current ON teacher_contracts (teacher_id) WHERE end_date IS NULL;
+
+-- =========================================================
+-- CHEFIAS DE DEPARTAMENTO E COORDENAÇÕES DE CURSO
+-- =========================================================
+-- Mandatos com histórico: uma nova nomeação encerra a anterior na véspera do seu início
+CREATE TABLE department_heads (
+  id SERIAL PRIMARY KEY,
+  department_id INT NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+  teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+  start_date DATE NOT NULL,
+  end_date DATE,
+  assigned_by VARCHAR(120) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CHECK (end_date >= start_date)
+);
+
+CREATE UNIQUE INDEX department_heads_open ON department_heads (department_id) WHERE end_date IS NULL;
+
+CREATE TABLE course_coordinators (
+  id SERIAL PRIMARY KEY,
+  course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+  start_date DATE NOT NULL,
+  end_date DATE,
+  assigned_by VARCHAR(120) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CHECK (end_date >= start_date)
+);
+
+CREATE UNIQUE INDEX course_coordinators_open ON course_coordinators (course_id) WHERE end_date IS NULL;
+
+-- =========================================================
+-- TABELA DE SEMESTRES ACADÊMICOS
+-- =========================================================
+-- Além dos semestres regulares, os cursos podem ter períodos intensivos de verão e inverno
+-- e os programas de pós-graduação trimestres ou módulos
+CREATE TYPE period_kind AS ENUM (
+  'semester',
+  'summer',
+  'winter',
+  'quarter',
+  'module'
+);
+
+CREATE TABLE academic_semesters (
+  id SERIAL PRIMARY KEY,
+  year INT NOT NULL,
+  kind period_kind NOT NULL DEFAULT 'semester',
+  period SMALLINT NOT NULL CHECK (
+    (kind = 'semester' AND period IN (1,2)) OR
+    (kind IN ('summer','winter') AND period = 1) OR
+    (kind = 'quarter' AND period BETWEEN 1 AND 4) OR
+    (kind = 'module' AND period BETWEEN 1 AND 12)
+  ),
+  start_date DATE,
+  end_date DATE,
+  CHECK (end_date >= start_date),
+  UNIQUE (year, kind, period)
+);
+
+-- Rótulo exibido na interface: semestres regulares mantêm "Ano.Período" (2025.1)
+CREATE OR REPLACE FUNCTION semester_label(p_kind period_kind, p_year INT, p_period INT)
+RETURNS TEXT AS $$
+  SELECT CASE p_kind
+    WHEN 'summer'  THEN p_year || '.V'
+    WHEN 'winter'  THEN p_year || '.I'
+    WHEN 'quarter' THEN p_year || '.T' || p_period
+    WHEN 'module'  THEN p_year || '.M' || p_period
+    ELSE p_year || '.' || p_period
+  END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- =========================================================
+-- SITUAÇÃO ACADÊMICA DO ALUNO
+-- =========================================================
+CREATE TYPE student_status AS ENUM (
+  'enrolled',
+  'leave_of_absence',
+  'transferred',
+  'graduated',
+  'dropped_out',
+  'expelled'
+);
+
 -- =========================================================
 -- TABELA DE ALUNOS
 -- =========================================================
@@ -51,12 +178,37 @@ CREATE TABLE students (
   date_birth DATE NOT NULL CHECK (date_birth <= CURRENT_DATE),
   cpf CHAR(11) UNIQUE NOT NULL CHECK (cpf ~ '^[0-9]{11}$'),
   registration_number VARCHAR(30) UNIQUE NOT NULL,
+  -- active é derivado de status (matriculado ou trancado) e mantido pela aplicação
   active BOOLEAN DEFAULT TRUE NOT NULL,
+  status student_status DEFAULT 'enrolled' NOT NULL,
   course_id INT REFERENCES courses(id) ON DELETE RESTRICT,
+  -- Semestre de ingresso (preenchido na confirmação do processo seletivo)
+  entry_semester_id INT REFERENCES academic_semesters(id) ON DELETE SET NULL,
   created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
   updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
+-- Último número usado por escopo de matrícula (a matrícula sem a sequência, ex.: "20251#").
+-- O UPSERT nesta tabela bloqueia a linha do escopo e garante números distintos em inscrições simultâneas.
+CREATE TABLE registration_sequences (
+  scope VARCHAR(60) PRIMARY KEY,
+  last_value INT NOT NULL
+);
+
+-- =========================================================
+-- HISTÓRICO DE SITUAÇÃO DO ALUNO
+-- =========================================================
+CREATE TABLE student_status_history (
+  id SERIAL PRIMARY KEY,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  from_status student_status,
+  to_status student_status NOT NULL,
+  reason TEXT NOT NULL,
+  changed_by VARCHAR(120) NOT NULL,
+  effective_date DATE DEFAULT CURRENT_DATE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
 -- =========================================================
 -- TABELA DE DISCIPLINAS
 -- =========================================================
@@ -73,13 +225,53 @@ CREATE TABLE disciplines (
 );
 
 -- =========================================================
--- TABELA DE SEMESTRES ACADÊMICOS
+-- MATRIZ CURRICULAR DOS CURSOS
 -- =========================================================
-CREATE TABLE academic_semesters (
+CREATE TABLE course_disciplines (
+  course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  -- Semestre sugerido na matriz (1 = primeiro semestre do curso)
+  suggested_semester SMALLINT CHECK(suggested_semester > 0),
+  mandatory BOOLEAN DEFAULT TRUE NOT NULL,
+  PRIMARY KEY (course_id, discipline_id)
+);
+
+-- =========================================================
+-- PRÉ-REQUISITOS DAS DISCIPLINAS
+-- =========================================================
+CREATE TABLE discipline_prerequisites (
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  prerequisite_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  PRIMARY KEY (discipline_id, prerequisite_id),
+  CHECK (discipline_id <> prerequisite_id)
+);
+
+-- =========================================================
+-- DISCIPLINAS QUE O PROFESSOR ESTÁ HABILITADO A LECIONAR
+-- =========================================================
+CREATE TABLE teacher_disciplines (
+  teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  PRIMARY KEY (teacher_id, discipline_id)
+);
+
+-- =========================================================
+-- CALENDÁRIO ACADÊMICO (FERIADOS, RECESSOS E SEMANAS DE PROVA)
+-- =========================================================
+CREATE TYPE calendar_event_kind AS ENUM (
+  'holiday',
+  'recess',
+  'exam_week'
+);
+
+CREATE TABLE calendar_events (
   id SERIAL PRIMARY KEY,
-  year INT NOT NULL,
-  period SMALLINT NOT NULL CHECK(period IN (1,2)),
-  UNIQUE (year, period)
+  semester_id INT NOT NULL REFERENCES academic_semesters(id) ON DELETE CASCADE,
+  kind calendar_event_kind NOT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  description VARCHAR(120) NOT NULL,
+  CHECK (end_date >= start_date)
 );
 
 -- =========================================================
@@ -91,9 +283,52 @@ CREATE TABLE discipline_offers (
   semester_id INT REFERENCES academic_semesters(id) ON DELETE CASCADE,
   teacher_id INT REFERENCES teachers(id) ON DELETE RESTRICT,
   schedule VARCHAR(100) NOT NULL,
+  room VARCHAR(30),
+  -- Carga horária prevista pelo calendário (encontros em dias letivos); NULL usa disciplines.workload_hours
+  planned_hours INT CHECK(planned_hours >= 0),
+  -- Vagas da turma; NULL não limita
+  capacity INT CHECK(capacity > 0),
+  -- Fechamento do diário: grava a situação final das matrículas, que deixam de ser recalculadas
+  closed_at TIMESTAMPTZ,
+  closed_by VARCHAR(120),
   UNIQUE(discipline_id, semester_id)
 );
 
+-- =========================================================
+-- EQUIPE DOCENTE DA OFERTA E SUBSTITUIÇÕES
+-- =========================================================
+-- discipline_offers.teacher_id continua sendo o professor responsável (lead);
+-- offer_teachers guarda toda a equipe com a fração da carga de cada um
+CREATE TYPE offer_teacher_role AS ENUM (
+  'lead',
+  'assistant'
+);
+
+CREATE TABLE offer_teachers (
+  offer_id INT NOT NULL REFERENCES discipline_offers(id) ON DELETE CASCADE,
+  teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+  role offer_teacher_role NOT NULL DEFAULT 'assistant',
+  hour_share DECIMAL(5,2) NOT NULL DEFAULT 100 CHECK (hour_share > 0 AND hour_share <= 100),
+  PRIMARY KEY (offer_id, teacher_id)
+);
+
+CREATE UNIQUE INDEX offer_teachers_one_lead ON offer_teachers (offer_id) WHERE role = 'lead';
+
+-- Substituição de um professor da equipe por um período (afastamento, licença...)
+CREATE TABLE offer_substitutions (
+  id SERIAL PRIMARY KEY,
+  offer_id INT NOT NULL REFERENCES discipline_offers(id) ON DELETE CASCADE,
+  absent_teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+  substitute_teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  reason TEXT NOT NULL,
+  created_by VARCHAR(120) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CHECK (end_date >= start_date),
+  CHECK (substitute_teacher_id <> absent_teacher_id)
+);
+
 -- =========================================================
 -- STATUS POSSÍVEL DO ALUNO NA DISCIPLINA
 -- =========================================================
@@ -101,7 +336,8 @@ CREATE TYPE registration_status AS ENUM (
   'in_progress',
   'approved',
   'failed',
-  'take_test'
+  'take_test',
+  'dropped'
 );
 
 -- =========================================================
@@ -121,6 +357,210 @@ CREATE TABLE registrations (
   updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
+-- =========================================================
+-- TRANSFERÊNCIAS DE CURSO
+-- =========================================================
+CREATE TABLE course_transfers (
+  id SERIAL PRIMARY KEY,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  from_course_id INT REFERENCES courses(id) ON DELETE RESTRICT,
+  to_course_id INT NOT NULL REFERENCES courses(id) ON DELETE RESTRICT,
+  reason TEXT NOT NULL,
+  transferred_by VARCHAR(120) NOT NULL,
+  transferred_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CHECK (from_course_id <> to_course_id)
+);
+
+-- Aproveitamento das disciplinas aprovadas no curso de origem:
+-- 'curriculum' conta para a matriz do novo curso, 'free_elective' vira optativa livre
+CREATE TABLE course_transfer_credits (
+  transfer_id INT NOT NULL REFERENCES course_transfers(id) ON DELETE CASCADE,
+  registration_id INT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  classification VARCHAR(20) NOT NULL CHECK (classification IN ('curriculum', 'free_elective')),
+  PRIMARY KEY (transfer_id, registration_id)
+);
+
+-- =========================================================
+-- SITUAÇÃO DE SOLICITAÇÕES COM APROVAÇÃO
+-- =========================================================
+CREATE TYPE request_status AS ENUM (
+  'pending',
+  'approved',
+  'rejected'
+);
+
+-- =========================================================
+-- SOLICITAÇÕES DE TRANCAMENTO
+-- =========================================================
+CREATE TABLE leave_requests (
+  id SERIAL PRIMARY KEY,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  semester_id INT NOT NULL REFERENCES academic_semesters(id) ON DELETE RESTRICT,
+  reason TEXT NOT NULL,
+  status request_status DEFAULT 'pending' NOT NULL,
+  requested_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  reviewed_by VARCHAR(120),
+  reviewed_at TIMESTAMPTZ,
+  review_notes TEXT,
+  -- Início efetivo do trancamento: na aprovação, ou no início do semestre se ele ainda não começou
+  started_at TIMESTAMPTZ,
+  -- Semestre de retorno previsto, definido na aprovação (ou quando o semestre seguinte for criado)
+  return_semester_id INT REFERENCES academic_semesters(id) ON DELETE SET NULL,
+  returned_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX leave_requests_active_key
+ON leave_requests (student_id, semester_id)
+WHERE status <> 'rejected';
+
+-- =========================================================
+-- PEDIDOS DE MATRÍCULA (CURSOS COM APROVAÇÃO DA COORDENAÇÃO)
+-- =========================================================
+CREATE TABLE enrollment_requests (
+  id SERIAL PRIMARY KEY,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  offer_id INT NOT NULL REFERENCES discipline_offers(id) ON DELETE CASCADE,
+  status request_status DEFAULT 'pending' NOT NULL,
+  requested_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  reviewed_by VARCHAR(120),
+  reviewed_at TIMESTAMPTZ,
+  rejection_reason TEXT,
+  -- Oferta alternativa sugerida pela coordenação ao rejeitar
+  suggested_offer_id INT REFERENCES discipline_offers(id) ON DELETE SET NULL,
+  registration_id INT REFERENCES registrations(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX enrollment_requests_active_key
+ON enrollment_requests (student_id, offer_id)
+WHERE status <> 'rejected';
+
+-- =========================================================
+-- CAMPANHAS DE REMATRÍCULA
+-- =========================================================
+CREATE TABLE reenrollment_campaigns (
+  id SERIAL PRIMARY KEY,
+  semester_id INT UNIQUE NOT NULL REFERENCES academic_semesters(id) ON DELETE CASCADE,
+  opens_at DATE NOT NULL,
+  closes_at DATE NOT NULL,
+  created_by VARCHAR(120) NOT NULL,
+  closed_at TIMESTAMPTZ,
+  closed_by VARCHAR(120),
+  CHECK (closes_at >= opens_at)
+);
+
+-- Confirmações de rematrícula dos alunos
+CREATE TABLE reenrollments (
+  campaign_id INT NOT NULL REFERENCES reenrollment_campaigns(id) ON DELETE CASCADE,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  confirmed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  PRIMARY KEY (campaign_id, student_id)
+);
+
+-- =========================================================
+-- EQUIVALÊNCIAS ENTRE DISCIPLINAS
+-- =========================================================
+-- A disciplina alvo é considerada cumprida quando todas as disciplinas de origem foram aprovadas
+CREATE TABLE discipline_equivalences (
+  id SERIAL PRIMARY KEY,
+  target_discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  notes TEXT,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE discipline_equivalence_sources (
+  equivalence_id INT NOT NULL REFERENCES discipline_equivalences(id) ON DELETE CASCADE,
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE CASCADE,
+  PRIMARY KEY (equivalence_id, discipline_id)
+);
+
+-- =========================================================
+-- APROVEITAMENTO DE DISCIPLINAS CURSADAS EM OUTRA INSTITUIÇÃO
+-- =========================================================
+CREATE TABLE external_credits (
+  id SERIAL PRIMARY KEY,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+  -- Disciplina da instituição que está sendo aproveitada
+  discipline_id INT NOT NULL REFERENCES disciplines(id) ON DELETE RESTRICT,
+  institution VARCHAR(120) NOT NULL,
+  external_code VARCHAR(30),
+  external_name VARCHAR(120) NOT NULL,
+  external_workload_hours INT NOT NULL CHECK(external_workload_hours > 0),
+  grade DECIMAL(5,2) CHECK(grade >= 0 AND grade <= 100),
+  completed_year INT NOT NULL,
+  status request_status DEFAULT 'pending' NOT NULL,
+  requested_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  reviewed_by VARCHAR(120),
+  reviewed_at TIMESTAMPTZ,
+  review_notes TEXT
+);
+
+CREATE UNIQUE INDEX external_credits_active_key
+ON external_credits (student_id, discipline_id)
+WHERE status <> 'rejected';
+
+-- =========================================================
+-- PROCESSO SELETIVO (VESTIBULAR / ENEM)
+-- =========================================================
+CREATE TYPE admission_method AS ENUM (
+  'vestibular',
+  'enem'
+);
+
+CREATE TABLE admission_processes (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(120) UNIQUE NOT NULL,
+  method admission_method NOT NULL,
+  -- Semestre de ingresso dos aprovados
+  semester_id INT NOT NULL REFERENCES academic_semesters(id) ON DELETE RESTRICT,
+  created_by VARCHAR(120) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE admission_seats (
+  process_id INT NOT NULL REFERENCES admission_processes(id) ON DELETE CASCADE,
+  course_id INT NOT NULL REFERENCES courses(id) ON DELETE RESTRICT,
+  seats INT NOT NULL CHECK (seats > 0),
+  PRIMARY KEY (process_id, course_id)
+);
+
+-- registered: inscrito; called: convocado em uma chamada; confirmed: matrícula confirmada (vira aluno);
+-- declined: desistiu; forfeited: não confirmou antes da chamada seguinte
+CREATE TYPE applicant_status AS ENUM (
+  'registered',
+  'called',
+  'confirmed',
+  'declined',
+  'forfeited'
+);
+
+CREATE TABLE applicants (
+  id SERIAL PRIMARY KEY,
+  process_id INT NOT NULL,
+  course_id INT NOT NULL,
+  name VARCHAR(120) NOT NULL,
+  cpf CHAR(11) NOT NULL CHECK (cpf ~ '^[0-9]{11}$'),
+  email VARCHAR(120),
+  gender CHAR(1) CHECK (gender IN ('M','F','O')),
+  date_birth DATE NOT NULL CHECK (date_birth <= CURRENT_DATE),
+  -- Notas por área do ENEM (0 a 1000); no vestibular só final_score é usada
+  score_languages DECIMAL(6,2) CHECK (score_languages BETWEEN 0 AND 1000),
+  score_humanities DECIMAL(6,2) CHECK (score_humanities BETWEEN 0 AND 1000),
+  score_sciences DECIMAL(6,2) CHECK (score_sciences BETWEEN 0 AND 1000),
+  score_math DECIMAL(6,2) CHECK (score_math BETWEEN 0 AND 1000),
+  score_essay DECIMAL(6,2) CHECK (score_essay BETWEEN 0 AND 1000),
+  final_score DECIMAL(6,2) CHECK (final_score >= 0),
+  -- Classificação no curso escolhido
+  rank INT,
+  status applicant_status DEFAULT 'registered' NOT NULL,
+  call_number SMALLINT,
+  called_at TIMESTAMPTZ,
+  student_id INT REFERENCES students(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  UNIQUE (process_id, cpf),
+  FOREIGN KEY (process_id, course_id) REFERENCES admission_seats(process_id, course_id) ON DELETE CASCADE
+);
+
 -- =========================================================
 -- LANÇAMENTOS DE NOTAS
 -- =========================================================
@@ -130,20 +570,159 @@ CREATE TABLE grade_items (
   title VARCHAR(100) NOT NULL,
   grade DECIMAL(5,2) NOT NULL CHECK(grade >= 0 AND grade <= 100),
   weight DECIMAL(5,2) NOT NULL CHECK(weight >= 0 AND weight <= 1),
+  -- Professor que lançou a nota (o substituto, durante uma substituição); preenchido por trigger
+  teacher_id INT REFERENCES teachers(id) ON DELETE SET NULL,
   created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
+-- =========================================================
+-- AULAS DAS OFERTAS (DIÁRIO DE CLASSE)
+-- =========================================================
+CREATE TABLE class_sessions (
+  id SERIAL PRIMARY KEY,
+  offer_id INT NOT NULL REFERENCES discipline_offers(id) ON DELETE CASCADE,
+  session_date DATE NOT NULL,
+  start_time TIME NOT NULL,
+  end_time TIME NOT NULL CHECK (end_time > start_time),
+  topic TEXT,
+  notes TEXT,
+  UNIQUE(offer_id, session_date, start_time)
+);
+
 -- =========================================================
 -- REGISTRO DE FALTAS
 -- =========================================================
 CREATE TABLE attendance_records (
   id SERIAL PRIMARY KEY,
   registration_id INT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+  session_id INT REFERENCES class_sessions(id) ON DELETE CASCADE,
   class_date DATE NOT NULL,
   hours_absent INT NOT NULL CHECK(hours_absent >= 0),
-  UNIQUE(registration_id, class_date)
+  -- Professor responsável pela aula na data (o substituto, durante uma substituição); preenchido por trigger
+  teacher_id INT REFERENCES teachers(id) ON DELETE SET NULL,
+  -- Um lançamento por aula; dias com mais de uma aula têm um lançamento para cada
+  UNIQUE(registration_id, session_id)
 );
 
+-- Ofertas sem aulas geradas lançam as faltas por data
+CREATE UNIQUE INDEX attendance_records_by_date ON attendance_records (registration_id, class_date) WHERE session_id IS NULL;
+
+-- =========================================================
+-- DOCUMENTOS OFICIAIS EMITIDOS
+-- =========================================================
+CREATE TYPE document_kind AS ENUM (
+  'enrollment_declaration',
+  'attendance_declaration',
+  'transcript'
+);
+
+-- Registro de cada documento emitido. O código de verificação impresso no documento (e no QR Code)
+-- é consultado publicamente em /verify/{code}; content_hash é o SHA-256 do PDF entregue e content
+-- guarda os dados impressos. signature é a assinatura Ed25519 do PDF (base64), feita com a chave
+-- signature_key_id da instituição.
+CREATE TABLE issued_documents (
+  id SERIAL PRIMARY KEY,
+  code VARCHAR(20) UNIQUE NOT NULL,
+  kind document_kind NOT NULL,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE RESTRICT,
+  semester_id INT REFERENCES academic_semesters(id) ON DELETE RESTRICT,
+  content JSONB NOT NULL,
+  content_hash CHAR(64) NOT NULL,
+  pdf BYTEA NOT NULL,
+  signature_key_id VARCHAR(40) NOT NULL,
+  signature TEXT NOT NULL,
+  issued_by VARCHAR(100) NOT NULL,
+  issued_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- =========================================================
+-- DIPLOMAS DIGITAIS
+-- =========================================================
+-- Livro de registro dos diplomas emitidos. registry_number é o número de registro ("{ano}/{sequência}",
+-- sequência por ano da colação em registration_sequences); code é o código de validação público,
+-- consultado em /verify/diplomas/{code}. xml guarda o diploma assinado entregue e xml_hash seu SHA-256.
+-- Um diploma por aluno e curso.
+CREATE TABLE diplomas (
+  id SERIAL PRIMARY KEY,
+  code VARCHAR(20) UNIQUE NOT NULL,
+  registry_number VARCHAR(20) UNIQUE NOT NULL,
+  student_id INT NOT NULL REFERENCES students(id) ON DELETE RESTRICT,
+  course_id INT NOT NULL REFERENCES courses(id) ON DELETE RESTRICT,
+  graduation_date DATE NOT NULL,
+  xml TEXT NOT NULL,
+  xml_hash CHAR(64) NOT NULL,
+  signature_key_id VARCHAR(40) NOT NULL,
+  issued_by VARCHAR(100) NOT NULL,
+  issued_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  UNIQUE (student_id, course_id)
+);
+
+-- =========================================================
+-- VIEW: DISCIPLINAS CUMPRIDAS PELO ALUNO (CURSADAS OU APROVEITADAS)
+-- =========================================================
+CREATE VIEW student_completed_disciplines AS
+SELECT r.student_id, o.discipline_id, 'taken' AS origin
+FROM registrations r
+JOIN discipline_offers o ON o.id = r.offer_id
+WHERE r.status = 'approved'
+UNION
+SELECT e.student_id, e.discipline_id, 'transferred' AS origin
+FROM external_credits e
+WHERE e.status = 'approved';
+
+-- =========================================================
+-- VIEW: DISCIPLINAS CUMPRIDAS, INCLUINDO AS CUMPRIDAS POR EQUIVALÊNCIA
+-- =========================================================
+CREATE VIEW student_fulfilled_disciplines AS
+SELECT student_id, discipline_id
+FROM student_completed_disciplines
+UNION
+SELECT s.id, e.target_discipline_id
+FROM students s
+JOIN discipline_equivalences e
+  ON EXISTS (SELECT 1 FROM discipline_equivalence_sources es WHERE es.equivalence_id = e.id)
+WHERE NOT EXISTS (
+  SELECT 1 FROM discipline_equivalence_sources es
+  WHERE es.equivalence_id = e.id
+    AND NOT EXISTS (
+      SELECT 1 FROM student_completed_disciplines c
+      WHERE c.student_id = s.id AND c.discipline_id = es.discipline_id
+    )
+);
+
+-- =========================================================
+-- FUNÇÃO: A DISCIPLINA CONTA PARA A MATRIZ DO CURSO?
+-- =========================================================
+-- Conta se faz parte da matriz ou se, junto com as demais disciplinas cumpridas pelo aluno,
+-- completa uma equivalência cuja disciplina alvo faz parte da matriz
+CREATE OR REPLACE FUNCTION counts_for_curriculum(p_student INT, p_discipline INT, p_course INT)
+RETURNS BOOLEAN AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM course_disciplines
+    WHERE course_id = p_course AND discipline_id = p_discipline
+  ) THEN
+    RETURN TRUE;
+  END IF;
+
+  RETURN EXISTS (
+    SELECT 1
+    FROM discipline_equivalence_sources es
+    JOIN discipline_equivalences e ON e.id = es.equivalence_id
+    JOIN course_disciplines cd ON cd.course_id = p_course AND cd.discipline_id = e.target_discipline_id
+    WHERE es.discipline_id = p_discipline
+      AND NOT EXISTS (
+        SELECT 1 FROM discipline_equivalence_sources other
+        WHERE other.equivalence_id = e.id
+          AND NOT EXISTS (
+            SELECT 1 FROM student_completed_disciplines c
+            WHERE c.student_id = p_student AND c.discipline_id = other.discipline_id
+          )
+      )
+  );
+END;
+$$ LANGUAGE plpgsql STABLE;
+
 -- =========================================================
 -- FUNÇÃO: CALCULAR NOTA FINAL
 -- =========================================================
@@ -174,7 +753,7 @@ BEGIN
   FROM attendance_records
   WHERE registration_id = reg_id;
 
-  SELECT d.workload_hours
+  SELECT COALESCE(o.planned_hours, d.workload_hours)
   INTO workload
   FROM registrations r
   JOIN discipline_offers o ON o.id = r.offer_id
@@ -210,35 +789,74 @@ BEGIN
   END IF;
 
   calc_grade := calculate_final_grade(reg_id);
-  SELECT absences, frequency INTO abs, freq
-  FROM calculate_attendance(reg_id);
+  SELECT a.absences, a.frequency INTO abs, freq
+  FROM calculate_attendance(reg_id) a;
+
+  -- O trigger é disparado em grade_items/attendance_records, então o resultado
+  -- precisa ser gravado na matrícula (NEW aqui é o lançamento, não a matrícula).
+  -- A situação (status) só é definida no fechamento do diário; depois dele, e nas
+  -- matrículas trancadas, os valores gravados não mudam mais.
+  UPDATE registrations r
+  SET final_grade = calc_grade,
+      absences = abs,
+      frequency = freq,
+      approved = (freq >= 75 AND calc_grade >= 60)
+  FROM discipline_offers o
+  WHERE r.id = reg_id
+    AND o.id = r.offer_id
+    AND o.closed_at IS NULL
+    AND r.status <> 'dropped';
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
 
-  IF TG_OP = 'DELETE' THEN
-    RETURN OLD;
-  ELSE
-    NEW.final_grade := calc_grade;
-    NEW.absences := abs;
-    NEW.frequency := freq;
-
-    IF freq < 75 THEN
-      NEW.status := 'failed';
-      NEW.approved := FALSE;
-    ELSIF calc_grade >= 60 THEN
-      NEW.status := 'approved';
-      NEW.approved := TRUE;
-    ELSIF calc_grade < 60 AND freq >= 75 THEN
-      NEW.status := 'take_test';
-      NEW.approved := FALSE;
-    ELSE
-      NEW.status := 'failed';
-      NEW.approved := FALSE;
-    END IF;
-
-    RETURN NEW;
-  END IF;
+-- =========================================================
+-- FUNÇÃO: PROFESSOR RESPONSÁVEL PELA OFERTA EM UMA DATA
+-- =========================================================
+-- O responsável é o professor da oferta, ou o substituto se houver substituição do responsável na data
+CREATE OR REPLACE FUNCTION offer_teacher_on(p_offer INT, p_day DATE)
+RETURNS INT AS $$
+  SELECT COALESCE(
+    (SELECT s.substitute_teacher_id
+     FROM offer_substitutions s
+     WHERE s.offer_id = o.id AND s.absent_teacher_id = o.teacher_id
+       AND p_day BETWEEN s.start_date AND s.end_date
+     ORDER BY s.start_date DESC
+     LIMIT 1),
+    o.teacher_id
+  )
+  FROM discipline_offers o
+  WHERE o.id = p_offer
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION attribute_attendance_teacher()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.teacher_id := offer_teacher_on((SELECT offer_id FROM registrations WHERE id = NEW.registration_id), NEW.class_date);
+  RETURN NEW;
 END;
 $$ LANGUAGE plpgsql;
 
+CREATE OR REPLACE FUNCTION attribute_grade_teacher()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.teacher_id := offer_teacher_on((SELECT offer_id FROM registrations WHERE id = NEW.registration_id), CURRENT_DATE);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Só na inserção: corrigir o lançamento depois não muda o professor que o fez
+CREATE TRIGGER trg_attribute_attendance_teacher
+BEFORE INSERT ON attendance_records
+FOR EACH ROW
+EXECUTE PROCEDURE attribute_attendance_teacher();
+
+CREATE TRIGGER trg_attribute_grade_teacher
+BEFORE INSERT ON grade_items
+FOR EACH ROW
+EXECUTE PROCEDURE attribute_grade_teacher();
+
 -- =========================================================
 -- TRIGGERS: ATUALIZAR STATUS AUTOMATICAMENTE
 -- =========================================================
@@ -289,16 +907,25 @@ VALUES
 ('Computação', 'COMP');
 
 -- Cursos
-INSERT INTO courses (name, total_credits_required, duration_semesters)
+INSERT INTO courses (name, code, total_credits_required, duration_semesters, requires_enrollment_approval, min_credits_per_semester, max_credits_per_semester)
 VALUES
-('Sistemas de Informação', 200, 8),
-('Ciência da Computação', 220, 8);
+('Sistemas de Informação', 'SI', 200, 8, FALSE, NULL, NULL),
+('Ciência da Computação', 'CC', 220, 8, TRUE, 4, 32);
 
 -- Professores
-INSERT INTO teachers (name, email, cpf, telephone, department_id)
+INSERT INTO teachers (name, email, cpf, telephone, department_id, contract_hours, contract_regime, academic_title, expertise_areas)
 VALUES
-('Carlos Souza', 'carlos.souza@facul.com', '12345678901', '11999999999', 1),
-('Mariana Lima', 'mariana.lima@facul.com', '98765432100', '21988888888', 2);
+('Carlos Souza', 'carlos.souza@facul.com', '12345678901', '11999999999', 1, 40, 'full_time', 'doctor', '{"Engenharia de Software","Banco de Dados"}'),
+('Mariana Lima', 'mariana.lima@facul.com', '98765432100', '21988888888', 2, 20, 'part_time', 'master', '{"Cálculo","Álgebra Linear"}');
+
+INSERT INTO teacher_contracts (teacher_id, regime, contract_hours, start_date, changed_by)
+SELECT id, contract_regime, contract_hours, date_contract, 'sistema' FROM teachers;
+
+INSERT INTO department_heads (department_id, teacher_id, start_date, assigned_by)
+VALUES (1, 1, '2025-01-01', 'sistema'), (2, 2, '2025-01-01', 'sistema');
+
+INSERT INTO course_coordinators (course_id, teacher_id, start_date, assigned_by)
+VALUES (1, 1, '2025-01-01', 'sistema'), (2, 2, '2025-01-01', 'sistema');
 
 -- Alunos
 INSERT INTO students (name, date_birth, cpf, registration_number, email, gender, course_id)
@@ -307,9 +934,22 @@ VALUES
 ('Ana Santos', '1999-10-02', '55566677788', '2025002', 'ana@aluno.com', 'F', 1),
 ('Pedro Alves', '2001-02-20', '99988877766', '2025003', 'pedro@aluno.com', 'M', 2);
 
+INSERT INTO student_status_history (student_id, from_status, to_status, reason, changed_by, effective_date)
+SELECT id, NULL, 'enrolled', 'Ingresso', 'sistema', '2025-02-17' FROM students;
+
 -- Semestre
-INSERT INTO academic_semesters (year, period)
-VALUES (2025, 1);
+INSERT INTO academic_semesters (year, period, start_date, end_date)
+VALUES (2025, 1, '2025-02-17', '2025-07-05');
+
+-- Calendário
+INSERT INTO calendar_events (semester_id, kind, start_date, end_date, description)
+VALUES
+(1, 'holiday', '2025-03-03', '2025-03-05', 'Carnaval'),
+(1, 'holiday', '2025-04-18', '2025-04-18', 'Sexta-feira Santa'),
+(1, 'holiday', '2025-04-21', '2025-04-21', 'Tiradentes'),
+(1, 'holiday', '2025-05-01', '2025-05-01', 'Dia do Trabalho'),
+(1, 'recess', '2025-06-19', '2025-06-21', 'Recesso de Corpus Christi'),
+(1, 'exam_week', '2025-06-30', '2025-07-05', 'Semana de provas finais');
 
 -- Disciplinas
 INSERT INTO disciplines (name, code, credits, workload_hours, description, department_id)
@@ -318,12 +958,33 @@ VALUES
 ('Programação Go', 'GO202', 3, 60, 'Programação moderna com Go', 2),
 ('Algoritmos', 'ALG303', 4, 80, 'Introdução à lógica', 1);
 
+-- Habilitações: cada professor leciona as disciplinas do seu departamento
+INSERT INTO teacher_disciplines (teacher_id, discipline_id)
+SELECT t.id, d.id FROM teachers t JOIN disciplines d ON d.department_id = t.department_id;
+
+-- Matriz curricular
+INSERT INTO course_disciplines (course_id, discipline_id, suggested_semester, mandatory)
+VALUES
+(1, 1, 2, TRUE),
+(1, 3, 1, TRUE),
+(2, 2, 3, FALSE),
+(2, 3, 1, TRUE);
+
+-- Pré-requisitos
+INSERT INTO discipline_prerequisites (discipline_id, prerequisite_id)
+VALUES
+(1, 3),
+(2, 3);
+
 -- Ofertas
-INSERT INTO discipline_offers (discipline_id, semester_id, teacher_id, schedule)
+INSERT INTO discipline_offers (discipline_id, semester_id, teacher_id, schedule, room, capacity)
 VALUES
-(1, 1, 1, 'Seg/Qua 10h'),
-(2, 1, 2, 'Ter/Qui 14h'),
-(3, 1, 1, 'Seg/Qua 08h');
+(1, 1, 1, 'Seg/Qua 10h', 'Sala 101', 40),
+(2, 1, 2, 'Ter/Qui 14h', 'Lab 02', 25),
+(3, 1, 1, 'Seg/Qua 08h', 'Sala 101', 40);
+
+INSERT INTO offer_teachers (offer_id, teacher_id, role, hour_share)
+SELECT id, teacher_id, 'lead', 100 FROM discipline_offers WHERE teacher_id IS NOT NULL;
 
 -- Matrículas
 INSERT INTO registrations (student_id, offer_id)
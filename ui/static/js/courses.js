@@ -10,7 +10,7 @@ async function loadCourses() {
         tbody.innerHTML = '';
 
         if (!courses || courses.length === 0) {
-            tbody.innerHTML = '<tr><td colspan="4" class="text-center py-4">Nenhum curso encontrado.</td></tr>';
+            tbody.innerHTML = '<tr><td colspan="5" class="text-center py-4">Nenhum curso encontrado.</td></tr>';
             return;
         }
 
@@ -21,6 +21,7 @@ async function loadCourses() {
                 <td class="fw-bold">${c.name}</td>
                 <td>${c.total_credits_required}</td>
                 <td>${c.duration_semesters} semestres</td>
+                <td>${c.coordinator_name || '<span class="text-muted">—</span>'}</td>
             `;
             tbody.appendChild(tr);
         });
@@ -39,6 +40,7 @@ async function initForm() {
         // Mapeia os inputs para o JSON que o Go espera em models/course.go
         const data = {
             name: document.getElementById('name').value,
+            code: document.getElementById('code').value.trim().toUpperCase() || null,
             total_credits_required: parseInt(document.getElementById('credits').value),
             duration_semesters: parseInt(document.getElementById('semesters').value)
         };
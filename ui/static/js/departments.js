@@ -10,7 +10,7 @@ async function loadDepartments() {
         tbody.innerHTML = '';
 
         if (!departments || departments.length === 0) {
-            tbody.innerHTML = '<tr><td colspan="4" class="text-center py-4">Nenhum departamento encontrado.</td></tr>';
+            tbody.innerHTML = '<tr><td colspan="5" class="text-center py-4">Nenhum departamento encontrado.</td></tr>';
             return;
         }
 
@@ -22,6 +22,7 @@ async function loadDepartments() {
                 <td>#${d.id}</td>
                 <td class="fw-bold">${d.name}</td>
                 <td><span class="badge bg-secondary">${d.abbreviation}</span></td>
+                <td>${d.head_name || '<span class="text-muted">—</span>'}</td>
                 <td>${date}</td>
             `;
             tbody.appendChild(tr);
@@ -1,5 +1,13 @@
 const API_URL = '/api/semesters';
 
+const PERIOD_KINDS = {
+    semester: { label: 'Semestre', periods: [['1', '1º Semestre'], ['2', '2º Semestre']] },
+    summer: { label: 'Verão', periods: [['1', 'Verão']] },
+    winter: { label: 'Inverno', periods: [['1', 'Inverno']] },
+    quarter: { label: 'Trimestre', periods: [1, 2, 3, 4].map(n => [String(n), `${n}º Trimestre`]) },
+    module: { label: 'Módulo', periods: Array.from({ length: 12 }, (_, i) => [String(i + 1), `Módulo ${i + 1}`]) }
+};
+
 // --- LISTAGEM ---
 async function loadSemesters() {
     try {
@@ -22,8 +30,8 @@ async function loadSemesters() {
             const tr = document.createElement('tr');
             tr.innerHTML = `
                 <td class="fw-bold">${s.year}</td>
-                <td>${s.period}º</td>
-                <td><span class="badge bg-info text-dark">${s.year}.${s.period}</span></td>
+                <td>${s.period}º ${(PERIOD_KINDS[s.kind] || PERIOD_KINDS.semester).label}</td>
+                <td><span class="badge bg-info text-dark">${s.label}</span></td>
                 <td class="text-end">
                     <button onclick="deleteSemester(${s.id})" class="btn btn-sm btn-danger action-btn" title="Excluir">
                         <i class="bi bi-trash-fill"></i>
@@ -47,12 +55,30 @@ async function initForm() {
     // Define ano atual como padrão
     document.getElementById('year').value = new Date().getFullYear();
 
+    // Os períodos disponíveis dependem do tipo (semestre, verão, inverno, trimestre ou módulo)
+    const kindSelect = document.getElementById('kind');
+    const periodSelect = document.getElementById('period');
+    const fillPeriods = () => {
+        periodSelect.innerHTML = '<option value="" selected disabled>Selecione...</option>';
+        PERIOD_KINDS[kindSelect.value].periods.forEach(([value, text]) => {
+            periodSelect.add(new Option(text, value));
+        });
+        if (periodSelect.options.length === 2) periodSelect.selectedIndex = 1;
+    };
+    kindSelect.addEventListener('change', fillPeriods);
+
     form.addEventListener('submit', async (e) => {
         e.preventDefault();
 
+        const startDate = document.getElementById('start_date').value;
+        const endDate = document.getElementById('end_date').value;
+
         const data = {
             year: parseInt(document.getElementById('year').value),
-            period: parseInt(document.getElementById('period').value)
+            kind: document.getElementById('kind').value,
+            period: parseInt(document.getElementById('period').value),
+            start_date: startDate ? new Date(startDate).toISOString() : null,
+            end_date: endDate ? new Date(endDate).toISOString() : null
         };
 
         try {
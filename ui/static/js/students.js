@@ -2,6 +2,15 @@
 
 const API_URL = '/api/students';
 
+const STATUS_LABELS = {
+    enrolled: '<span class="badge bg-success">Matriculado</span>',
+    leave_of_absence: '<span class="badge bg-warning text-dark">Trancado</span>',
+    transferred: '<span class="badge bg-secondary">Transferido</span>',
+    graduated: '<span class="badge bg-primary">Formado</span>',
+    dropped_out: '<span class="badge bg-secondary">Evadido</span>',
+    expelled: '<span class="badge bg-danger">Desligado</span>'
+};
+
 // --- LISTAGEM (index.html) ---
 async function loadStudents() {
     try {
@@ -47,7 +56,7 @@ async function loadStudents() {
                 <td class="fw-bold">${s.name}</td>
                 <td>${s.email || '-'}</td>
                 <td><span class="badge bg-info text-dark">${s.course_name}</span></td>
-                <td>${s.active ? '<span class="badge bg-success">Ativo</span>' : '<span class="badge bg-secondary">Inativo</span>'}</td>
+                <td>${STATUS_LABELS[s.status] || s.status}</td>
                 <td class="text-end">
                     <a href="students_form.html?id=${s.id}" class="btn btn-sm btn-warning action-btn" title="Editar">
                         <i class="bi bi-pencil-fill"></i>
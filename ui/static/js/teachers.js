@@ -162,6 +162,11 @@ async function loadTeacherData(id) {
         document.getElementById('cpf').value = t.cpf || '';
         document.getElementById('telephone').value = t.telephone || '';
         document.getElementById('department_id').value = t.department_id || '';
+        document.getElementById('contract_hours').value = t.contract_hours || '';
+        document.getElementById('contract_regime').value = t.contract_regime || '';
+        document.getElementById('academic_title').value = t.academic_title || '';
+        document.getElementById('expertise_areas').value = (t.expertise_areas || []).join(', ');
+        document.getElementById('preferred_schedule').value = t.preferred_schedule || '';
 
         // Formata data de contratação
         if (t.date_contract) {
@@ -184,7 +189,12 @@ async function saveTeacher(id) {
         cpf: document.getElementById('cpf').value,
         telephone: document.getElementById('telephone').value,
         department_id: parseInt(document.getElementById('department_id').value),
-        date_contract: new Date(document.getElementById('date_contract').value).toISOString()
+        date_contract: new Date(document.getElementById('date_contract').value).toISOString(),
+        contract_hours: parseInt(document.getElementById('contract_hours').value) || null,
+        contract_regime: document.getElementById('contract_regime').value || null,
+        academic_title: document.getElementById('academic_title').value || null,
+        expertise_areas: document.getElementById('expertise_areas').value.split(',').map(a => a.trim()).filter(a => a),
+        preferred_schedule: document.getElementById('preferred_schedule').value.trim()
     };
 
     const method = id ? 'PUT' : 'POST';